@@ -0,0 +1,126 @@
+package audio
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxTTSInputLength OpenAI の音声合成APIに1回で送る最大文字数（APIの上限は4096文字）
+const maxTTSInputLength = 4000
+
+// sentenceTerminators 文の区切りとみなす文字
+const sentenceTerminators = "。！？!?．\n"
+
+// clauseSeparators 長すぎる文を分割する際に使う区切り文字
+const clauseSeparators = "、，,；;：:"
+
+// splitText テキストを文の境界で maxRunes 文字以下のチャンクに分割する
+func splitText(text string, maxRunes int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentLength := 0
+
+	flush := func() {
+		if chunk := strings.TrimSpace(current.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+		currentLength = 0
+	}
+
+	for _, sentence := range splitSentences(text) {
+		// 1文だけで上限を超える場合はさらに細かく分割
+		pieces := []string{sentence}
+		if utf8.RuneCountInString(sentence) > maxRunes {
+			pieces = splitLongSentence(sentence, maxRunes)
+		}
+
+		for _, piece := range pieces {
+			length := utf8.RuneCountInString(piece)
+			if currentLength+length > maxRunes {
+				flush()
+			}
+			current.WriteString(piece)
+			currentLength += length
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// splitSentences 文末記号（日本語の 。！？ を含む）の直後でテキストを区切る
+// 文末記号に続く閉じ括弧や引用符は同じ文に含める
+func splitSentences(text string) []string {
+	var sentences []string
+	runes := []rune(text)
+	start := 0
+
+	for i := 0; i < len(runes); i++ {
+		if !isSentenceEnd(runes, i) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && (strings.ContainsRune(sentenceTerminators, runes[end]) || isClosingRune(runes[end])) {
+			end++
+		}
+		sentences = append(sentences, string(runes[start:end]))
+		start = end
+		i = end - 1
+	}
+
+	if start < len(runes) {
+		sentences = append(sentences, string(runes[start:]))
+	}
+
+	return sentences
+}
+
+// isSentenceEnd runes[i] が文末か判定
+// 英語などのピリオドは小数点や略語と区別するため、直後が空白の場合だけ文末とみなす
+func isSentenceEnd(runes []rune, i int) bool {
+	if strings.ContainsRune(sentenceTerminators, runes[i]) {
+		return true
+	}
+	return runes[i] == '.' && i+1 < len(runes) && unicode.IsSpace(runes[i+1])
+}
+
+// splitLongSentence 上限を超える文を読点や空白、最後の手段として文字数で分割
+func splitLongSentence(sentence string, maxRunes int) []string {
+	var pieces []string
+	runes := []rune(sentence)
+
+	for len(runes) > maxRunes {
+		cut := -1
+		for i := maxRunes - 1; i > maxRunes/2; i-- {
+			if strings.ContainsRune(clauseSeparators, runes[i]) || unicode.IsSpace(runes[i]) {
+				cut = i + 1
+				break
+			}
+		}
+		if cut < 0 {
+			cut = maxRunes
+		}
+		pieces = append(pieces, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+
+	if len(runes) > 0 {
+		pieces = append(pieces, string(runes))
+	}
+	return pieces
+}
+
+// isClosingRune 文末記号の後に続く閉じ括弧・引用符か判定
+func isClosingRune(r rune) bool {
+	return strings.ContainsRune("」』）)】\"'”’", r)
+}
@@ -0,0 +1,69 @@
+package audio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxRunes int
+		want     []string
+	}{
+		{"empty", "  \n ", 10, nil},
+		{"fits in one chunk", "  短い文。  ", 10, []string{"短い文。"}},
+		// 10文字・30バイトの文も10文字として数える
+		{"counts runes not bytes", "あいうえおかきくけこ", 10, []string{"あいうえおかきくけこ"}},
+		{"sentence boundaries", "一文目。二文目！三文目？", 8, []string{"一文目。二文目！", "三文目？"}},
+		{"closing quote stays with sentence", "「はい。」と言った。次の文です。", 8, []string{"「はい。」", "と言った。", "次の文です。"}},
+		{"repeated terminators", "本当？！そうです。", 5, []string{"本当？！", "そうです。"}},
+		{"english sentences", "Rates rose. Stocks fell. Bonds held.", 14, []string{"Rates rose.", "Stocks fell.", "Bonds held."}},
+		{"decimal point is not a boundary", "Pi is 3.14 today. Yes.", 18, []string{"Pi is 3.14 today.", "Yes."}},
+		{"long sentence split at commas", "あいうえお、かきくけこ、さしすせそ", 8, []string{"あいうえお、", "かきくけこ、", "さしすせそ"}},
+		{"long sentence without separators", "あいうえおかきくけこさしすせそ", 8, []string{"あいうえおかきく", "けこさしすせそ"}},
+	}
+	for _, tt := range tests {
+		got := splitText(tt.text, tt.maxRunes)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: splitText(%q, %d) = %q, want %q", tt.name, tt.text, tt.maxRunes, got, tt.want)
+		}
+	}
+}
+
+func TestSplitTextLimit(t *testing.T) {
+	text := strings.Repeat("日本語の長い要約文です、読点で区切られています。", 300)
+	chunks := splitText(text, maxTTSInputLength)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want several", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if n := utf8.RuneCountInString(chunk); n > maxTTSInputLength {
+			t.Errorf("chunk %d has %d runes, limit %d", i, n, maxTTSInputLength)
+		}
+	}
+	if joined := strings.Join(chunks, ""); joined != text {
+		t.Error("chunks do not join back to the original text")
+	}
+}
+
+func TestSplitLongSentence(t *testing.T) {
+	tests := []struct {
+		sentence string
+		maxRunes int
+		want     []string
+	}{
+		{"one two three four five", 10, []string{"one two ", "three four", " five"}},
+		// 区切りが前半にしかない場合は上限の文字数で切る
+		{"あ、いうえおかきくけこさし", 6, []string{"あ、いうえお", "かきくけこさ", "し"}},
+		{"短い", 6, []string{"短い"}},
+	}
+	for _, tt := range tests {
+		if got := splitLongSentence(tt.sentence, tt.maxRunes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitLongSentence(%q, %d) = %q, want %q", tt.sentence, tt.maxRunes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"news_reporter/config"
+)
+
+// maxCommandInputLength ローカルの音声合成コマンドに1回で渡す最大文字数
+// 短く分けるほど並列に合成でき、コマンドライン引数の長さ制限にもかかりにくい
+const maxCommandInputLength = 1000
+
+// CommandSynthesizer Open JTalk・espeak-ng などのローカルのコマンドを実行して音声を合成する
+// テンプレートの {text} {input} {output} {voice} {speed} は実行のたびに置き換える
+//
+//	{text}   読み上げるテキスト（1つの引数として渡す）
+//	{input}  テキストを書き出した一時ファイル（{text} も {input} もなければ標準入力に渡す）
+//	{output} 音声を書き出させる一時ファイル（なければ標準出力を音声として読む）
+//	{voice}  設定された声
+//	{speed}  設定された話速
+//
+// シェルは経由しないため、検索結果のテキストがコマンドとして解釈されることはない
+type CommandSynthesizer struct {
+	template string
+	format   string
+	voice    string
+	speed    float64
+	timeout  time.Duration
+}
+
+// NewCommandSynthesizer tts.command の設定からローカルの音声合成を作成
+func NewCommandSynthesizer(cfg *config.Config) *CommandSynthesizer {
+	return &CommandSynthesizer{
+		template: cfg.TTSCommand,
+		format:   cfg.CommandFormat,
+		voice:    cfg.Voice,
+		speed:    cfg.Speed,
+		timeout:  cfg.TTSTimeout,
+	}
+}
+
+// Format コマンドが出力する音声形式
+func (s *CommandSynthesizer) Format() string {
+	return s.format
+}
+
+// MaxInputLength 1回のコマンド実行に渡す最大文字数
+func (s *CommandSynthesizer) MaxInputLength() int {
+	return maxCommandInputLength
+}
+
+// Synthesize コマンドを実行してテキストを音声に変換
+func (s *CommandSynthesizer) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	args, err := splitCommand(s.template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TTS command: %w", err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("TTS command is empty")
+	}
+
+	// 入出力の一時ファイルはチャンクごとに別のディレクトリに作る（並列に実行するため）
+	dir, err := os.MkdirTemp("", "news_reporter-tts-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	inputPath := filepath.Join(dir, "input.txt")
+	outputPath := filepath.Join(dir, "output."+s.format)
+
+	usesText, usesInput, usesOutput := false, false, false
+	replacer := strings.NewReplacer(
+		"{text}", text,
+		"{input}", inputPath,
+		"{output}", outputPath,
+		"{voice}", s.voice,
+		"{speed}", strconv.FormatFloat(s.speed, 'f', -1, 64),
+	)
+	for i, arg := range args {
+		usesText = usesText || strings.Contains(arg, "{text}")
+		usesInput = usesInput || strings.Contains(arg, "{input}")
+		usesOutput = usesOutput || strings.Contains(arg, "{output}")
+		args[i] = replacer.Replace(arg)
+	}
+
+	if usesInput {
+		if err := os.WriteFile(inputPath, []byte(text+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write input file: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if !usesText && !usesInput {
+		cmd.Stdin = strings.NewReader(text + "\n")
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s: %w", args[0], ctx.Err())
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%s: %w: %s", args[0], err, message)
+		}
+		return nil, fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	audioData := stdout.Bytes()
+	if usesOutput {
+		if audioData, err = os.ReadFile(outputPath); err != nil {
+			return nil, fmt.Errorf("%s did not write audio: %w", args[0], err)
+		}
+	}
+	if len(audioData) == 0 {
+		return nil, fmt.Errorf("%s produced no audio", args[0])
+	}
+	return audioData, nil
+}
+
+// splitCommand コマンドのテンプレートを引数に分割する
+// 空白で区切り、シングルクォート・ダブルクォートで囲んだ部分は1つの引数として扱う
+func splitCommand(template string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	for _, r := range template {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", template)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
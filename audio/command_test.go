@@ -0,0 +1,126 @@
+package audio
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"news_reporter/config"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{"empty", "  ", nil},
+		{"spaces and tabs", "espeak-ng\t-v ja  --stdout", []string{"espeak-ng", "-v", "ja", "--stdout"}},
+		{"single quotes", `sh -c 'cat > "$0"' {output}`, []string{"sh", "-c", `cat > "$0"`, "{output}"}},
+		{"double quotes", `open_jtalk -m "/usr/share/voice dir/mei.htsvoice" -ow {output}`, []string{"open_jtalk", "-m", "/usr/share/voice dir/mei.htsvoice", "-ow", "{output}"}},
+		{"quotes inside an argument", `--text="{text}"`, []string{"--text={text}"}},
+		{"empty quoted argument", `say ''`, []string{"say", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitCommand(tt.template)
+			if err != nil {
+				t.Fatalf("splitCommand: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCommand(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := splitCommand(`say "unterminated`); err == nil {
+		t.Error("unterminated quote: want an error")
+	}
+}
+
+// newCommandSynthesizer sh -c で動くコマンドのテンプレートからローカルの音声合成を作成
+func newCommandSynthesizer(t *testing.T, template string) *CommandSynthesizer {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	cfg := config.Default()
+	cfg.TTSCommand = template
+	cfg.CommandFormat = "wav"
+	cfg.Voice = "mei"
+	cfg.Speed = 1.25
+	cfg.TTSTimeout = 10 * time.Second
+	return NewCommandSynthesizer(cfg)
+}
+
+func TestCommandSynthesizer(t *testing.T) {
+	// シェルの特殊文字を含むテキストもそのまま1つの引数・入力として渡す
+	const text = `ニュース; echo "$HOME" 'x'`
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"stdin to stdout", `sh -c 'cat; echo done'`, text + "\ndone\n"},
+		{"text argument", `sh -c 'printf "%s" "$0"' {text}`, text},
+		{"input file", `sh -c 'cat "$0"' {input}`, text + "\n"},
+		{"output file", `sh -c 'printf "%s|%s|%s" "$0" "$1" "$2" > "$3"' {text} {voice} {speed} {output}`, text + "|mei|1.25"},
+		{"placeholder inside an argument", `sh -c 'printf "%s" "$0"' --voice={voice}`, "--voice=mei"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newCommandSynthesizer(t, tt.template).Synthesize(context.Background(), text)
+			if err != nil {
+				t.Fatalf("Synthesize: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("audio = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandSynthesizerStdin(t *testing.T) {
+	// {text} を使う場合は標準入力に何も渡さない
+	got, err := newCommandSynthesizer(t, `sh -c 'printf "%s:" "$0"; cat' {text}`).Synthesize(context.Background(), "本文")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "本文:" {
+		t.Errorf("audio = %q, want %q", got, "本文:")
+	}
+}
+
+func TestCommandSynthesizerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"empty command", " ", "TTS command is empty"},
+		{"unterminated quote", `sh -c 'cat`, "invalid TTS command"},
+		{"exit status with stderr", `sh -c 'echo "no voice" >&2; exit 3'`, "exit status 3: no voice"},
+		{"no audio", `sh -c 'cat > /dev/null'`, "produced no audio"},
+		{"output file not written", `sh -c 'cat > /dev/null' {output}`, "did not write audio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newCommandSynthesizer(t, tt.template).Synthesize(context.Background(), "テスト")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Synthesize error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandSynthesizerTimeout(t *testing.T) {
+	s := newCommandSynthesizer(t, `sh -c 'exec sleep 5'`)
+	s.timeout = 50 * time.Millisecond
+	_, err := s.Synthesize(context.Background(), "テスト")
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("Synthesize error = %v, want a deadline error", err)
+	}
+}
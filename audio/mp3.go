@@ -0,0 +1,146 @@
+package audio
+
+import "bytes"
+
+// MPEGオーディオのビットレート表（kbps）[バージョン区分][レイヤー区分][インデックス]
+// バージョン区分: 0 = MPEG-1, 1 = MPEG-2/2.5
+// レイヤー区分:   0 = Layer I, 1 = Layer II, 2 = Layer III
+var mpegBitrates = [2][3][16]int{
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	},
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	},
+}
+
+// MPEGオーディオのサンプリング周波数表（Hz）[バージョン][インデックス]
+// バージョン: 0 = MPEG-2.5, 2 = MPEG-2, 3 = MPEG-1（1 は予約）
+var mpegSampleRates = [4][3]int{
+	{11025, 12000, 8000},
+	{0, 0, 0},
+	{22050, 24000, 16000},
+	{44100, 48000, 32000},
+}
+
+// concatMP3 複数のMP3データを1つの再生可能なストリームに連結する
+// 各チャンクのID3タグとXing/Infoヘッダーフレームを取り除き、音声フレームだけを順番に結合する
+func concatMP3(parts [][]byte) []byte {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	var out bytes.Buffer
+	for _, part := range parts {
+		out.Write(mp3Frames(part))
+	}
+	return out.Bytes()
+}
+
+// mp3Frames MP3データから音声フレーム部分だけを取り出す
+func mp3Frames(data []byte) []byte {
+	data = stripID3v2(data)
+	data = stripID3v1(data)
+
+	offset := findFrameSync(data)
+	if offset < 0 {
+		return data
+	}
+	data = data[offset:]
+
+	// 先頭フレームが長さ情報用の Xing/Info フレームであれば除外（連結後の長さと一致しないため）
+	if size := mp3FrameSize(data); size > 0 && size <= len(data) && isInfoFrame(data[:size]) {
+		data = data[size:]
+	}
+
+	return data
+}
+
+// stripID3v2 先頭のID3v2タグを取り除く
+func stripID3v2(data []byte) []byte {
+	if len(data) < 10 || !bytes.HasPrefix(data, []byte("ID3")) {
+		return data
+	}
+
+	// タグサイズは7ビットずつの syncsafe 整数
+	size := int(data[6]&0x7f)<<21 | int(data[7]&0x7f)<<14 | int(data[8]&0x7f)<<7 | int(data[9]&0x7f)
+	size += 10
+	if data[5]&0x10 != 0 { // フッターあり
+		size += 10
+	}
+	if size > len(data) {
+		return data
+	}
+	return data[size:]
+}
+
+// stripID3v1 末尾のID3v1タグを取り除く
+func stripID3v1(data []byte) []byte {
+	if len(data) >= 128 && bytes.Equal(data[len(data)-128:len(data)-125], []byte("TAG")) {
+		return data[:len(data)-128]
+	}
+	return data
+}
+
+// findFrameSync 最初の有効なフレームヘッダーの位置を返す（見つからない場合は -1）
+func findFrameSync(data []byte) int {
+	for i := 0; i+4 <= len(data); i++ {
+		if data[i] == 0xff && data[i+1]&0xe0 == 0xe0 && mp3FrameSize(data[i:]) > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// mp3FrameSize フレームヘッダーからフレーム長（バイト）を求める（不正なヘッダーは 0）
+func mp3FrameSize(header []byte) int {
+	if len(header) < 4 || header[0] != 0xff || header[1]&0xe0 != 0xe0 {
+		return 0
+	}
+
+	version := int(header[1]>>3) & 0x03
+	layer := int(header[1]>>1) & 0x03
+	bitrateIndex := int(header[2]>>4) & 0x0f
+	sampleRateIndex := int(header[2]>>2) & 0x03
+	padding := int(header[2]>>1) & 0x01
+
+	if version == 1 || layer == 0 || sampleRateIndex == 3 {
+		return 0
+	}
+
+	versionGroup := 1
+	if version == 3 {
+		versionGroup = 0
+	}
+	layerGroup := 3 - layer // ヘッダー値（3=Layer I, 2=Layer II, 1=Layer III）を表の区分に変換
+
+	bitrate := mpegBitrates[versionGroup][layerGroup][bitrateIndex] * 1000
+	sampleRate := mpegSampleRates[version][sampleRateIndex]
+	if bitrate == 0 || sampleRate == 0 {
+		return 0
+	}
+
+	switch {
+	case layerGroup == 0: // Layer I
+		return (12*bitrate/sampleRate + padding) * 4
+	case layerGroup == 2 && versionGroup == 1: // Layer III (MPEG-2/2.5)
+		return 72*bitrate/sampleRate + padding
+	default:
+		return 144*bitrate/sampleRate + padding
+	}
+}
+
+// isInfoFrame Xing/Info/VBRI などのメタデータ用フレームか判定
+func isInfoFrame(frame []byte) bool {
+	// サイド情報の長さに依存せず、フレーム先頭付近のマーカーを探す
+	limit := len(frame)
+	if limit > 64 {
+		limit = 64
+	}
+	head := frame[:limit]
+	return bytes.Contains(head, []byte("Xing")) || bytes.Contains(head, []byte("Info")) || bytes.Contains(head, []byte("VBRI"))
+}
@@ -0,0 +1,99 @@
+package audio
+
+import (
+	"bytes"
+	"testing"
+)
+
+// MPEG-1 Layer III 128kbps 44.1kHz のフレームヘッダー（フレーム長 417 バイト）
+var testFrameHeader = []byte{0xff, 0xfb, 0x90, 0x00}
+
+// testFrame fill で埋めた1フレーム分のデータを作る
+func testFrame(fill byte) []byte {
+	frame := bytes.Repeat([]byte{fill}, 417)
+	copy(frame, testFrameHeader)
+	return frame
+}
+
+// testInfoFrame 長さ情報用の Xing/Info フレームを作る
+func testInfoFrame(marker string) []byte {
+	frame := testFrame(0)
+	copy(frame[36:], marker)
+	return frame
+}
+
+// testID3v2 10バイトの本体を持つID3v2タグ
+func testID3v2() []byte {
+	tag := []byte{'I', 'D', '3', 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a}
+	return append(tag, bytes.Repeat([]byte{'x'}, 10)...)
+}
+
+// testID3v1 末尾に付く128バイトのID3v1タグ
+func testID3v1() []byte {
+	tag := bytes.Repeat([]byte{' '}, 128)
+	copy(tag, "TAG")
+	return tag
+}
+
+// joinBytes バイト列を順に連結する
+func joinBytes(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestMP3FrameSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   int
+	}{
+		{"MPEG-1 Layer III 128kbps 44.1kHz", []byte{0xff, 0xfb, 0x90, 0x00}, 417},
+		{"with padding", []byte{0xff, 0xfb, 0x92, 0x00}, 418},
+		{"MPEG-2 Layer III 64kbps 24kHz", []byte{0xff, 0xf3, 0x84, 0x00}, 192},
+		{"free bitrate", []byte{0xff, 0xfb, 0x00, 0x00}, 0},
+		{"reserved sample rate", []byte{0xff, 0xfb, 0x9c, 0x00}, 0},
+		{"no sync", []byte{0x49, 0x44, 0x33, 0x03}, 0},
+		{"too short", []byte{0xff, 0xfb}, 0},
+	}
+	for _, tt := range tests {
+		if got := mp3FrameSize(tt.header); got != tt.want {
+			t.Errorf("%s: mp3FrameSize = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConcatMP3(t *testing.T) {
+	first := joinBytes(testID3v2(), testInfoFrame("Xing"), testFrame(0x11), testFrame(0x12), testID3v1())
+	second := joinBytes(testID3v2(), testInfoFrame("Info"), testFrame(0x21))
+	third := testFrame(0x31) // タグも Info フレームもないチャンク
+
+	got := concatMP3([][]byte{first, second, third})
+	want := joinBytes(testFrame(0x11), testFrame(0x12), testFrame(0x21), testFrame(0x31))
+	if !bytes.Equal(got, want) {
+		t.Errorf("concatMP3 returned %d bytes, want %d bytes of audio frames only", len(got), len(want))
+	}
+
+	// 1つだけの場合はタグを含めそのまま返す
+	if got := concatMP3([][]byte{first}); !bytes.Equal(got, first) {
+		t.Error("concatMP3 changed a single part")
+	}
+}
+
+func TestMP3Frames(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{"id3v2 and info frame", joinBytes(testID3v2(), testInfoFrame("Info"), testFrame(1)), testFrame(1)},
+		{"id3v2 with footer", joinBytes([]byte{'I', 'D', '3', 0x04, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00}, make([]byte, 10), testFrame(1)), testFrame(1)},
+		{"id3v1", joinBytes(testFrame(1), testID3v1()), testFrame(1)},
+		{"garbage before sync", joinBytes([]byte{0x00, 0x01, 0x02}, testFrame(1)), testFrame(1)},
+		{"audio frame is kept", joinBytes(testFrame(1), testFrame(2)), joinBytes(testFrame(1), testFrame(2))},
+		{"truncated id3v2 is kept", []byte{'I', 'D', '3', 0x03, 0x00, 0x00, 0x00, 0x00, 0x7f, 0x7f}, []byte{'I', 'D', '3', 0x03, 0x00, 0x00, 0x00, 0x00, 0x7f, 0x7f}},
+	}
+	for _, tt := range tests {
+		if got := mp3Frames(tt.data); !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: mp3Frames returned %d bytes, want %d", tt.name, len(got), len(tt.want))
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package audio
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NormalizeStep 読み上げ用テキストに対する1つの変換処理
+type NormalizeStep func(text string) string
+
+// SpeechNormalizer 要約を読み上げに適したテキストに変換するパイプライン
+type SpeechNormalizer struct {
+	steps []NormalizeStep
+}
+
+// NewSpeechNormalizer 指定した順に変換処理を適用するパイプラインを作成
+func NewSpeechNormalizer(steps ...NormalizeStep) *SpeechNormalizer {
+	return &SpeechNormalizer{steps: steps}
+}
+
+// DefaultSpeechNormalizer 標準の変換処理をすべて含むパイプラインを作成
+func DefaultSpeechNormalizer() *SpeechNormalizer {
+	return NewSpeechNormalizer(
+		StripMarkdown,
+		RemoveCitationMarks,
+		ReplaceURLs,
+		ExpandDates,
+		ExpandNumbers,
+		ExpandAbbreviations,
+		CollapseWhitespace,
+	)
+}
+
+// SpeechNormalizerFor 言語に合わせたパイプラインを作成
+// 日付・数値・略語の読み替えは日本語向けのため、他の言語では記法とURLの整理だけを行う
+func SpeechNormalizerFor(lang string) *SpeechNormalizer {
+	if lang == "" || lang == "ja" {
+		return DefaultSpeechNormalizer()
+	}
+	return NewSpeechNormalizer(
+		StripMarkdown,
+		RemoveCitationMarks,
+		ReplaceURLsWithDomains,
+		CollapseWhitespace,
+	)
+}
+
+// Normalize テキストに変換処理を順に適用
+func (n *SpeechNormalizer) Normalize(text string) string {
+	for _, step := range n.steps {
+		text = step(text)
+	}
+	return text
+}
+
+// NormalizeForSpeech 標準のパイプラインでテキストを読み上げ用に変換
+func NormalizeForSpeech(text string) string {
+	return DefaultSpeechNormalizer().Normalize(text)
+}
+
+var (
+	markdownImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	markdownHeader     = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s*(.*?)\s*#*\s*$`)
+	markdownBullet     = regexp.MustCompile(`(?m)^\s*(?:[-*+•・●○■□◆◇▪▶]|\d+[.)])\s+`)
+	markdownQuote      = regexp.MustCompile(`(?m)^\s*>+\s?`)
+	markdownRule       = regexp.MustCompile(`(?m)^\s*(?:[-*_]\s*){3,}$`)
+	markdownTableRule  = regexp.MustCompile(`(?m)^\s*\|?\s*:?-{3,}:?\s*(?:\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	markdownEmphasis   = regexp.MustCompile(`(\*\*|\*|~~)([^\s*_~](?:.*?[^\s*_~])?)(\*\*|\*|~~)`)
+	underscoreEmphasis = regexp.MustCompile(`(^|[^\p{L}\p{N}_])(__|_)([^\s_](?:.*?[^\s_])?)(__|_)($|[^\p{L}\p{N}_])`) // snake_case は強調としない
+	markdownInlineCode = regexp.MustCompile("`+([^`]*)`+")
+	lineEndPunctuation = regexp.MustCompile(`[。！？!?.、,:：」』）)]$`)
+)
+
+// StripMarkdown 見出し・強調・箇条書き・リンクなどのMarkdown記法を取り除く
+// 見出しや箇条書きの行末には句点を補い、読み上げ時に区切りが入るようにする
+func StripMarkdown(text string) string {
+	text = markdownImage.ReplaceAllString(text, "$1")
+	text = markdownLink.ReplaceAllStringFunc(text, func(link string) string {
+		match := markdownLink.FindStringSubmatch(link)
+		label, target := match[1], match[2]
+		// リンクテキストがURLそのものであれば後段のURL置換に任せる
+		if strings.HasPrefix(label, "http://") || strings.HasPrefix(label, "https://") {
+			return target
+		}
+		return label
+	})
+	text = markdownInlineCode.ReplaceAllString(text, "$1")
+	text = markdownRule.ReplaceAllString(text, "")
+	text = markdownTableRule.ReplaceAllString(text, "")
+	text = markdownQuote.ReplaceAllString(text, "")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		structural := markdownHeader.MatchString(line) || markdownBullet.MatchString(line)
+		line = markdownHeader.ReplaceAllString(line, "$1")
+		line = markdownBullet.ReplaceAllString(line, "")
+
+		// 表の区切り記号は読点に置き換える
+		if strings.HasPrefix(strings.TrimSpace(line), "|") {
+			cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+			for j := range cells {
+				cells[j] = strings.TrimSpace(cells[j])
+			}
+			line = strings.Join(cells, "、")
+			structural = true
+		}
+
+		line = strings.TrimSpace(line)
+		if structural && line != "" && !lineEndPunctuation.MatchString(line) {
+			line += "。"
+		}
+		lines[i] = line
+	}
+	text = strings.Join(lines, "\n")
+
+	// 入れ子の強調に対応するため変化がなくなるまで繰り返す
+	for {
+		stripped := markdownEmphasis.ReplaceAllString(text, "$2")
+		stripped = underscoreEmphasis.ReplaceAllString(stripped, "$1$3$5")
+		if stripped == text {
+			break
+		}
+		text = stripped
+	}
+
+	return text
+}
+
+var (
+	numericCitation = regexp.MustCompile(`\s*(?:\[\d+(?:[,\s]*\d+)*\]|【\d+(?:†[^】]*)?】|\^\d+)`)
+	emptyBrackets   = regexp.MustCompile(`[（(]\s*[)）]`)
+)
+
+// RemoveCitationMarks [1] や【1†source】のような引用番号を取り除く
+func RemoveCitationMarks(text string) string {
+	text = numericCitation.ReplaceAllString(text, "")
+	return emptyBrackets.ReplaceAllString(text, "")
+}
+
+var (
+	rawURL          = regexp.MustCompile(`https?://[^\s)）\]」』<>"]+`)
+	bracketedSource = regexp.MustCompile(`[（(]\s*(出典[:：]\s*)?([a-z0-9.-]+\.[a-z]{2,})\s*[)）]`)
+)
+
+// ReplaceURLs URLをドメイン名に置き換える。括弧内の出典URLは「出典、ドメイン名」として読む
+func ReplaceURLs(text string) string {
+	text = replaceURLs(text, "出典")
+	return bracketedSource.ReplaceAllString(text, "（出典、$2）")
+}
+
+// ReplaceURLsWithDomains URLをドメイン名に置き換える（言語に依存する語は補わない）
+func ReplaceURLsWithDomains(text string) string {
+	return replaceURLs(text, "")
+}
+
+// replaceURLs URLをドメイン名に置き換え、解釈できないURLは fallback にする
+func replaceURLs(text, fallback string) string {
+	return rawURL.ReplaceAllStringFunc(text, func(raw string) string {
+		parsed, err := url.Parse(strings.TrimRight(raw, ".,。、"))
+		if err != nil || parsed.Hostname() == "" {
+			return fallback
+		}
+		return strings.TrimPrefix(parsed.Hostname(), "www.")
+	})
+}
+
+var (
+	isoDate      = regexp.MustCompile(`\b(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})\b`)
+	clockTime    = regexp.MustCompile(`\b([01]?\d|2[0-3]):([0-5]\d)\b`)
+	yearMonth    = regexp.MustCompile(`\b(\d{4})[-/](\d{1,2})\b`)
+	thousandsSep = regexp.MustCompile(`\d{1,3}(?:,\d{3})+`)
+	largeNumber  = regexp.MustCompile(`(\d[.,])?(\d{5,})`) // 小数点・区切りの後の数字は1つ目のグループで見分ける
+	currency     = regexp.MustCompile(`(US\$|\$|¥|￥|€|£)\s?(\d+(?:\.\d+)?)([万億兆]?)`)
+	percentage   = regexp.MustCompile(`(\d+(?:\.\d+)?)\s?[%％]`)
+	numberRange  = regexp.MustCompile(`(\d)\s?[~〜～]\s?(\d)`)
+)
+
+// ExpandDates 2024-01-15 や 15:30 のような表記を日本語の読みやすい表現に変換（15:00 は「15時」）
+func ExpandDates(text string) string {
+	text = isoDate.ReplaceAllStringFunc(text, func(match string) string {
+		parts := isoDate.FindStringSubmatch(match)
+		return parts[1] + "年" + trimLeadingZeros(parts[2]) + "月" + trimLeadingZeros(parts[3]) + "日"
+	})
+	text = yearMonth.ReplaceAllStringFunc(text, func(match string) string {
+		parts := yearMonth.FindStringSubmatch(match)
+		month, _ := strconv.Atoi(parts[2])
+		if month < 1 || month > 12 {
+			return match
+		}
+		return parts[1] + "年" + strconv.Itoa(month) + "月"
+	})
+	return clockTime.ReplaceAllStringFunc(text, func(match string) string {
+		parts := clockTime.FindStringSubmatch(match)
+		minutes := trimLeadingZeros(parts[2])
+		if minutes == "0" {
+			return trimLeadingZeros(parts[1]) + "時" // ちょうどの時刻は「3時」と読む
+		}
+		return trimLeadingZeros(parts[1]) + "時" + minutes + "分"
+	})
+}
+
+// ExpandNumbers 桁区切りや通貨・百分率の記号を読み上げやすい表現に変換
+// 5桁以上の数値は「万」「億」「兆」の単位を使った表記にする
+func ExpandNumbers(text string) string {
+	text = thousandsSep.ReplaceAllStringFunc(text, func(match string) string {
+		return strings.ReplaceAll(match, ",", "")
+	})
+	text = currency.ReplaceAllStringFunc(text, func(match string) string {
+		parts := currency.FindStringSubmatch(match)
+		unit := map[string]string{"US$": "米ドル", "$": "ドル", "¥": "円", "￥": "円", "€": "ユーロ", "£": "ポンド"}[parts[1]]
+		return parts[2] + parts[3] + unit
+	})
+	text = percentage.ReplaceAllString(text, "${1}パーセント")
+	text = numberRange.ReplaceAllString(text, "${1}から${2}")
+	return largeNumber.ReplaceAllStringFunc(text, func(match string) string {
+		// 3.14159 の小数部などは桁の単位を付けずにそのまま読む
+		if parts := largeNumber.FindStringSubmatch(match); parts[1] == "" {
+			return japaneseNumber(parts[2])
+		}
+		return match
+	})
+}
+
+// japaneseNumber 数字列を万・億・兆の単位で区切った表記に変換（例: 123456789 → 1億2345万6789）
+func japaneseNumber(digits string) string {
+	trimmed := strings.TrimLeft(digits, "0")
+	if trimmed == "" || len(trimmed) > 16 {
+		return digits
+	}
+
+	units := []string{"", "万", "億", "兆"}
+	var groups []string
+	for i := 0; len(trimmed) > 0; i++ {
+		start := len(trimmed) - 4
+		if start < 0 {
+			start = 0
+		}
+		group := strings.TrimLeft(trimmed[start:], "0")
+		if group != "" {
+			groups = append([]string{group + units[i]}, groups...)
+		}
+		trimmed = trimmed[:start]
+	}
+	return strings.Join(groups, "")
+}
+
+// trimLeadingZeros 先頭の0を取り除く（"0" はそのまま）
+func trimLeadingZeros(value string) string {
+	trimmed := strings.TrimLeft(value, "0")
+	if trimmed == "" {
+		return "0"
+	}
+	return trimmed
+}
+
+// speechAbbreviations 読み上げ時に誤読されやすい略語と記号
+var speechAbbreviations = []struct {
+	pattern *regexp.Regexp
+	reading string
+}{
+	{regexp.MustCompile(`\bAI\b`), "エーアイ"},
+	{regexp.MustCompile(`\bGDP\b`), "ジーディーピー"},
+	{regexp.MustCompile(`\bCPI\b`), "消費者物価指数"},
+	{regexp.MustCompile(`\bFRB\b`), "エフアールビー"},
+	{regexp.MustCompile(`\bFed\b`), "米連邦準備制度"},
+	{regexp.MustCompile(`\bECB\b`), "欧州中央銀行"},
+	{regexp.MustCompile(`\bIMF\b`), "国際通貨基金"},
+	{regexp.MustCompile(`\bEU\b`), "イーユー"},
+	{regexp.MustCompile(`\bIT\b`), "アイティー"},
+	{regexp.MustCompile(`\bCEO\b`), "最高経営責任者"},
+	{regexp.MustCompile(`\bIPO\b`), "新規株式公開"},
+	{regexp.MustCompile(`\bM&A\b`), "エムアンドエー"},
+	{regexp.MustCompile(`\bAPI\b`), "エーピーアイ"},
+	{regexp.MustCompile(`\bLLM\b`), "大規模言語モデル"},
+	{regexp.MustCompile(`\bvs\.?\b`), "対"},
+	{regexp.MustCompile(`\betc\.?`), "など"},
+	{regexp.MustCompile(`\be\.g\.`), "例えば"},
+	{regexp.MustCompile(`&`), "アンド"},
+	{regexp.MustCompile(`→`), "から"},
+}
+
+// ExpandAbbreviations よく使われる略語や記号を読みに置き換える
+func ExpandAbbreviations(text string) string {
+	for _, abbreviation := range speechAbbreviations {
+		text = abbreviation.pattern.ReplaceAllString(text, abbreviation.reading)
+	}
+	return text
+}
+
+var (
+	repeatedSpaces   = regexp.MustCompile(`[ \t\x{3000}]+`)
+	repeatedNewlines = regexp.MustCompile(`\n{2,}`)
+)
+
+// CollapseWhitespace 連続する空白と空行をまとめる
+func CollapseWhitespace(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(repeatedSpaces.ReplaceAllString(line, " "))
+	}
+	text = strings.Join(lines, "\n")
+	text = repeatedNewlines.ReplaceAllString(text, "\n")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,64 @@
+package audio
+
+import "testing"
+
+func TestNormalizeSteps(t *testing.T) {
+	tests := []struct {
+		name string
+		step NormalizeStep
+		in   string
+		want string
+	}{
+		{"bold", StripMarkdown, "**強調**と*斜体*と~~取消~~", "強調と斜体と取消"},
+		{"underscore emphasis", StripMarkdown, "これは _強調_ です", "これは 強調 です"},
+		{"double underscore", StripMarkdown, "「__重要__」", "「重要」"},
+		{"snake case", StripMarkdown, "snake_case_name を使う", "snake_case_name を使う"},
+		{"snake case in emphasis", StripMarkdown, "**snake_case_name**", "snake_case_name"},
+		{"header", StripMarkdown, "## 今日のニュース", "今日のニュース。"},
+		{"bullet", StripMarkdown, "- 株価が上昇", "株価が上昇。"},
+		{"link", StripMarkdown, "[記事](https://example.com/a)", "記事"},
+
+		{"citation", RemoveCitationMarks, "発表した[1]。【2†source】", "発表した。"},
+		{"url", ReplaceURLs, "詳細は https://www.example.com/news を参照", "詳細は example.com を参照"},
+
+		{"date", ExpandDates, "2024-01-05に", "2024年1月5日に"},
+		{"year month", ExpandDates, "2024/03の統計", "2024年3月の統計"},
+		{"time", ExpandDates, "15:30から", "15時30分から"},
+		{"time on the hour", ExpandDates, "3:00に", "3時に"},
+		{"minutes after midnight", ExpandDates, "0:05に", "0時5分に"},
+		{"time with leading zero", ExpandDates, "09:05に", "9時5分に"},
+
+		{"thousands", ExpandNumbers, "1,234,567円", "123万4567円"},
+		{"large number", ExpandNumbers, "123456789人", "1億2345万6789人"},
+		{"small number", ExpandNumbers, "1234人", "1234人"},
+		{"decimal", ExpandNumbers, "円周率は3.14159です", "円周率は3.14159です"},
+		{"decimal with large integer part", ExpandNumbers, "12345.6789", "1万2345.6789"},
+		{"currency", ExpandNumbers, "$100と€5", "100ドルと5ユーロ"},
+		{"percentage", ExpandNumbers, "3.5%上昇", "3.5パーセント上昇"},
+		{"range", ExpandNumbers, "10〜20件", "10から20件"},
+
+		{"abbreviation", ExpandAbbreviations, "AIとGDP", "エーアイとジーディーピー"},
+		{"whitespace", CollapseWhitespace, "  a   b \n\n\n c  ", "a b\nc"},
+	}
+	for _, tt := range tests {
+		if got := tt.step(tt.in); got != tt.want {
+			t.Errorf("%s: %q → %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeForSpeech(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"## 速報\n- 日経平均が**35,000円**を回復[1]", "速報。\n日経平均が3万5000円を回復。"},
+		{"会見は3:00に始まり、円周率3.14159が話題に", "会見は3時に始まり、円周率3.14159が話題に"},
+		{"`snake_case_name` の変数", "snake_case_name の変数"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeForSpeech(tt.in); got != tt.want {
+			t.Errorf("NormalizeForSpeech(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
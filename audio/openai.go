@@ -0,0 +1,100 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"news_reporter/config"
+	"news_reporter/transport"
+)
+
+// OpenAISynthesizer OpenAI の /audio/speech で音声を合成する
+type OpenAISynthesizer struct {
+	config     *config.Config
+	httpClient *http.Client
+}
+
+type TTSRequest struct {
+	Model  string  `json:"model"`
+	Input  string  `json:"input"`
+	Voice  string  `json:"voice"`
+	Format string  `json:"response_format"`
+	Speed  float64 `json:"speed,omitempty"`
+}
+
+// NewOpenAISynthesizer 新しい OpenAI の音声合成クライアントを作成
+func NewOpenAISynthesizer(cfg *config.Config) *OpenAISynthesizer {
+	return &OpenAISynthesizer{
+		config: cfg,
+		// 並列の合成リクエストでもレート制限の待ち時間を共有する
+		httpClient: transport.NewHTTPClient(cfg, cfg.TTSTimeout),
+	}
+}
+
+// Format 設定された出力形式（response_format）
+func (s *OpenAISynthesizer) Format() string {
+	return s.config.ResponseFormat
+}
+
+// MaxInputLength APIの入力上限に余裕を持たせた文字数
+func (s *OpenAISynthesizer) MaxInputLength() int {
+	return maxTTSInputLength
+}
+
+// Synthesize OpenAI TTS APIを使用してテキストを音声に変換
+func (s *OpenAISynthesizer) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	// リクエストボディを構築
+	request := TTSRequest{
+		Model:  s.config.TTSModel,
+		Input:  text,
+		Voice:  s.config.Voice,
+		Format: s.config.ResponseFormat,
+		Speed:  s.config.Speed,
+	}
+
+	// JSONエンコード
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// HTTPリクエストを作成
+	req, err := http.NewRequestWithContext(ctx, "POST", s.config.BaseURL+"/audio/speech", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// ヘッダーを設定
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.config.OpenAIAPIKey)
+
+	// リクエストを送信
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		// 再送しても成功しなかった場合は型付きのエラーをそのまま返す（errors.As で APIError を取り出せる）
+		var retryErr *transport.RetryError
+		if errors.As(err, &retryErr) {
+			return nil, retryErr
+		}
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// ステータスコードをチェック
+	if resp.StatusCode != http.StatusOK {
+		return nil, transport.NewAPIError(resp)
+	}
+
+	// 音声データを読み取り
+	audioData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio data: %w", err)
+	}
+
+	return audioData, nil
+}
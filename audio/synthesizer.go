@@ -0,0 +1,30 @@
+package audio
+
+import (
+	"context"
+
+	"news_reporter/config"
+)
+
+// Synthesizer テキストを音声データに変換する音声合成エンジン
+// 長いテキストの分割・連結・再生・保存は TTSClient が行い、Synthesizer は1チャンク分の合成だけを担う
+type Synthesizer interface {
+	// Synthesize MaxInputLength 以下のテキストを音声データに変換
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+	// Format 返す音声データの形式（mp3, wav など）
+	Format() string
+	// MaxInputLength 1回の合成に渡せる最大文字数
+	MaxInputLength() int
+}
+
+// NewSynthesizer 設定された音声合成バックエンドの Synthesizer を作成
+func NewSynthesizer(cfg *config.Config) Synthesizer {
+	switch cfg.TTSBackend {
+	case config.TTSBackendCommand:
+		return NewCommandSynthesizer(cfg)
+	case config.TTSBackendVoicevox:
+		return NewVoicevoxSynthesizer(cfg)
+	default:
+		return NewOpenAISynthesizer(cfg)
+	}
+}
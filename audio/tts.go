@@ -2,154 +2,262 @@ package audio
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/hajimehoshi/go-mp3"
 	"github.com/hajimehoshi/oto/v2"
 
+	"news_reporter/cache"
 	"news_reporter/config"
+	"news_reporter/i18n"
 )
 
-type TTSClient struct {
-	config     *config.Config
-	httpClient *http.Client
-}
+// maxParallelSynthesis 長文を分割した際に同時に合成するチャンクの数
+const maxParallelSynthesis = 4
 
-type TTSRequest struct {
-	Model  string `json:"model"`
-	Input  string `json:"input"`
-	Voice  string `json:"voice"`
-	Format string `json:"response_format"`
+// TTSClient 設定された Synthesizer で音声を合成し、長文の分割・連結・再生・保存を行う
+// 同じ文章の音声は内容のハッシュをキーにキャッシュし、ブリーフィングの再生成などでは合成し直さない
+type TTSClient struct {
+	synthesizer Synthesizer
+	cache       *cache.Store // nil の場合はキャッシュしない
+	refresh     bool         // キャッシュを読まずに合成し直す（--refresh）
+	voiceKey    string       // 音声が変わる設定（バックエンド・モデル・声・速度・形式）をまとめたキーの一部
+	status      io.Writer    // 進捗メッセージの出力先（標準出力を結果専用にできるよう既定は標準エラー出力）
 }
 
-// NewTTSClient 新しいTTSクライアントを作成
+// NewTTSClient 設定された音声合成バックエンドを使う新しいTTSクライアントを作成
 func NewTTSClient(cfg *config.Config) *TTSClient {
-	return &TTSClient{
-		config: cfg,
-		httpClient: &http.Client{
-			Timeout: 120 * time.Second, // 音声生成は時間がかかる場合があるため長めに設定
-		},
+	t := &TTSClient{synthesizer: NewSynthesizer(cfg), status: os.Stderr}
+	if cfg.SpeechCacheEnabled() {
+		t.cache = cache.NewStore(filepath.Join(cfg.CacheDir, "speech"), cfg.SpeechCacheTTL)
+		t.refresh = cfg.CacheMode == config.CacheModeRefresh
+		t.voiceKey = cache.Key(cfg.TTSBackend, cfg.TTSModel, cfg.Voice,
+			strconv.FormatFloat(cfg.Speed, 'g', -1, 64), cfg.AudioFormat(), cfg.TTSCommand, cfg.VoicevoxURL)
 	}
+	return t
+}
+
+// SetStatus 進捗メッセージの出力先を設定
+func (t *TTSClient) SetStatus(w io.Writer) {
+	t.status = w
+}
+
+// Format 合成される音声データの形式（mp3, wav など）
+func (t *TTSClient) Format() string {
+	return t.synthesizer.Format()
 }
 
 // SynthesizeAndPlay テキストを音声に変換して再生
-func (t *TTSClient) SynthesizeAndPlay(text string) error {
-	fmt.Println("🎵 音声を生成中...")
+func (t *TTSClient) SynthesizeAndPlay(ctx context.Context, text string) error {
+	fmt.Fprintln(t.status, i18n.T("audio.generating"))
 
 	// 音声データを生成
-	audioData, err := t.synthesize(text)
+	audioData, err := t.synthesize(ctx, text)
 	if err != nil {
 		return fmt.Errorf("音声生成に失敗しました: %w", err)
 	}
 
-	fmt.Println("🔊 音声を再生中...")
+	fmt.Fprintln(t.status, i18n.T("audio.playing"))
 
 	// 音声を再生
-	if err := t.playAudio(audioData); err != nil {
+	return t.Play(ctx, audioData)
+}
+
+// Play 生成済みのMP3・WAV音声データを再生（ctx がキャンセルされると再生を止めて ctx のエラーを返す）
+func (t *TTSClient) Play(ctx context.Context, audioData []byte) error {
+	if format := t.Format(); format != "mp3" && format != "wav" {
+		return fmt.Errorf("音声の再生は mp3 または wav 形式のみ対応しています (現在: %s)", format)
+	}
+	if err := t.playAudio(ctx, audioData); err != nil {
 		return fmt.Errorf("音声再生に失敗しました: %w", err)
 	}
-
 	return nil
 }
 
-// synthesize OpenAI TTS APIを使用してテキストを音声に変換
-func (t *TTSClient) synthesize(text string) ([]byte, error) {
-	// リクエストボディを構築
-	request := TTSRequest{
-		Model:  "tts-1", // 高速モデル（tts-1-hdもあります）
-		Input:  text,
-		Voice:  "alloy", // 利用可能な声: alloy, echo, fable, onyx, nova, shimmer
-		Format: "mp3",
+// Synthesize テキストを音声に変換して設定された形式の音声データを返す
+func (t *TTSClient) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	return t.synthesize(ctx, text)
+}
+
+// Cached テキストの音声がキャッシュされているか（合成しても費用がかからないか）
+func (t *TTSClient) Cached(text string) bool {
+	_, ok := t.cached(text)
+	return ok
+}
+
+// cached キャッシュされた音声データを返す
+func (t *TTSClient) cached(text string) ([]byte, bool) {
+	if t.cache == nil || t.refresh {
+		return nil, false
 	}
+	data, _, ok := t.cache.Get(cache.Key(t.voiceKey, text))
+	return data, ok
+}
 
-	// JSONエンコード
-	requestBody, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+// synthesize テキストを音声に変換（キャッシュがあれば合成しない）
+func (t *TTSClient) synthesize(ctx context.Context, text string) ([]byte, error) {
+	if data, ok := t.cached(text); ok {
+		return data, nil
+	}
+	data, err := t.synthesizeChunks(ctx, text)
+	if err != nil || t.cache == nil {
+		return data, err
 	}
+	if err := t.cache.Put(cache.Key(t.voiceKey, text), data); err != nil {
+		fmt.Fprintln(t.status, i18n.T("cache.speech_failed", err))
+	}
+	return data, nil
+}
 
-	// HTTPリクエストを作成
-	req, err := http.NewRequest("POST", t.config.BaseURL+"/audio/speech", bytes.NewBuffer(requestBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// synthesizeChunks テキストを音声に変換
+// 音声合成エンジンの入力上限を超える長いテキストは文単位のチャンクに分けて並列に合成し、順番どおりに連結する
+func (t *TTSClient) synthesizeChunks(ctx context.Context, text string) ([]byte, error) {
+	chunks := splitText(text, t.synthesizer.MaxInputLength())
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("text to synthesize is empty")
+	}
+	if len(chunks) == 1 {
+		return t.synthesizer.Synthesize(ctx, chunks[0])
 	}
 
-	// ヘッダーを設定
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+t.config.OpenAIAPIKey)
+	fmt.Fprintln(t.status, i18n.T("audio.chunked", len(chunks)))
 
-	// リクエストを送信
-	resp, err := t.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+	parts := make([][]byte, len(chunks))
+	errs := make([]error, len(chunks))
+	semaphore := make(chan struct{}, maxParallelSynthesis)
+	var wg sync.WaitGroup
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			parts[i], errs[i] = t.synthesizer.Synthesize(ctx, chunk)
+		}(i, chunk)
 	}
-	defer resp.Body.Close()
+	wg.Wait()
 
-	// ステータスコードをチェック
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	// 中断された場合はチャンクごとのエラーではなく中断として返す
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+		}
 	}
 
-	// 音声データを読み取り
-	audioData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read audio data: %w", err)
+	switch format := t.Format(); format {
+	case "mp3":
+		return concatMP3(parts), nil
+	case "wav":
+		return concatWAV(parts)
+	case "pcm":
+		// ヘッダーのない生データはそのまま連結できる
+		return bytes.Join(parts, nil), nil
+	default:
+		return nil, fmt.Errorf("長いテキストの分割合成は mp3・wav・pcm 形式のみ対応しています (現在: %s)", format)
 	}
+}
 
-	return audioData, nil
+// ContentType 合成される音声形式のMIMEタイプを返す
+func (t *TTSClient) ContentType() string {
+	switch t.Format() {
+	case "opus":
+		return "audio/ogg"
+	case "aac":
+		return "audio/aac"
+	case "flac":
+		return "audio/flac"
+	case "wav":
+		return "audio/wav"
+	case "pcm":
+		return "audio/L16"
+	default:
+		return "audio/mpeg"
+	}
 }
 
-// playAudio MP3音声データを再生
-func (t *TTSClient) playAudio(audioData []byte) error {
-	// MP3デコーダーを作成
-	decoder, err := mp3.NewDecoder(bytes.NewReader(audioData))
-	if err != nil {
-		return fmt.Errorf("failed to create MP3 decoder: %w", err)
+// playAudio MP3・WAV音声データを再生
+func (t *TTSClient) playAudio(ctx context.Context, audioData []byte) error {
+	var source io.Reader
+	var sampleRate, channels int
+	if t.Format() == "wav" {
+		wav, err := parseWAV(audioData)
+		if err != nil {
+			return err
+		}
+		if wav.audioFormat() != 1 || wav.bitsPerSample() != 16 {
+			return fmt.Errorf("unsupported WAV encoding (format %d, %d bits): only 16-bit PCM can be played", wav.audioFormat(), wav.bitsPerSample())
+		}
+		source, sampleRate, channels = bytes.NewReader(wav.data), wav.sampleRate(), wav.channels()
+	} else {
+		// MP3デコーダーを作成（デコード結果は常に16ビットのステレオ）
+		decoder, err := mp3.NewDecoder(bytes.NewReader(audioData))
+		if err != nil {
+			return fmt.Errorf("failed to create MP3 decoder: %w", err)
+		}
+		source, sampleRate, channels = decoder, decoder.SampleRate(), 2
 	}
 
 	// オーディオコンテキストを初期化
-	ctx, ready, err := oto.NewContext(decoder.SampleRate(), 2, 2)
+	otoCtx, ready, err := oto.NewContext(sampleRate, channels, 2)
 	if err != nil {
 		return fmt.Errorf("failed to create audio context: %w", err)
 	}
 	<-ready
 
 	// オーディオプレイヤーを作成
-	player := ctx.NewPlayer(decoder)
+	player := otoCtx.NewPlayer(source)
 	defer player.Close()
 
 	// 再生開始
 	player.Play()
 
-	// 再生完了まで待機
+	// 再生完了まで待機（中断された場合はその場で止める）
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
 	for player.IsPlaying() {
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			player.Pause()
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
 
 	return nil
 }
 
 // SaveToFile 音声データをファイルに保存（オプション機能）
-func (t *TTSClient) SaveToFile(text, filename string) error {
-	fmt.Printf("🎵 音声ファイルを生成中: %s\n", filename)
+func (t *TTSClient) SaveToFile(ctx context.Context, text, filename string) error {
+	fmt.Fprintln(t.status, i18n.T("audio.generating_file", filename))
 
 	// 音声データを生成
-	audioData, err := t.synthesize(text)
+	audioData, err := t.synthesize(ctx, text)
 	if err != nil {
 		return fmt.Errorf("音声生成に失敗しました: %w", err)
 	}
 
 	// ファイルに保存
+	return t.WriteFile(audioData, filename)
+}
+
+// WriteFile 生成済みの音声データをファイルに保存
+func (t *TTSClient) WriteFile(audioData []byte, filename string) error {
 	if err := os.WriteFile(filename, audioData, 0644); err != nil {
 		return fmt.Errorf("ファイル保存に失敗しました: %w", err)
 	}
 
-	fmt.Printf("✅ 音声ファイルを保存しました: %s\n", filename)
+	fmt.Fprintln(t.status, i18n.T("audio.saved", filename))
 	return nil
 }
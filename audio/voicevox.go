@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"news_reporter/config"
+	"news_reporter/transport"
+)
+
+// maxVoicevoxInputLength VOICEVOX に1回で送る最大文字数
+// 長い文を一度に合成すると遅く、抑揚も崩れやすいため OpenAI より短く分ける
+const maxVoicevoxInputLength = 300
+
+// VoicevoxSynthesizer VOICEVOX 互換のHTTP API（VOICEVOX ENGINE・AivisSpeech など）で音声を合成する
+// /audio_query で読みと抑揚を求め、/synthesis でWAVを生成する
+type VoicevoxSynthesizer struct {
+	baseURL    string
+	speaker    string // 話者ID（/speakers の styles[].id）
+	speed      float64
+	httpClient *http.Client
+}
+
+// NewVoicevoxSynthesizer tts.voicevox の設定から音声合成クライアントを作成
+func NewVoicevoxSynthesizer(cfg *config.Config) *VoicevoxSynthesizer {
+	return &VoicevoxSynthesizer{
+		baseURL:    cfg.VoicevoxURL,
+		speaker:    cfg.Voice,
+		speed:      cfg.Speed,
+		httpClient: transport.NewHTTPClient(cfg, cfg.TTSTimeout),
+	}
+}
+
+// Format VOICEVOX は常にWAVを返す
+func (s *VoicevoxSynthesizer) Format() string {
+	return "wav"
+}
+
+// MaxInputLength 1回の合成に渡す最大文字数
+func (s *VoicevoxSynthesizer) MaxInputLength() int {
+	return maxVoicevoxInputLength
+}
+
+// Synthesize 音声合成用のクエリを作成し、話速を反映してからWAVを合成する
+func (s *VoicevoxSynthesizer) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	params := url.Values{"text": {text}, "speaker": {s.speaker}}
+	query, err := s.post(ctx, "/audio_query?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("audio_query: %w", err)
+	}
+
+	// クエリの他の項目はエンジンごとに異なるため、speedScale だけを書き換えてそのまま送り返す
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(query, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse audio query: %w", err)
+	}
+	if fields["speedScale"], err = json.Marshal(s.speed); err != nil {
+		return nil, fmt.Errorf("failed to marshal speed: %w", err)
+	}
+	if query, err = json.Marshal(fields); err != nil {
+		return nil, fmt.Errorf("failed to marshal audio query: %w", err)
+	}
+
+	params = url.Values{"speaker": {s.speaker}}
+	audioData, err := s.post(ctx, "/synthesis?"+params.Encode(), query)
+	if err != nil {
+		return nil, fmt.Errorf("synthesis: %w", err)
+	}
+	return audioData, nil
+}
+
+// post APIにPOSTして本文を返す（body が nil の場合は本文なし）
+func (s *VoicevoxSynthesizer) post(ctx context.Context, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		var retryErr *transport.RetryError
+		if errors.As(err, &retryErr) {
+			return nil, retryErr
+		}
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, transport.NewAPIError(resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,111 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"news_reporter/config"
+)
+
+func TestVoicevoxSynthesizer(t *testing.T) {
+	const wav = "RIFF....WAVEfmt "
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.Method != http.MethodPost {
+			t.Errorf("%s %s, want POST", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("speaker"); got != "3" {
+			t.Errorf("%s speaker = %q, want 3", r.URL.Path, got)
+		}
+
+		switch r.URL.Path {
+		case "/audio_query":
+			if got := r.URL.Query().Get("text"); got != "こんにちは、世界" {
+				t.Errorf("text = %q", got)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"accent_phrases":[{"moras":[{"text":"コ"}]}],"speedScale":1.0,"pitchScale":0.0,"kana":"コンニチワ"}`)
+		case "/synthesis":
+			if got := r.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			var query map[string]json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+				t.Fatalf("decode synthesis body: %v", err)
+			}
+			if got := string(query["speedScale"]); got != "1.5" {
+				t.Errorf("speedScale = %s, want 1.5", got)
+			}
+			// speedScale 以外の項目はエンジンが返したまま送り返す
+			if got := string(query["accent_phrases"]); got != `[{"moras":[{"text":"コ"}]}]` {
+				t.Errorf("accent_phrases = %s", got)
+			}
+			if got := string(query["kana"]); got != `"コンニチワ"` {
+				t.Errorf("kana = %s", got)
+			}
+			w.Header().Set("Content-Type", "audio/wav")
+			io.WriteString(w, wav)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.VoicevoxURL = server.URL
+	cfg.Voice = "3"
+	cfg.Speed = 1.5
+	got, err := NewVoicevoxSynthesizer(cfg).Synthesize(context.Background(), "こんにちは、世界")
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if string(got) != wav {
+		t.Errorf("audio = %q, want %q", got, wav)
+	}
+	if strings.Join(paths, ",") != "/audio_query,/synthesis" {
+		t.Errorf("requests = %v", paths)
+	}
+}
+
+func TestVoicevoxSynthesizerErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{"unknown speaker", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			io.WriteString(w, `{"detail":"speaker not found"}`)
+		}, "audio_query: API error (status 422"},
+		{"invalid audio query", func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, `not json`)
+		}, "failed to parse audio query"},
+		{"synthesis failed", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/audio_query" {
+				io.WriteString(w, `{"speedScale":1.0}`)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+		}, "synthesis: API error (status 400"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			cfg := config.Default()
+			cfg.VoicevoxURL = server.URL
+			cfg.MaxRetries = 0
+			_, err := NewVoicevoxSynthesizer(cfg).Synthesize(context.Background(), "テスト")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Synthesize error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
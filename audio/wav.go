@@ -0,0 +1,112 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// wavAudio RIFF/WAVE ファイルから取り出した fmt チャンクと波形データ
+type wavAudio struct {
+	format []byte // fmt チャンクの内容（16バイト以上）
+	data   []byte
+}
+
+// parseWAV WAVファイルを解析する
+// 標準出力に書き出されたWAVでは data チャンクの長さが不正な場合があるため、ファイルの終わりまでで打ち切る
+func parseWAV(wav []byte) (*wavAudio, error) {
+	if len(wav) < 12 || string(wav[0:4]) != "RIFF" || string(wav[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("not a WAV file")
+	}
+
+	audio := &wavAudio{}
+	for offset := 12; offset+8 <= len(wav); {
+		id := string(wav[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(wav[offset+4 : offset+8]))
+		start := offset + 8
+		end := start + size
+		if size < 0 || end > len(wav) {
+			end = len(wav)
+		}
+
+		switch id {
+		case "fmt ":
+			audio.format = wav[start:end]
+		case "data":
+			audio.data = wav[start:end]
+		}
+		if audio.format != nil && audio.data != nil {
+			break
+		}
+		// チャンクは2バイト境界に揃えられている
+		offset = end + end%2
+	}
+
+	if len(audio.format) < 16 {
+		return nil, fmt.Errorf("WAV file has no fmt chunk")
+	}
+	if audio.data == nil {
+		return nil, fmt.Errorf("WAV file has no data chunk")
+	}
+	return audio, nil
+}
+
+// audioFormat 波形の符号化方式（1 = リニアPCM）
+func (w *wavAudio) audioFormat() int {
+	return int(binary.LittleEndian.Uint16(w.format[0:2]))
+}
+
+// channels チャンネル数
+func (w *wavAudio) channels() int {
+	return int(binary.LittleEndian.Uint16(w.format[2:4]))
+}
+
+// sampleRate サンプリング周波数（Hz）
+func (w *wavAudio) sampleRate() int {
+	return int(binary.LittleEndian.Uint32(w.format[4:8]))
+}
+
+// bitsPerSample 1サンプルあたりのビット数
+func (w *wavAudio) bitsPerSample() int {
+	return int(binary.LittleEndian.Uint16(w.format[14:16]))
+}
+
+// concatWAV 同じ形式のWAVファイルを1つのファイルに連結する
+// 各ファイルの波形データをつなぎ、ヘッダーは1つ目のファイルの fmt チャンクから作り直す
+func concatWAV(parts [][]byte) ([]byte, error) {
+	var format []byte
+	var data bytes.Buffer
+	for i, part := range parts {
+		audio, err := parseWAV(part)
+		if err != nil {
+			return nil, fmt.Errorf("part %d: %w", i+1, err)
+		}
+		if format == nil {
+			format = audio.format
+		} else if !bytes.Equal(format[:16], audio.format[:16]) {
+			return nil, fmt.Errorf("part %d: WAV format differs from the first part", i+1)
+		}
+		data.Write(audio.data)
+	}
+	if format == nil {
+		return nil, fmt.Errorf("no WAV data to concatenate")
+	}
+
+	// 奇数長のチャンクには埋め草の1バイトを付ける
+	formatPad := len(format) % 2
+	dataPad := data.Len() % 2
+
+	var out bytes.Buffer
+	out.WriteString("RIFF")
+	binary.Write(&out, binary.LittleEndian, uint32(4+8+len(format)+formatPad+8+data.Len()+dataPad))
+	out.WriteString("WAVE")
+	out.WriteString("fmt ")
+	binary.Write(&out, binary.LittleEndian, uint32(len(format)))
+	out.Write(format)
+	out.Write(make([]byte, formatPad))
+	out.WriteString("data")
+	binary.Write(&out, binary.LittleEndian, uint32(data.Len()))
+	out.Write(data.Bytes())
+	out.Write(make([]byte, dataPad))
+	return out.Bytes(), nil
+}
@@ -0,0 +1,164 @@
+// Package cache 検索結果や合成した音声をメモリとディスクに保存し、有効期限内は再利用する
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxMemoryItems メモリに保持する件数の上限（サーバー・デーモンで増え続けないようにする）
+const maxMemoryItems = 64
+
+// Key 値を連結してキャッシュのキー（SHA-256 の16進表記）を作る
+func Key(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// item メモリに保持する1件
+type item struct {
+	data  []byte
+	saved time.Time
+}
+
+// Store キーごとのデータをメモリと dir 以下のファイルに保存し、保存から ttl の間だけ再利用する
+// ディスクの記録は保存時刻をファイルの更新時刻で表すため、別のプロセスが保存したものも再利用できる
+// 有効期限を過ぎたファイルは、読み込もうとしたときと同じディレクトリに保存したときに削除する
+type Store struct {
+	dir    string
+	ttl    time.Duration
+	mu     sync.Mutex
+	memory map[string]item
+	now    func() time.Time
+}
+
+// NewStore dir を保存先とし、保存から ttl の間だけ再利用する新しいキャッシュを作成
+func NewStore(dir string, ttl time.Duration) *Store {
+	return &Store{
+		dir:    dir,
+		ttl:    ttl,
+		memory: make(map[string]item),
+		now:    time.Now,
+	}
+}
+
+// Dir 保存先のディレクトリを返す
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// path キーに対応するファイルのパス（1つのディレクトリにファイルが集中しないよう先頭2文字で分ける）
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key)
+}
+
+// Get 有効期限内のデータと保存時刻を返す（なければ false）
+// 読み込めないファイルは保存されていないものとして扱い、有効期限を過ぎたファイルは削除する
+func (s *Store) Get(key string) ([]byte, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if cached, ok := s.memory[key]; ok {
+		if !s.expired(cached.saved, now) {
+			return cached.data, cached.saved, true
+		}
+		delete(s.memory, key)
+	}
+
+	path := s.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	if s.expired(info.ModTime(), now) {
+		os.Remove(path)
+		return nil, time.Time{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	s.remember(key, item{data: data, saved: info.ModTime()})
+	return data, info.ModTime(), true
+}
+
+// Put データを保存する（書き込み途中のファイルを読まれないよう一時ファイルから置き換える）
+// 保存先のディレクトリにある有効期限を過ぎたファイルも合わせて削除する
+func (s *Store) Put(key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	_, writeErr := file.Write(data)
+	closeErr := file.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to save cache file: %w", err)
+	}
+
+	s.remember(key, item{data: data, saved: s.now()})
+	s.prune(filepath.Dir(path), key)
+	return nil
+}
+
+// expired 保存時刻 saved のデータが有効期限を過ぎているか
+func (s *Store) expired(saved, now time.Time) bool {
+	return now.Sub(saved) > s.ttl
+}
+
+// prune ディレクトリ内の有効期限を過ぎたファイル（中断された書き込みの一時ファイルを含む）を削除する
+// 削除に失敗したファイルは次の機会に削除する
+func (s *Store) prune(dir, keep string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	now := s.now()
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == keep {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !s.expired(info.ModTime(), now) {
+			continue
+		}
+		os.Remove(filepath.Join(dir, entry.Name()))
+		delete(s.memory, entry.Name())
+	}
+}
+
+// remember メモリに保持する（上限を超える場合は最も古いものを捨てる）
+func (s *Store) remember(key string, value item) {
+	if _, ok := s.memory[key]; !ok && len(s.memory) >= maxMemoryItems {
+		oldest := ""
+		for k, v := range s.memory {
+			if oldest == "" || v.saved.Before(s.memory[oldest].saved) {
+				oldest = k
+			}
+		}
+		delete(s.memory, oldest)
+	}
+	s.memory[key] = value
+}
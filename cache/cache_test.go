@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, time.Hour)
+	key := Key("search", "ai ニュース", "gpt-4o-mini")
+	if key == Key("search", "ai ニュース", "gpt-4o") || key == Key("searchai ニュース", "gpt-4o-mini") {
+		t.Fatal("different parts produced the same key")
+	}
+
+	if _, _, ok := store.Get(key); ok {
+		t.Fatal("Get hit an empty cache")
+	}
+	if err := store.Put(key, []byte("result")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _, ok := store.Get(key); !ok || string(data) != "result" {
+		t.Fatalf("Get = %q, %v", data, ok)
+	}
+
+	// 別のプロセス（新しい Store）でもディスクから読み込める
+	if data, _, ok := NewStore(dir, time.Hour).Get(key); !ok || string(data) != "result" {
+		t.Fatalf("Get from a new store = %q, %v", data, ok)
+	}
+
+	// 有効期限を過ぎたものはメモリ・ディスクとも使わず、ファイルも削除する
+	store.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, _, ok := store.Get(key); ok {
+		t.Error("Get returned an expired entry")
+	}
+	if _, err := os.Stat(store.path(key)); !os.IsNotExist(err) {
+		t.Errorf("expired file was not removed: %v", err)
+	}
+
+	// 書き込み途中の一時ファイルは残さない
+	files, err := filepath.Glob(filepath.Join(dir, key[:2], "*.tmp"))
+	if err != nil || len(files) != 0 {
+		t.Errorf("temporary files left: %v, %v", files, err)
+	}
+}
+
+func TestStoreMemoryLimit(t *testing.T) {
+	store := NewStore(t.TempDir(), time.Hour)
+	for i := 0; i < maxMemoryItems+10; i++ {
+		if err := store.Put(Key(fmt.Sprint(i)), []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(store.memory) != maxMemoryItems {
+		t.Errorf("kept %d items in memory, want %d", len(store.memory), maxMemoryItems)
+	}
+
+	// メモリから捨てたものもディスクから読み込める
+	if data, _, ok := store.Get(Key("0")); !ok || data[0] != 0 {
+		t.Errorf("Get after eviction = %v, %v", data, ok)
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	store := NewStore(t.TempDir(), time.Hour)
+	stale, fresh := Key("stale"), ""
+	for i := 0; fresh == ""; i++ {
+		if key := Key(fmt.Sprint(i)); key[:2] == stale[:2] {
+			fresh = key
+		}
+	}
+
+	// 再び読まれることのない古いファイルも、同じディレクトリへの保存で削除する
+	if err := store.Put(stale, []byte("old")); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(store.path(stale), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(fresh, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(store.path(stale)); !os.IsNotExist(err) {
+		t.Errorf("expired file was not pruned: %v", err)
+	}
+	if _, _, ok := store.Get(stale); ok {
+		t.Error("Get returned a pruned entry from memory")
+	}
+	if data, _, ok := store.Get(fresh); !ok || string(data) != "new" {
+		t.Errorf("Get after prune = %q, %v", data, ok)
+	}
+}
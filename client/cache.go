@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"news_reporter/cache"
+	"news_reporter/config"
+	"news_reporter/i18n"
+	"news_reporter/models"
+	"news_reporter/prompt"
+)
+
+// cacheFormat キャッシュの保存形式の版（SearchResult の形を変えたら上げて古いキャッシュを使わないようにする）
+const cacheFormat = "1"
+
+// CachedSearcher OpenAIClient の前に置き、同じ検索・生成の結果を有効期限内は再利用する
+// キーは正規化したクエリ・実際に使うモデル・温度・プロンプトの版から作る
+// 使用額の上限に近づいて安価なモデルに切り替えた結果は、元のモデルの結果とは別に保存する
+// プロンプトの版は描画したシステムプロンプトとクエリのハッシュのため、日付が変わるとキャッシュも使われなくなる
+type CachedSearcher struct {
+	client *OpenAIClient
+	store  *cache.Store
+	mode   string
+}
+
+// NewCachedSearcher client の結果を cfg.CacheDir にキャッシュする検索バックエンドを作成
+func NewCachedSearcher(cfg *config.Config, client *OpenAIClient) *CachedSearcher {
+	return &CachedSearcher{
+		client: client,
+		store:  cache.NewStore(filepath.Join(cfg.CacheDir, "search"), cfg.CacheTTL),
+		mode:   cfg.CacheMode,
+	}
+}
+
+// Search Web検索を実行（キャッシュがあれば検索しない）
+func (c *CachedSearcher) Search(ctx context.Context, query string) (*models.SearchResult, error) {
+	return c.SearchStream(ctx, query, nil)
+}
+
+// SearchStream Web検索を実行し、受信したイベントを逐次コールバックに渡す
+// キャッシュを使った場合は要約全体を1つの差分として通知する
+func (c *CachedSearcher) SearchStream(ctx context.Context, query string, onEvent models.StreamCallback) (*models.SearchResult, error) {
+	model, candidates, budgetErr := c.models()
+	for _, candidate := range candidates {
+		key, err := c.searchKey(query, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if result, ok := c.lookup(key); ok {
+			result.Query = query // 正規化前のクエリで表示・記録する
+			replay(result, onEvent)
+			return result, nil
+		}
+	}
+	if budgetErr != nil {
+		return nil, budgetErr
+	}
+
+	key, err := c.searchKey(query, model)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.client.searchStream(ctx, query, model, onEvent)
+	if err != nil {
+		return result, err
+	}
+	c.save(key, result)
+	return result, nil
+}
+
+// Generate 指示と入力からテキストを生成（同じ指示・入力の結果があれば再利用する）
+func (c *CachedSearcher) Generate(ctx context.Context, instructions, input string) (*models.SearchResult, error) {
+	model, candidates, budgetErr := c.models()
+	for _, candidate := range candidates {
+		if result, ok := c.lookup(generateKey(candidate, instructions, input)); ok {
+			return result, nil
+		}
+	}
+	if budgetErr != nil {
+		return nil, budgetErr
+	}
+
+	result, err := c.client.generate(ctx, model, instructions, input)
+	if err != nil {
+		return result, err
+	}
+	c.save(generateKey(model, instructions, input), result)
+	return result, nil
+}
+
+// models 使用額の上限に応じて使うモデルと、キャッシュを探すモデルの一覧を返す
+// 上限に達している場合（err が nil でない場合）も、キャッシュした結果は費用がかからないためどちらのモデルの結果も探す
+func (c *CachedSearcher) models() (model string, candidates []string, err error) {
+	model, err = c.client.model()
+	if err == nil {
+		return model, []string{model}, nil
+	}
+	candidates = []string{c.client.config.Model}
+	if fallback := c.client.budget.FallbackModel(); fallback != "" && fallback != c.client.config.Model {
+		candidates = append(candidates, fallback)
+	}
+	return "", candidates, err
+}
+
+// PromptVars 設定と現在時刻からプロンプトテンプレートの変数を組み立てる
+func (c *CachedSearcher) PromptVars(query string) prompt.Vars {
+	return c.client.PromptVars(query)
+}
+
+// searchKey model で検索した結果のキャッシュのキー
+func (c *CachedSearcher) searchKey(query, model string) (string, error) {
+	normalized := normalizeQuery(query)
+	vars := c.client.PromptVars(normalized)
+	systemMessage, err := prompt.System(c.client.config.SystemPrompt, vars)
+	if err != nil {
+		return "", fmt.Errorf("システムプロンプト: %w", err)
+	}
+	enhancedQuery, err := prompt.Query(c.client.config.QueryTemplate, vars)
+	if err != nil {
+		return "", fmt.Errorf("クエリテンプレート: %w", err)
+	}
+	promptVersion := cache.Key(systemMessage, enhancedQuery)
+
+	return cache.Key(cacheFormat, "search", normalized, model,
+		strconv.FormatFloat(c.client.config.Temperature, 'g', -1, 64), promptVersion), nil
+}
+
+// generateKey model で生成した結果のキャッシュのキー
+func generateKey(model, instructions, input string) string {
+	return cache.Key(cacheFormat, "generate", model, instructions, input)
+}
+
+// lookup 有効期限内のキャッシュを読み込む（--refresh の場合や壊れたキャッシュは使わない）
+func (c *CachedSearcher) lookup(key string) (*models.SearchResult, bool) {
+	if c.mode == config.CacheModeRefresh {
+		return nil, false
+	}
+	data, _, ok := c.store.Get(key)
+	if !ok {
+		return nil, false
+	}
+	var result models.SearchResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, false
+	}
+	result.Cached = true
+	return &result, true
+}
+
+// save 完了した結果を保存する（保存の失敗は警告のみ）
+func (c *CachedSearcher) save(key string, result *models.SearchResult) {
+	if result == nil || result.Partial {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err == nil {
+		err = c.store.Put(key, data)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("cache.result_failed", err))
+	}
+}
+
+// replay キャッシュした結果をストリーミングのイベントとして通知
+func replay(result *models.SearchResult, onEvent models.StreamCallback) {
+	if onEvent == nil {
+		return
+	}
+	if result.Summary != "" {
+		onEvent(models.StreamEvent{Type: models.StreamEventDelta, Delta: result.Summary})
+	}
+	onEvent(models.StreamEvent{Type: models.StreamEventDone})
+}
+
+// normalizeQuery 大文字・小文字や空白の違いだけのクエリを同じものとして扱う
+func normalizeQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
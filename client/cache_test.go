@@ -0,0 +1,45 @@
+package client
+
+import (
+	"testing"
+
+	"news_reporter/config"
+	"news_reporter/models"
+)
+
+func TestCachedSearcherKeyModel(t *testing.T) {
+	cfg := config.Default()
+	cfg.CacheDir = t.TempDir()
+	c := NewCachedSearcher(cfg, NewOpenAIClient(cfg))
+
+	primary, err := c.searchKey("AI ニュース", cfg.Model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fallback, err := c.searchKey("AI ニュース", "gpt-4.1-nano")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if primary == fallback {
+		t.Error("search keys for different models are equal")
+	}
+	normalized, err := c.searchKey("  ai　ニュース ", cfg.Model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalized != primary {
+		t.Error("search key differs for a query that only differs in case and spaces")
+	}
+	if generateKey(cfg.Model, "指示", "入力") == generateKey("gpt-4.1-nano", "指示", "入力") {
+		t.Error("generate keys for different models are equal")
+	}
+
+	// 安価なモデルで検索した結果は、元のモデルの検索には使わない
+	c.save(fallback, &models.SearchResult{Summary: "要約", Model: "gpt-4.1-nano"})
+	if _, ok := c.lookup(primary); ok {
+		t.Error("the fallback model's result was found under the primary model's key")
+	}
+	if result, ok := c.lookup(fallback); !ok || result.Summary != "要約" {
+		t.Errorf("lookup(fallback) = %+v, %v", result, ok)
+	}
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"news_reporter/config"
+	"news_reporter/models"
+	"news_reporter/transport"
+)
+
+// ChatClient Chat Completions 互換API（OpenAI・Ollama・vLLM など）のクライアント
+type ChatClient struct {
+	baseURL     string
+	apiKey      string
+	model       string
+	temperature float64
+	httpClient  *http.Client
+}
+
+// ChatCompletion ストリーミングで受信した応答をまとめたもの
+type ChatCompletion struct {
+	Content string
+	Model   string
+	Usage   *models.Usage
+}
+
+// NewChatClient フィード検索の設定（feed セクション）からクライアントを作成
+func NewChatClient(cfg *config.Config) *ChatClient {
+	return &ChatClient{
+		baseURL:     cfg.FeedBaseURL,
+		apiKey:      cfg.FeedAPIKey,
+		model:       cfg.FeedModel,
+		temperature: cfg.Temperature,
+		httpClient:  transport.NewHTTPClient(cfg, cfg.SearchTimeout),
+	}
+}
+
+// Complete メッセージを送信し、応答のテキストを受信しながら onDelta に渡す
+// ctx のキャンセルで受信が途切れた場合は、受信済みの内容もエラーと一緒に返す
+func (c *ChatClient) Complete(ctx context.Context, messages []models.Message, onDelta func(string)) (*ChatCompletion, error) {
+	temperature := c.temperature
+	request := models.ChatRequest{
+		Model:         c.model,
+		Messages:      messages,
+		Stream:        true,
+		StreamOptions: &models.ChatStreamOptions{IncludeUsage: true},
+		Temperature:   &temperature,
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		var retryErr *transport.RetryError
+		if errors.As(err, &retryErr) {
+			return nil, retryErr
+		}
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, transport.NewAPIError(resp)
+	}
+
+	completion, err := c.processChatStream(ctx, resp.Body, onDelta)
+	if completion != nil && completion.Model == "" {
+		completion.Model = c.model
+	}
+	return completion, err
+}
+
+// processChatStream Chat Completions のストリーミングレスポンスを処理
+func (c *ChatClient) processChatStream(ctx context.Context, body io.Reader, onDelta func(string)) (*ChatCompletion, error) {
+	decoder := newSSEDecoder(body)
+	completion := &ChatCompletion{}
+	var content strings.Builder
+
+	for {
+		sse, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				completion.Content = content.String()
+				return completion, fmt.Errorf("stream interrupted: %w", ctx.Err())
+			}
+			return nil, fmt.Errorf("error reading stream: %w", err)
+		}
+		if sse.Data == "[DONE]" {
+			break
+		}
+
+		// ストリームの途中でエラーが通知される場合がある
+		if strings.Contains(sse.Data, `"error"`) {
+			if apiErr := transport.ParseAPIError(http.StatusOK, nil, []byte(sse.Data)); apiErr.Code != "" || apiErr.Type != "" {
+				return nil, apiErr
+			}
+		}
+
+		var chunk models.ChatChunk
+		if err := json.Unmarshal([]byte(sse.Data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+		if chunk.Model != "" {
+			completion.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			completion.Usage = &models.Usage{
+				InputTokens:  chunk.Usage.PromptTokens,
+				OutputTokens: chunk.Usage.CompletionTokens,
+				TotalTokens:  chunk.Usage.TotalTokens,
+			}
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta == nil || choice.Delta.Content == "" {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			if onDelta != nil {
+				onDelta(choice.Delta.Content)
+			}
+		}
+	}
+
+	completion.Content = content.String()
+	return completion, nil
+}
@@ -0,0 +1,485 @@
+package client
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"news_reporter/config"
+	"news_reporter/i18n"
+	"news_reporter/models"
+	"news_reporter/prompt"
+	"news_reporter/transport"
+	"news_reporter/usage"
+)
+
+// maxFeedSummary 要約に渡す記事本文の最大文字数
+const maxFeedSummary = 400
+
+// FeedSearcher RSS/Atom フィードから記事を集め、Chat Completions 互換APIで要約する検索バックエンド
+// OpenAI の web_search_preview ツールが使えない環境（ローカルの Ollama など）で使う
+type FeedSearcher struct {
+	config     *config.Config
+	httpClient *http.Client
+	chat       *ChatClient
+}
+
+// feedItem フィードから取り出した1件の記事
+type feedItem struct {
+	Title     string
+	Link      string
+	Summary   string
+	Source    string // フィードのタイトル
+	Published time.Time
+}
+
+// NewFeedSearcher 新しいフィード検索バックエンドを作成
+func NewFeedSearcher(cfg *config.Config) *FeedSearcher {
+	return &FeedSearcher{
+		config:     cfg,
+		httpClient: transport.NewHTTPClient(cfg, cfg.SearchTimeout),
+		chat:       NewChatClient(cfg),
+	}
+}
+
+// Search フィードから関連する記事を選んで要約する
+func (f *FeedSearcher) Search(ctx context.Context, query string) (*models.SearchResult, error) {
+	return f.SearchStream(ctx, query, nil)
+}
+
+// SearchStream フィードから関連する記事を選び、要約を受信しながらコールバックに渡す
+// 記事の取得は web_search イベント、要約は delta イベントとして通知する
+func (f *FeedSearcher) SearchStream(ctx context.Context, query string, onEvent models.StreamCallback) (*models.SearchResult, error) {
+	emit := func(event models.StreamEvent) {
+		if onEvent != nil {
+			event.Timestamp = time.Now()
+			onEvent(event)
+		}
+	}
+
+	emit(models.StreamEvent{Type: models.StreamEventCreated})
+	emit(models.StreamEvent{Type: models.StreamEventWebSearch, Status: "searching"})
+	items, err := f.fetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vars := f.PromptVars(query)
+	selected := selectFeedItems(items, query, vars.Now, f.config.RecencyDays, f.config.FeedMaxItems)
+	emit(models.StreamEvent{Type: models.StreamEventWebSearch, Status: "completed"})
+
+	systemMessage, err := prompt.Render(prompt.KindFeed, "", vars)
+	if err != nil {
+		return nil, fmt.Errorf("システムプロンプト: %w", err)
+	}
+	enhancedQuery, err := prompt.Query(f.config.QueryTemplate, vars)
+	if err != nil {
+		return nil, fmt.Errorf("クエリテンプレート: %w", err)
+	}
+
+	result := &models.SearchResult{
+		Query:     query,
+		Results:   make([]models.WebSearchResult, 0, len(selected)),
+		Timestamp: time.Now(),
+	}
+	for _, item := range selected {
+		result.Results = append(result.Results, models.WebSearchResult{
+			Title:   item.Title,
+			URL:     item.Link,
+			Snippet: item.snippet(),
+		})
+	}
+
+	messages := []models.Message{
+		{Role: "system", Content: systemMessage},
+		{Role: "user", Content: enhancedQuery + "\n\n" + buildFeedArticles(selected)},
+	}
+	completion, err := f.chat.Complete(ctx, messages, func(delta string) {
+		emit(models.StreamEvent{Type: models.StreamEventDelta, Delta: delta})
+	})
+	if completion != nil {
+		result.Summary = completion.Content
+		result.Model = completion.Model
+		result.Usage = completion.Usage
+		result.Cost = usage.Estimate(f.config.Pricing, result.Model, result.Usage)
+	}
+	if err != nil {
+		// 中断された場合は受信済みの要約と選んだ記事を返す
+		if completion != nil && ctx.Err() != nil {
+			result.Partial = true
+			return result, err
+		}
+		return nil, err
+	}
+
+	emit(models.StreamEvent{Type: models.StreamEventDone})
+	return result, nil
+}
+
+// Generate 検索を行わずに指示と入力からテキストを生成
+func (f *FeedSearcher) Generate(ctx context.Context, instructions, input string) (*models.SearchResult, error) {
+	completion, err := f.chat.Complete(ctx, []models.Message{
+		{Role: "system", Content: instructions},
+		{Role: "user", Content: input},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SearchResult{
+		Summary:   completion.Content,
+		Timestamp: time.Now(),
+		Model:     completion.Model,
+		Usage:     completion.Usage,
+		Cost:      usage.Estimate(f.config.Pricing, completion.Model, completion.Usage),
+	}, nil
+}
+
+// PromptVars 設定と現在時刻からプロンプトテンプレートの変数を組み立てる
+func (f *FeedSearcher) PromptVars(query string) prompt.Vars {
+	return promptVars(f.config, query)
+}
+
+// fetchAll 設定されたフィードを並列に取得する（一部のフィードの失敗は警告して続行）
+func (f *FeedSearcher) fetchAll(ctx context.Context) ([]feedItem, error) {
+	lists := make([][]feedItem, len(f.config.Feeds))
+	errs := make([]error, len(f.config.Feeds))
+	var wg sync.WaitGroup
+
+	for i, url := range f.config.Feeds {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			lists[i], errs[i] = f.fetchFeed(ctx, url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var items []feedItem
+	var firstErr error
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("feed.fetch_failed", f.config.Feeds[i], err))
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		items = append(items, lists[i]...)
+	}
+	if len(items) == 0 && firstErr != nil {
+		return nil, fmt.Errorf("%s: %w", i18n.T("feed.unavailable"), firstErr)
+	}
+	return items, nil
+}
+
+// fetchFeed 1つのフィードを取得して記事を取り出す
+func (f *FeedSearcher) fetchFeed(ctx context.Context, url string) ([]feedItem, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	req.Header.Set("User-Agent", "news_reporter")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		var retryErr *transport.RetryError
+		if errors.As(err, &retryErr) {
+			return nil, retryErr
+		}
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed request failed with status %d", resp.StatusCode)
+	}
+	return parseFeed(resp.Body)
+}
+
+// rssItem RSS 2.0 / RSS 1.0 の item 要素
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+// feedDocument RSS 2.0（rss/channel/item）・RSS 1.0（rdf:RDF/item）・Atom（feed/entry）をまとめて読むための構造
+type feedDocument struct {
+	XMLName xml.Name
+	Title   string    `xml:"title"` // Atom
+	Items   []rssItem `xml:"item"`  // RSS 1.0
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"` // RSS 2.0
+	} `xml:"channel"`
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Updated   string `xml:"updated"`
+		Published string `xml:"published"`
+	} `xml:"entry"`
+}
+
+// parseFeed RSS または Atom の文書から記事を取り出す
+func parseFeed(r io.Reader) ([]feedItem, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(charset) {
+		case "utf-8", "utf8", "us-ascii", "ascii":
+			return input, nil
+		}
+		return nil, fmt.Errorf("未対応の文字コードです: %s (UTF-8 のフィードのみ対応)", charset)
+	}
+
+	var doc feedDocument
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("フィードを解析できません: %w", err)
+	}
+
+	var items []feedItem
+	switch doc.XMLName.Local {
+	case "rss", "RDF":
+		source := doc.Channel.Title
+		rssItems := append(doc.Channel.Items, doc.Items...)
+		for _, item := range rssItems {
+			date := item.PubDate
+			if date == "" {
+				date = item.Date
+			}
+			items = append(items, feedItem{
+				Title:     cleanText(item.Title),
+				Link:      strings.TrimSpace(item.Link),
+				Summary:   cleanText(item.Description),
+				Source:    cleanText(source),
+				Published: parseFeedTime(date),
+			})
+		}
+	case "feed":
+		for _, entry := range doc.Entries {
+			item := feedItem{
+				Title:   cleanText(entry.Title),
+				Summary: cleanText(entry.Summary),
+				Source:  cleanText(doc.Title),
+			}
+			if item.Summary == "" {
+				item.Summary = cleanText(entry.Content)
+			}
+			for _, link := range entry.Links {
+				if link.Rel == "" || link.Rel == "alternate" {
+					item.Link = strings.TrimSpace(link.Href)
+					break
+				}
+			}
+			item.Published = parseFeedTime(entry.Published)
+			if item.Published.IsZero() {
+				item.Published = parseFeedTime(entry.Updated)
+			}
+			items = append(items, item)
+		}
+	default:
+		return nil, fmt.Errorf("RSS または Atom のフィードではありません (<%s>)", doc.XMLName.Local)
+	}
+	return items, nil
+}
+
+// feedTimeLayouts フィードで使われる日時の書式（RFC 822 系と RFC 3339 系）
+var feedTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02",
+}
+
+// parseFeedTime フィードの日時を解析する（解析できない場合はゼロ値）
+func parseFeedTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// cleanText HTMLタグと文字参照を取り除き、空白を詰める
+func cleanText(text string) string {
+	text = htmlTagPattern.ReplaceAllString(text, " ")
+	text = html.UnescapeString(text)
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+}
+
+// snippet 検索結果に表示する記事の説明（情報源と日付）
+func (item feedItem) snippet() string {
+	parts := make([]string, 0, 2)
+	if item.Source != "" {
+		parts = append(parts, item.Source)
+	}
+	if !item.Published.IsZero() {
+		parts = append(parts, item.Published.Format("2006-01-02 15:04"))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// genericTerms どの記事にも当てはまるため関連度の計算に使わない語
+var genericTerms = []string{"ニュース", "最新", "今日", "情報", "動向", "news", "latest", "today"}
+
+// queryTerms クエリを記事と照合する語に分ける
+// 英数字の語はそのまま使い、日本語など空白で区切らない文字の並びは2文字ずつに分ける
+// ひらがなを含む組（助詞など）は関連度の手がかりにならないため使わない
+func queryTerms(query string) []string {
+	query = strings.ToLower(query)
+	for _, generic := range genericTerms {
+		query = strings.ReplaceAll(query, generic, " ")
+	}
+
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		for _, segment := range splitScript(field) {
+			runes := []rune(segment)
+			if runes[0] <= unicode.MaxASCII {
+				if len(runes) >= 2 {
+					terms = append(terms, segment)
+				}
+				continue
+			}
+			if len(runes) == 1 && !unicode.In(runes[0], unicode.Hiragana) {
+				terms = append(terms, segment)
+				continue
+			}
+			for i := 0; i+1 < len(runes); i++ {
+				if unicode.In(runes[i], unicode.Hiragana) || unicode.In(runes[i+1], unicode.Hiragana) {
+					continue
+				}
+				terms = append(terms, string(runes[i:i+2]))
+			}
+		}
+	}
+	return terms
+}
+
+// splitScript 文字列をASCII文字の並びとそれ以外の並びに分ける（例: "aiの技術" → "ai", "の技術"）
+func splitScript(s string) []string {
+	var segments []string
+	start := 0
+	runes := []rune(s)
+	for i := 1; i <= len(runes); i++ {
+		if i == len(runes) || (runes[i] <= unicode.MaxASCII) != (runes[start] <= unicode.MaxASCII) {
+			segments = append(segments, string(runes[start:i]))
+			start = i
+		}
+	}
+	return segments
+}
+
+// selectFeedItems 最新とみなす期間内の記事から、クエリに関連する順に最大 limit 件を選ぶ
+// 関連する記事がない場合（「今日のニュース」のような漠然としたクエリ）は新しい順に選ぶ
+func selectFeedItems(items []feedItem, query string, now time.Time, recencyDays, limit int) []feedItem {
+	cutoff := now.AddDate(0, 0, -recencyDays)
+	terms := queryTerms(query)
+
+	type scored struct {
+		item  feedItem
+		score int
+	}
+	var candidates []scored
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if !item.Published.IsZero() && item.Published.Before(cutoff) {
+			continue
+		}
+		// 複数のフィードに同じ記事が載っている場合は1件にまとめる
+		if item.Link != "" {
+			if seen[item.Link] {
+				continue
+			}
+			seen[item.Link] = true
+		}
+
+		title := strings.ToLower(item.Title)
+		summary := strings.ToLower(item.Summary)
+		score := 0
+		for _, term := range terms {
+			if strings.Contains(title, term) {
+				score += 2
+			}
+			if strings.Contains(summary, term) {
+				score++
+			}
+		}
+		candidates = append(candidates, scored{item: item, score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].item.Published.After(candidates[j].item.Published)
+	})
+
+	relevant := len(candidates) > 0 && candidates[0].score > 0
+	selected := make([]feedItem, 0, limit)
+	for _, c := range candidates {
+		if len(selected) >= limit || (relevant && c.score == 0) {
+			break
+		}
+		selected = append(selected, c.item)
+	}
+	return selected
+}
+
+// buildFeedArticles 要約用に記事の一覧を番号付きで組み立てる
+func buildFeedArticles(items []feedItem) string {
+	if len(items) == 0 {
+		return "(no articles)"
+	}
+
+	var b strings.Builder
+	for i, item := range items {
+		fmt.Fprintf(&b, "[%d] %s", i+1, item.Title)
+		if snippet := item.snippet(); snippet != "" {
+			fmt.Fprintf(&b, " (%s)", snippet)
+		}
+		b.WriteString("\n")
+		if item.Link != "" {
+			b.WriteString(item.Link + "\n")
+		}
+		if summary := []rune(item.Summary); len(summary) > maxFeedSummary {
+			b.WriteString(string(summary[:maxFeedSummary]) + "…\n")
+		} else if len(summary) > 0 {
+			b.WriteString(item.Summary + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,252 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"news_reporter/config"
+)
+
+const rss2Feed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>テックニュース</title>
+    <item>
+      <title>生成AIの規制案がまとまる</title>
+      <link> https://example.com/regulation </link>
+      <description><![CDATA[<p>政府は<b>生成AI</b>の規制案を&amp;公表した。</p>
+<p>来年から施行する。</p>]]></description>
+      <pubDate>Mon, 14 Oct 2024 09:30:00 +0900</pubDate>
+    </item>
+    <item>
+      <title>日付のない記事</title>
+      <link>https://example.com/undated</link>
+      <description>本文</description>
+    </item>
+  </channel>
+</rss>`
+
+const rdfFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>経済ニュース</title>
+  </channel>
+  <item>
+    <title>日経平均が続伸</title>
+    <link>https://example.jp/nikkei</link>
+    <description>東京株式市場で日経平均が続伸した。</description>
+    <dc:date>2024-10-14T15:00:00+09:00</dc:date>
+  </item>
+</rdf:RDF>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Dev Blog</title>
+  <entry>
+    <title>Go 1.24 released</title>
+    <link rel="self" href="https://example.org/self"/>
+    <link rel="alternate" href="https://example.org/go124"/>
+    <content type="html">&lt;p&gt;Go 1.24 is &lt;em&gt;out&lt;/em&gt;.&lt;/p&gt;</content>
+    <updated>2024-10-13T12:00:00Z</updated>
+  </entry>
+  <entry>
+    <title>Updated only</title>
+    <link href="https://example.org/updated"/>
+    <summary>summary wins over content</summary>
+    <content>content</content>
+    <published>2024-10-12T08:00:00Z</published>
+    <updated>2024-10-13T08:00:00Z</updated>
+  </entry>
+</feed>`
+
+func TestParseFeed(t *testing.T) {
+	jst := time.FixedZone("", 9*60*60)
+	tests := []struct {
+		name string
+		feed string
+		want []feedItem
+	}{
+		{"rss 2.0", rss2Feed, []feedItem{
+			{
+				Title:     "生成AIの規制案がまとまる",
+				Link:      "https://example.com/regulation",
+				Summary:   "政府は 生成AI の規制案を&公表した。 来年から施行する。",
+				Source:    "テックニュース",
+				Published: time.Date(2024, 10, 14, 9, 30, 0, 0, jst),
+			},
+			{Title: "日付のない記事", Link: "https://example.com/undated", Summary: "本文", Source: "テックニュース"},
+		}},
+		{"rss 1.0", rdfFeed, []feedItem{
+			{
+				Title:     "日経平均が続伸",
+				Link:      "https://example.jp/nikkei",
+				Summary:   "東京株式市場で日経平均が続伸した。",
+				Source:    "経済ニュース",
+				Published: time.Date(2024, 10, 14, 15, 0, 0, 0, jst),
+			},
+		}},
+		{"atom", atomFeed, []feedItem{
+			{
+				Title:     "Go 1.24 released",
+				Link:      "https://example.org/go124",
+				Summary:   "Go 1.24 is out .",
+				Source:    "Dev Blog",
+				Published: time.Date(2024, 10, 13, 12, 0, 0, 0, time.UTC),
+			},
+			{
+				Title:     "Updated only",
+				Link:      "https://example.org/updated",
+				Summary:   "summary wins over content",
+				Source:    "Dev Blog",
+				Published: time.Date(2024, 10, 12, 8, 0, 0, 0, time.UTC),
+			},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := parseFeed(strings.NewReader(tt.feed))
+			if err != nil {
+				t.Fatalf("parseFeed: %v", err)
+			}
+			if len(items) != len(tt.want) {
+				t.Fatalf("len(items) = %d, want %d: %+v", len(items), len(tt.want), items)
+			}
+			for i, want := range tt.want {
+				got := items[i]
+				if got.Title != want.Title || got.Link != want.Link || got.Summary != want.Summary || got.Source != want.Source {
+					t.Errorf("items[%d] = %+v, want %+v", i, got, want)
+				}
+				if !got.Published.Equal(want.Published) {
+					t.Errorf("items[%d].Published = %v, want %v", i, got.Published, want.Published)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFeedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		feed string
+	}{
+		{"not a feed", `<html><body>not a feed</body></html>`},
+		{"broken xml", `<rss><channel><item>`},
+		{"unsupported charset", `<?xml version="1.0" encoding="Shift_JIS"?><rss></rss>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if items, err := parseFeed(strings.NewReader(tt.feed)); err == nil {
+				t.Errorf("parseFeed = %+v, want an error", items)
+			}
+		})
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"生成AIの規制", []string{"生成", "ai", "規制"}},
+		{"AIニュース", []string{"ai"}},
+		{"日本 株", []string{"日本", "株"}},
+		{"OpenAI o3 a", []string{"openai", "o3"}},
+		{"今日のニュース", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := queryTerms(tt.query); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("queryTerms(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectFeedItems(t *testing.T) {
+	now := time.Date(2024, 10, 14, 12, 0, 0, 0, time.UTC)
+	items := []feedItem{
+		{Title: "天気予報", Link: "https://example.com/weather", Published: now.Add(-1 * time.Hour)},
+		{Title: "新しいスマートフォン", Summary: "AI 機能を搭載", Link: "https://example.com/phone", Published: now.Add(-2 * time.Hour)},
+		{Title: "AI の規制案", Link: "https://example.com/regulation", Published: now.Add(-3 * time.Hour)},
+		{Title: "AI の規制案（再掲）", Link: "https://example.com/regulation", Published: now.Add(-3 * time.Hour)},
+		{Title: "古い AI の規制", Link: "https://example.com/old", Published: now.AddDate(0, 0, -10)},
+		{Title: "日付のない AI 記事", Link: "https://example.com/undated"},
+	}
+	titles := func(items []feedItem) string {
+		var titles []string
+		for _, item := range items {
+			titles = append(titles, item.Title)
+		}
+		return strings.Join(titles, ", ")
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  string
+	}{
+		// タイトルの一致は本文の一致より高く、同点は新しい順（日付のない記事は最後）
+		{"ranked by relevance", "AI 規制", 10, "AI の規制案, 日付のない AI 記事, 新しいスマートフォン"},
+		{"limit", "AI 規制", 1, "AI の規制案"},
+		// 関連する記事がなければ新しい順に選ぶ
+		{"vague query", "今日のニュース", 2, "天気予報, 新しいスマートフォン"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := titles(selectFeedItems(items, tt.query, now, 7, tt.limit)); got != tt.want {
+				t.Errorf("selectFeedItems = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rss":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			fmt.Fprint(w, rss2Feed)
+		case "/html":
+			fmt.Fprint(w, "<html></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	newSearcher := func(paths ...string) *FeedSearcher {
+		cfg := config.Default()
+		cfg.MaxRetries = 0
+		cfg.Feeds = nil
+		for _, path := range paths {
+			cfg.Feeds = append(cfg.Feeds, server.URL+path)
+		}
+		return NewFeedSearcher(cfg)
+	}
+
+	// 一部のフィードが失敗しても、取得できた記事を返す
+	items, err := newSearcher("/rss", "/missing").fetchAll(context.Background())
+	if err != nil {
+		t.Fatalf("fetchAll: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("len(items) = %d, want 2", len(items))
+	}
+
+	// すべて失敗した場合は最初のエラーを返す
+	_, err = newSearcher("/missing", "/html").fetchAll(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("fetchAll error = %v, want the 404 error", err)
+	}
+
+	_, err = newSearcher("/html").fetchAll(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "<html>") {
+		t.Errorf("fetchAll error = %v, want the parse error", err)
+	}
+}
@@ -1,56 +1,99 @@
 package client
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
 	"news_reporter/config"
+	"news_reporter/i18n"
 	"news_reporter/models"
+	"news_reporter/prompt"
+	"news_reporter/transport"
+	"news_reporter/usage"
 )
 
 type OpenAIClient struct {
 	config     *config.Config
 	httpClient *http.Client
+	budget     *usage.Budget // 使用額の上限（設定されていなければ nil）
 }
 
 // NewOpenAIClient 新しいOpenAIクライアントを作成
 func NewOpenAIClient(cfg *config.Config) *OpenAIClient {
 	return &OpenAIClient{
 		config: cfg,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		// 429・5xx は待ってから再送する（タイムアウトは再送を含めた全体の時間）
+		httpClient: transport.NewHTTPClient(cfg, cfg.SearchTimeout),
+	}
+}
+
+// SetBudget 検索の前に確認する使用額の上限を設定（nil の場合は確認しない）
+func (c *OpenAIClient) SetBudget(budget *usage.Budget) {
+	c.budget = budget
+}
+
+// model 使用額の上限に応じて使うモデルを選ぶ
+// 上限に達していれば BudgetError を返し、上限に近づいていれば安価なモデルに切り替える
+func (c *OpenAIClient) model() (string, error) {
+	status, err := c.budget.Check()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("budget.check_failed"), err)
+	}
+	if err := c.budget.Err(status); err != nil {
+		return "", err
 	}
+	fallback := c.budget.FallbackModel()
+	if status.Level == usage.BudgetApproaching && fallback != "" && fallback != c.config.Model {
+		fmt.Fprintln(os.Stderr, i18n.T("budget.fallback", status.Ratio()*100, fallback))
+		return fallback, nil
+	}
+	return c.config.Model, nil
 }
 
 // Search Web検索を実行
-func (c *OpenAIClient) Search(query string) (*models.SearchResult, error) {
-	// 現在の日付を取得
-	currentDate := time.Now().Format("2006年1月2日")
+func (c *OpenAIClient) Search(ctx context.Context, query string) (*models.SearchResult, error) {
+	return c.SearchStream(ctx, query, nil)
+}
 
-	// システムメッセージで最新情報検索を指示
-	systemMessage := fmt.Sprintf(`あなたは最新のニュースと情報を検索するアシスタントです。
-現在の日付: %s
+// SearchStream Web検索を実行し、受信したイベントを逐次コールバックに渡す
+// 戻り値の検索結果はストリーム完了後に組み立てられたもの
+// ctx がキャンセルされた場合は受信済みの内容を Partial を付けた結果としてエラーと一緒に返す
+func (c *OpenAIClient) SearchStream(ctx context.Context, query string, onEvent models.StreamCallback) (*models.SearchResult, error) {
+	model, err := c.model()
+	if err != nil {
+		return nil, err
+	}
+	return c.searchStream(ctx, query, model, onEvent)
+}
 
-以下の指示に従ってください：
-1. 必ずweb_search_previewツールを使用して、最新の情報を検索してください
-2. 検索結果から、今日（%s）またはできるだけ最近の情報を優先してください
-3. 古い情報（1週間以上前）は避け、最新のニュースに焦点を当ててください
-4. 検索結果を日本語で要約し、情報源のURLも含めてください
-5. 情報の日付が明確でない場合は、その旨を明記してください`, currentDate, currentDate)
+// searchStream 選んだモデルでWeb検索を実行する（使用額の上限は確認済み）
+func (c *OpenAIClient) searchStream(ctx context.Context, query, model string, onEvent models.StreamCallback) (*models.SearchResult, error) {
+
+	// 現在の日付などをテンプレートに埋め込んでシステムメッセージを作成
+	vars := c.PromptVars(query)
+	systemMessage, err := prompt.System(c.config.SystemPrompt, vars)
+	if err != nil {
+		return nil, fmt.Errorf("システムプロンプト: %w", err)
+	}
 
 	// ユーザークエリを現在の日付と組み合わせて強化
-	enhancedQuery := fmt.Sprintf("【%s時点】%s（最新情報・今日のニュース）", currentDate, query)
+	enhancedQuery, err := prompt.Query(c.config.QueryTemplate, vars)
+	if err != nil {
+		return nil, fmt.Errorf("クエリテンプレート: %w", err)
+	}
 
 	// リクエストボディを構築
+	temperature := c.config.Temperature
 	request := models.ResponseRequest{
-		Model: "gpt-4o-mini",
+		Model: model,
 		Input: []models.InputItem{
 			{
 				Type:    "message",
@@ -70,9 +113,52 @@ func (c *OpenAIClient) Search(query string) (*models.SearchResult, error) {
 		},
 		ToolChoice:  "required",
 		Stream:      true,
-		Temperature: 0.3, // より一貫性のある結果のために温度を下げる
+		Temperature: &temperature,
+	}
+
+	return c.sendResponseRequest(ctx, request, query, onEvent)
+}
+
+// PromptVars 設定と現在時刻からプロンプトテンプレートの変数を組み立てる
+func (c *OpenAIClient) PromptVars(query string) prompt.Vars {
+	return promptVars(c.config, query)
+}
+
+// Generate Web検索を使わずに指示と入力からテキストを生成
+func (c *OpenAIClient) Generate(ctx context.Context, instructions, input string) (*models.SearchResult, error) {
+	model, err := c.model()
+	if err != nil {
+		return nil, err
+	}
+	return c.generate(ctx, model, instructions, input)
+}
+
+// generate 選んだモデルでテキストを生成する（使用額の上限は確認済み）
+func (c *OpenAIClient) generate(ctx context.Context, model, instructions, input string) (*models.SearchResult, error) {
+	temperature := 0.7 // 原稿に自然な言い回しを持たせるため検索時より高めにする
+	request := models.ResponseRequest{
+		Model: model,
+		Input: []models.InputItem{
+			{
+				Type:    "message",
+				Role:    "system",
+				Content: instructions,
+			},
+			{
+				Type:    "message",
+				Role:    "user",
+				Content: input,
+			},
+		},
+		Stream:      true,
+		Temperature: &temperature,
 	}
 
+	return c.sendResponseRequest(ctx, request, "", nil)
+}
+
+// sendResponseRequest Responses APIにリクエストを送信し、ストリーミングレスポンスを処理
+func (c *OpenAIClient) sendResponseRequest(ctx context.Context, request models.ResponseRequest, query string, onEvent models.StreamCallback) (*models.SearchResult, error) {
 	// JSONエンコード
 	requestBody, err := json.Marshal(request)
 	if err != nil {
@@ -80,7 +166,7 @@ func (c *OpenAIClient) Search(query string) (*models.SearchResult, error) {
 	}
 
 	// HTTPリクエストを作成
-	req, err := http.NewRequest("POST", c.config.BaseURL+"/responses", bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.config.BaseURL+"/responses", bytes.NewBuffer(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -93,23 +179,56 @@ func (c *OpenAIClient) Search(query string) (*models.SearchResult, error) {
 	// リクエストを送信
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		// 再送しても成功しなかった場合は型付きのエラーをそのまま返す（errors.As で APIError を取り出せる）
+		var retryErr *transport.RetryError
+		if errors.As(err, &retryErr) {
+			return nil, retryErr
+		}
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// ステータスコードをチェック
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, transport.NewAPIError(resp)
+	}
+
+	// ストリーミングレスポンスを処理（中断された場合は途中までの結果も返る）
+	result, err := c.processStreamResponse(ctx, resp.Body, query, onEvent)
+	if result != nil {
+		if result.Model == "" {
+			result.Model = request.Model
+		}
+		result.Cost = usage.Estimate(c.config.Pricing, result.Model, result.Usage)
 	}
+	return result, err
+}
 
-	// ストリーミングレスポンスを処理
-	return c.processStreamResponse(resp.Body, query)
+// StreamError ストリーム中にAPIから通知されたエラー
+type StreamError struct {
+	Event   string
+	Code    string
+	Message string
+}
+
+func (e *StreamError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("stream %s: %s (%s)", e.Event, e.Message, e.Code)
+	}
+	return fmt.Sprintf("stream %s: %s", e.Event, e.Message)
 }
 
 // processStreamResponse ストリーミングレスポンスを処理
-func (c *OpenAIClient) processStreamResponse(body io.Reader, query string) (*models.SearchResult, error) {
-	scanner := bufio.NewScanner(body)
+// ctx のキャンセルで受信が途切れた場合は、受信済みの要約と引用元を Partial を付けて返す
+func (c *OpenAIClient) processStreamResponse(ctx context.Context, body io.Reader, query string, onEvent models.StreamCallback) (*models.SearchResult, error) {
+	emit := func(event models.StreamEvent) {
+		if onEvent != nil {
+			event.Timestamp = time.Now()
+			onEvent(event)
+		}
+	}
+
+	decoder := newSSEDecoder(body)
 	result := &models.SearchResult{
 		Query:     query,
 		Results:   make([]models.WebSearchResult, 0),
@@ -117,78 +236,153 @@ func (c *OpenAIClient) processStreamResponse(body io.Reader, query string) (*mod
 	}
 
 	var responseContent strings.Builder
+	completed := false
+	webSearchCalls := 0
+
+	for !completed {
+		sse, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				result.Summary = responseContent.String()
+				result.Partial = true
+				return result, fmt.Errorf("stream interrupted: %w", ctx.Err())
+			}
+			return nil, fmt.Errorf("error reading stream: %w", err)
+		}
 
-	for scanner.Scan() {
-		line := scanner.Text()
+		// [DONE]は終了シグナル
+		if sse.Data == "[DONE]" {
+			break
+		}
 
-		// Server-Sent Eventsの形式をパース
-		if strings.HasPrefix(line, "data: ") {
-			data := strings.TrimPrefix(line, "data: ")
+		// 共通部分をパースしてイベントタイプを判定
+		var base models.ResponseEvent
+		if err := json.Unmarshal([]byte(sse.Data), &base); err != nil {
+			return nil, fmt.Errorf("failed to parse stream event %q: %w", sse.Event, err)
+		}
+		eventType := base.Type
+		if eventType == "" {
+			eventType = sse.Event
+		}
 
-			// [DONE]は終了シグナル
-			if data == "[DONE]" {
-				break
+		switch {
+		case eventType == models.EventResponseCreated:
+			emit(models.StreamEvent{Type: models.StreamEventCreated})
+
+		case eventType == models.EventOutputTextDelta:
+			// テキストデルタを処理
+			var event models.OutputTextDeltaEvent
+			if err := decodeEvent(eventType, sse.Data, &event); err != nil {
+				return nil, err
+			}
+			responseContent.WriteString(event.Delta)
+			emit(models.StreamEvent{Type: models.StreamEventDelta, Delta: event.Delta})
+
+		case eventType == models.EventOutputTextAnnotation:
+			// アノテーションを処理（Web検索結果など）
+			var event models.OutputTextAnnotationEvent
+			if err := decodeEvent(eventType, sse.Data, &event); err != nil {
+				return nil, err
+			}
+			if citation := c.processAnnotation(event.Annotation, result); citation != nil {
+				emit(models.StreamEvent{Type: models.StreamEventCitation, Citation: citation})
+			}
+
+		case strings.HasPrefix(eventType, models.EventWebSearchCallPrefix):
+			var event models.WebSearchCallEvent
+			if err := decodeEvent(eventType, sse.Data, &event); err != nil {
+				return nil, err
 			}
+			status := strings.TrimPrefix(eventType, models.EventWebSearchCallPrefix)
+			if eventType == models.EventWebSearchCallCompleted {
+				webSearchCalls++
+			}
+			emit(models.StreamEvent{Type: models.StreamEventWebSearch, Status: status})
 
-			// JSONをパース
-			var event map[string]interface{}
-			if err := json.Unmarshal([]byte(data), &event); err != nil {
-				// パースエラーは無視して続行
-				continue
+		case eventType == models.EventResponseCompleted:
+			var event models.ResponseLifecycleEvent
+			if err := decodeEvent(eventType, sse.Data, &event); err != nil {
+				return nil, err
+			}
+			if event.Response.Model != "" {
+				result.Model = event.Response.Model
+			}
+			result.Usage = event.Response.Usage
+			completed = true
+
+		case eventType == models.EventResponseIncomplete:
+			// 上限到達などで途中終了した場合は受信済みの内容を返す
+			var event models.ResponseLifecycleEvent
+			if err := decodeEvent(eventType, sse.Data, &event); err != nil {
+				return nil, err
 			}
+			result.Usage = event.Response.Usage
+			if event.Response.IncompleteDetails != nil {
+				fmt.Fprintln(os.Stderr, i18n.T("search.incomplete", event.Response.IncompleteDetails.Reason))
+			}
+			completed = true
 
-			// イベントタイプに基づいて処理
-			eventType, ok := event["type"].(string)
-			if !ok {
-				continue
+		case eventType == models.EventResponseFailed:
+			var event models.ResponseLifecycleEvent
+			if err := decodeEvent(eventType, sse.Data, &event); err != nil {
+				return nil, err
+			}
+			streamErr := &StreamError{Event: eventType, Message: "response failed"}
+			if event.Response.Error != nil {
+				streamErr.Code = event.Response.Error.Code
+				streamErr.Message = event.Response.Error.Message
 			}
+			return nil, streamErr
 
-			switch eventType {
-			case "response.output_text.delta":
-				// テキストデルタを処理
-				if delta, ok := event["delta"].(string); ok {
-					responseContent.WriteString(delta)
-				}
-			case "response.output_text.annotation.added":
-				// アノテーションを処理（Web検索結果など）
-				if err := c.processAnnotation(event, result); err != nil {
-					fmt.Printf("Warning: failed to process annotation: %v\n", err)
-				}
+		case eventType == models.EventError:
+			var event models.ErrorEvent
+			if err := decodeEvent(eventType, sse.Data, &event); err != nil {
+				return nil, err
 			}
+			return nil, &StreamError{Event: eventType, Code: event.Code, Message: event.Message}
+
+		default:
+			// output_item.added など要約の組み立てに不要なイベントは読み飛ばす
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading stream: %w", err)
+	if !completed {
+		return nil, fmt.Errorf("stream ended before %s", models.EventResponseCompleted)
 	}
 
 	// 要約を設定
 	result.Summary = responseContent.String()
+	if result.Usage != nil {
+		result.Usage.WebSearchCalls = webSearchCalls
+	}
+	emit(models.StreamEvent{Type: models.StreamEventDone})
 
 	return result, nil
 }
 
-// processAnnotation アノテーションを処理してWeb検索結果を抽出
-func (c *OpenAIClient) processAnnotation(event map[string]interface{}, result *models.SearchResult) error {
-	annotation, ok := event["annotation"].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("invalid annotation data")
+// decodeEvent SSEイベントのデータを型付きの構造体にデコード
+func decodeEvent(eventType, data string, v interface{}) error {
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("failed to decode stream event %q: %w", eventType, err)
 	}
+	return nil
+}
 
+// processAnnotation アノテーションを処理してWeb検索結果を抽出
+// 新しく追加された検索結果を返し、対象外や重複の場合は nil を返す
+func (c *OpenAIClient) processAnnotation(annotation models.Annotation, result *models.SearchResult) *models.WebSearchResult {
 	// URL引用の場合のみ処理
-	annotationType, ok := annotation["type"].(string)
-	if !ok || annotationType != "url_citation" {
+	if annotation.Type != "url_citation" {
 		return nil
 	}
 
 	// Web検索結果を作成
-	searchResult := models.WebSearchResult{}
-
-	if title, ok := annotation["title"].(string); ok {
-		searchResult.Title = title
-	}
-	if url, ok := annotation["url"].(string); ok {
-		searchResult.URL = url
+	searchResult := models.WebSearchResult{
+		Title: annotation.Title,
+		URL:   annotation.URL,
 	}
 
 	// スニペットは含まれていない可能性があるため、タイトルを使用
@@ -204,5 +398,5 @@ func (c *OpenAIClient) processAnnotation(event map[string]interface{}, result *m
 	}
 
 	result.Results = append(result.Results, searchResult)
-	return nil
+	return &searchResult
 }
@@ -0,0 +1,219 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"news_reporter/config"
+	"news_reporter/models"
+)
+
+// openStream testdata のSSEを開く
+func openStream(t *testing.T, name string) io.Reader {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.NewReader(string(data))
+}
+
+func newTestClient() *OpenAIClient {
+	return NewOpenAIClient(config.Default())
+}
+
+func TestProcessStreamResponseCompleted(t *testing.T) {
+	var events []models.StreamEvent
+	result, err := newTestClient().processStreamResponse(context.Background(), openStream(t, "completed.sse"), "AIニュース", func(event models.StreamEvent) {
+		events = append(events, event)
+	})
+	if err != nil {
+		t.Fatalf("processStreamResponse: %v", err)
+	}
+
+	wantSummary := "今日のAI関連ニュースです。OpenAIが新しいモデルを発表しました。国内では生成AIの業務利用が広がっています。"
+	if result.Summary != wantSummary {
+		t.Errorf("Summary = %q, want %q", result.Summary, wantSummary)
+	}
+	if result.Query != "AIニュース" {
+		t.Errorf("Query = %q", result.Query)
+	}
+	if result.Model != "gpt-4o-mini-2024-07-18" {
+		t.Errorf("Model = %q", result.Model)
+	}
+	if result.Usage == nil || result.Usage.InputTokens != 312 || result.Usage.OutputTokens != 58 || result.Usage.TotalTokens != 370 {
+		t.Errorf("Usage = %+v", result.Usage)
+	}
+	if result.Partial {
+		t.Error("Partial = true for a completed stream")
+	}
+
+	// 重複した引用は1件にまとめる
+	wantURLs := []string{"https://example.com/openai-model", "https://example.jp/genai-business"}
+	if len(result.Results) != len(wantURLs) {
+		t.Fatalf("len(Results) = %d, want %d: %+v", len(result.Results), len(wantURLs), result.Results)
+	}
+	for i, url := range wantURLs {
+		if result.Results[i].URL != url {
+			t.Errorf("Results[%d].URL = %q, want %q", i, result.Results[i].URL, url)
+		}
+	}
+
+	counts := map[models.StreamEventType]int{}
+	var deltas strings.Builder
+	var statuses []string
+	for _, event := range events {
+		counts[event.Type]++
+		switch event.Type {
+		case models.StreamEventDelta:
+			deltas.WriteString(event.Delta)
+		case models.StreamEventWebSearch:
+			statuses = append(statuses, event.Status)
+		}
+	}
+	if deltas.String() != wantSummary {
+		t.Errorf("deltas = %q, want %q", deltas.String(), wantSummary)
+	}
+	if got := strings.Join(statuses, ","); got != "in_progress,searching,completed" {
+		t.Errorf("web search statuses = %s", got)
+	}
+	if counts[models.StreamEventCreated] != 1 || counts[models.StreamEventCitation] != 2 || counts[models.StreamEventDone] != 1 {
+		t.Errorf("event counts = %v", counts)
+	}
+	if events[0].Type != models.StreamEventCreated || events[len(events)-1].Type != models.StreamEventDone {
+		t.Errorf("first/last events = %s/%s", events[0].Type, events[len(events)-1].Type)
+	}
+}
+
+func TestProcessStreamResponseIncomplete(t *testing.T) {
+	result, err := newTestClient().processStreamResponse(context.Background(), openStream(t, "incomplete.sse"), "q", nil)
+	if err != nil {
+		t.Fatalf("processStreamResponse: %v", err)
+	}
+	if result.Summary != "今日のAI関連ニュースです。" {
+		t.Errorf("Summary = %q", result.Summary)
+	}
+	if result.Usage == nil || result.Usage.OutputTokens != 16 {
+		t.Errorf("Usage = %+v", result.Usage)
+	}
+}
+
+func TestProcessStreamResponseErrors(t *testing.T) {
+	tests := []struct {
+		file     string
+		wantCode string
+	}{
+		{"failed.sse", "server_error"},
+		{"error.sse", "rate_limit_exceeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			result, err := newTestClient().processStreamResponse(context.Background(), openStream(t, tt.file), "q", nil)
+			if result != nil {
+				t.Errorf("result = %+v, want nil", result)
+			}
+			var streamErr *StreamError
+			if !errors.As(err, &streamErr) {
+				t.Fatalf("err = %v, want *StreamError", err)
+			}
+			if streamErr.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", streamErr.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestProcessStreamResponseTruncated(t *testing.T) {
+	_, err := newTestClient().processStreamResponse(context.Background(), openStream(t, "truncated.sse"), "q", nil)
+	if err == nil || !strings.Contains(err.Error(), models.EventResponseCompleted) {
+		t.Fatalf("err = %v, want stream ended before %s", err, models.EventResponseCompleted)
+	}
+}
+
+func TestProcessStreamResponseInvalidJSON(t *testing.T) {
+	body := strings.NewReader("event: response.created\ndata: {not json}\n\n")
+	if _, err := newTestClient().processStreamResponse(context.Background(), body, "q", nil); err == nil {
+		t.Fatal("err = nil for invalid JSON")
+	}
+}
+
+func TestProcessStreamResponseCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader, writer := io.Pipe()
+
+	go func() {
+		data, _ := os.ReadFile(filepath.Join("testdata", "truncated.sse"))
+		writer.Write(data)
+		// 受信中に中断された場合は接続が切られて読み取りエラーになる
+		cancel()
+		writer.CloseWithError(context.Canceled)
+	}()
+
+	result, err := newTestClient().processStreamResponse(ctx, reader, "q", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if result == nil || !result.Partial || result.Summary != "今日の" {
+		t.Fatalf("result = %+v, want partial summary", result)
+	}
+}
+
+func TestProcessAnnotation(t *testing.T) {
+	client := newTestClient()
+	result := &models.SearchResult{}
+
+	tests := []struct {
+		name        string
+		annotation  models.Annotation
+		wantAdded   bool
+		wantSnippet string
+	}{
+		{
+			name:        "url citation",
+			annotation:  models.Annotation{Type: "url_citation", URL: "https://example.com/a", Title: "記事A"},
+			wantAdded:   true,
+			wantSnippet: "Web検索結果から引用",
+		},
+		{
+			name:       "duplicate url",
+			annotation: models.Annotation{Type: "url_citation", URL: "https://example.com/a", Title: "記事A（再掲）"},
+		},
+		{
+			name:       "other annotation type",
+			annotation: models.Annotation{Type: "file_citation", URL: "https://example.com/b"},
+		},
+		{
+			name:       "citation without title",
+			annotation: models.Annotation{Type: "url_citation", URL: "https://example.com/c"},
+			wantAdded:  true,
+		},
+	}
+
+	added := 0
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			citation := client.processAnnotation(tt.annotation, result)
+			if (citation != nil) != tt.wantAdded {
+				t.Fatalf("citation = %+v, wantAdded %v", citation, tt.wantAdded)
+			}
+			if citation == nil {
+				return
+			}
+			added++
+			if citation.URL != tt.annotation.URL || citation.Title != tt.annotation.Title {
+				t.Errorf("citation = %+v", citation)
+			}
+			if citation.Snippet != tt.wantSnippet {
+				t.Errorf("Snippet = %q, want %q", citation.Snippet, tt.wantSnippet)
+			}
+		})
+	}
+	if len(result.Results) != added {
+		t.Errorf("len(Results) = %d, want %d", len(result.Results), added)
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"news_reporter/config"
+	"news_reporter/models"
+	"news_reporter/prompt"
+	"news_reporter/usage"
+)
+
+// Searcher 検索バックエンド。クエリに関する最新情報を集めて要約する
+type Searcher interface {
+	// Search 検索して要約を返す
+	Search(ctx context.Context, query string) (*models.SearchResult, error)
+
+	// SearchStream 検索し、受信したイベントを逐次コールバックに渡す
+	// ctx がキャンセルされた場合は受信済みの内容を Partial を付けた結果としてエラーと一緒に返す
+	SearchStream(ctx context.Context, query string, onEvent models.StreamCallback) (*models.SearchResult, error)
+
+	// Generate 検索を行わずに指示と入力からテキストを生成する（ブリーフィング原稿など）
+	// 生成したテキストは Summary に入り、使用量・費用も検索と同じ形で返す
+	Generate(ctx context.Context, instructions, input string) (*models.SearchResult, error)
+
+	// PromptVars 設定と現在時刻からプロンプトテンプレートの変数を組み立てる
+	PromptVars(query string) prompt.Vars
+}
+
+// NewSearcher 設定された検索バックエンドを作成
+// budget は OpenAI の検索の前に確認する使用額の上限（nil の場合は確認しない）
+func NewSearcher(cfg *config.Config, budget *usage.Budget) Searcher {
+	switch cfg.Backend {
+	case config.BackendFeed:
+		return NewFeedSearcher(cfg)
+	default:
+		openAI := NewOpenAIClient(cfg)
+		openAI.SetBudget(budget)
+		if cfg.SearchCacheEnabled() {
+			return NewCachedSearcher(cfg, openAI)
+		}
+		return openAI
+	}
+}
+
+// promptVars 設定と現在時刻からプロンプトテンプレートの変数を組み立てる（バックエンド共通）
+func promptVars(cfg *config.Config, query string) prompt.Vars {
+	vars := prompt.NewVars(query, time.Now(), cfg.Location(), cfg.Language)
+	vars.RecencyDays = cfg.RecencyDays
+	vars.OutputLength = cfg.OutputLength
+	vars.Audience = cfg.Audience
+	vars.Sources = cfg.Sources
+	return vars
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// maxSSELineSize 1行の最大サイズ（response.completed は本文全体を含むため大きめに確保）
+const maxSSELineSize = 4 * 1024 * 1024
+
+// sseEvent Server-Sent Eventsの1イベント
+type sseEvent struct {
+	Event string
+	Data  string
+	ID    string
+}
+
+// sseDecoder Server-Sent Eventsのストリームをイベント単位に分割する
+type sseDecoder struct {
+	scanner *bufio.Scanner
+}
+
+// newSSEDecoder 新しいSSEデコーダーを作成
+func newSSEDecoder(r io.Reader) *sseDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+	return &sseDecoder{scanner: scanner}
+}
+
+// Next 次のイベントを返す。ストリーム終端では io.EOF を返す
+func (d *sseDecoder) Next() (*sseEvent, error) {
+	var event sseEvent
+	var data []string
+	hasData := false
+
+	for d.scanner.Scan() {
+		line := strings.TrimSuffix(d.scanner.Text(), "\r")
+
+		// 空行でイベントを確定
+		if line == "" {
+			if !hasData {
+				// データを持たないイベントは破棄
+				event = sseEvent{}
+				continue
+			}
+			event.Data = strings.Join(data, "\n")
+			return &event, nil
+		}
+
+		// コロンで始まる行はコメント
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, found := strings.Cut(line, ":")
+		if found {
+			value = strings.TrimPrefix(value, " ")
+		}
+
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+			hasData = true
+		case "id":
+			event.ID = value
+		default:
+			// retry などその他のフィールドは使用しない
+		}
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// 末尾に空行がないまま終了した場合も最後のイベントを返す
+	if hasData {
+		event.Data = strings.Join(data, "\n")
+		return &event, nil
+	}
+
+	return nil, io.EOF
+}
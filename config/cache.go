@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"news_reporter/i18n"
+)
+
+const (
+	DefaultCacheTTL       = 30 * time.Minute   // ニュースは数十分で古くなるため短めにする
+	DefaultSpeechCacheTTL = 7 * 24 * time.Hour // 同じ文章からは同じ音声ができるため長めに再利用する
+)
+
+// キャッシュの使い方（コマンドラインの --no-cache・--refresh で切り替える）
+const (
+	CacheModeDefault = ""        // 有効期限内のキャッシュを使い、新しい結果を保存する
+	CacheModeOff     = "off"     // キャッシュを読み書きしない（--no-cache）
+	CacheModeRefresh = "refresh" // キャッシュを読まずに取得し直し、結果で上書きする（--refresh）
+)
+
+// CacheFile 設定ファイルの cache セクション
+// ttl・speech_ttl に 0 を指定するとそれぞれのキャッシュを無効にする
+//
+//	cache:
+//	  dir: ~/.news_reporter/cache
+//	  ttl: 30m
+//	  speech_ttl: 168h
+type CacheFile struct {
+	Dir       string    `yaml:"dir" toml:"dir"`
+	TTL       *Duration `yaml:"ttl" toml:"ttl"`
+	SpeechTTL *Duration `yaml:"speech_ttl" toml:"speech_ttl"`
+}
+
+// applyCache 設定ファイルの cache セクションを反映（dir は設定ファイルからの相対パスでも指定できる）
+func (c *Config) applyCache(file CacheFile, baseDir string) {
+	if file.Dir != "" {
+		c.CacheDir = file.Dir
+		if !filepath.IsAbs(c.CacheDir) {
+			c.CacheDir = filepath.Join(baseDir, c.CacheDir)
+		}
+	}
+	if file.TTL != nil {
+		c.CacheTTL = time.Duration(*file.TTL)
+	}
+	if file.SpeechTTL != nil {
+		c.SpeechCacheTTL = time.Duration(*file.SpeechTTL)
+	}
+}
+
+// validateCache キャッシュの設定を検証
+func (c *Config) validateCache() error {
+	if c.CacheTTL < 0 || c.SpeechCacheTTL < 0 {
+		return errors.New(i18n.T("config.cache_ttl"))
+	}
+	switch c.CacheMode {
+	case CacheModeDefault, CacheModeOff, CacheModeRefresh:
+	default:
+		return errors.New(i18n.T("config.cache_mode", c.CacheMode))
+	}
+	if c.CacheDir == "" && (c.SearchCacheEnabled() || c.SpeechCacheEnabled()) {
+		return errors.New(i18n.T("config.cache_dir"))
+	}
+	return nil
+}
+
+// SearchCacheEnabled 検索結果をキャッシュするか
+func (c *Config) SearchCacheEnabled() bool {
+	return c.CacheMode != CacheModeOff && c.CacheTTL > 0
+}
+
+// SpeechCacheEnabled 合成した音声をキャッシュするか
+func (c *Config) SpeechCacheEnabled() bool {
+	return c.CacheMode != CacheModeOff && c.SpeechCacheTTL > 0
+}
+
+// CacheDir 検索結果・音声のキャッシュを保存するディレクトリを返す
+func CacheDir() string {
+	if path := os.Getenv("NEWS_REPORTER_CACHE"); path != "" {
+		return path
+	}
+	return filepath.Join(DataDir(), "cache")
+}
@@ -1,34 +1,565 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"news_reporter/i18n"
+	"news_reporter/prompt"
+)
+
+// 設定の既定値
+const (
+	DefaultBaseURL        = "https://api.openai.com/v1"
+	DefaultModel          = "gpt-4o-mini"
+	DefaultTemperature    = 0.3 // より一貫性のある結果のために温度を下げる
+	DefaultSearchTimeout  = 60 * time.Second
+	DefaultTTSModel       = "tts-1" // 高速モデル（tts-1-hdもあります）
+	DefaultVoice          = "alloy" // 利用可能な声: alloy, echo, fable, onyx, nova, shimmer
+	DefaultSpeed          = 1.0
+	DefaultResponseFormat = "mp3"
+	DefaultTTSTimeout     = 120 * time.Second // 音声生成は時間がかかる場合があるため長めに設定
+	DefaultLanguage       = "ja"
+	DefaultRecencyDays    = 7
+	DefaultMaxRetries     = 3                           // 429・5xx・接続エラー時に再送する回数
+	DefaultRetryMaxWait   = 60 * time.Second            // サーバーが指定した待ち時間をこれ以上は待たない
+	DefaultFeedBaseURL    = "http://localhost:11434/v1" // Ollama の Chat Completions 互換エンドポイント
+	DefaultFeedModel      = "llama3.1"
+	DefaultFeedMaxItems   = 10
+	DefaultVoicevoxURL    = "http://localhost:50021"
+	DefaultVoicevoxVoice  = "3" // VOICEVOX の話者ID（ずんだもん ノーマル）
+	DefaultCommandFormat  = "wav"
+)
+
+// 検索バックエンド
+const (
+	BackendOpenAI = "openai" // Responses API の web_search_preview で検索
+	BackendFeed   = "feed"   // RSS/Atom フィードの記事を Chat Completions 互換APIで要約
+)
+
+// フィクスチャ（APIとのやり取りの記録）の扱い
+const (
+	FixtureRecord = "record" // 実際のAPIに送信し、やり取りを FixtureDir に保存する
+	FixtureReplay = "replay" // ネットワークに接続せず、FixtureDir の記録を返す
 )
 
+// Backends 対応している検索バックエンド
+var Backends = []string{BackendOpenAI, BackendFeed}
+
+// 音声合成バックエンド
+const (
+	TTSBackendOpenAI   = "openai"   // OpenAI の /audio/speech
+	TTSBackendCommand  = "command"  // Open JTalk・espeak-ng などのローカルのコマンド
+	TTSBackendVoicevox = "voicevox" // VOICEVOX 互換のHTTP API（AivisSpeech なども可）
+)
+
+// TTSBackends 対応している音声合成バックエンド
+var TTSBackends = []string{TTSBackendOpenAI, TTSBackendCommand, TTSBackendVoicevox}
+
+// DefaultVoices 声を指定しなかった場合の言語ごとの声（ない言語は DefaultVoice）
+var DefaultVoices = map[string]string{
+	"ja": "alloy",
+	"en": "nova",
+	"zh": "shimmer",
+	"ko": "nova",
+}
+
+// ErrMissingAPIKey APIキーがどこにも設定されていない
+var ErrMissingAPIKey = errors.New("OPENAI_API_KEY environment variable is required")
+
+// ResponseFormats 音声合成APIが対応する出力形式
+var ResponseFormats = []string{"mp3", "opus", "aac", "flac", "wav", "pcm"}
+
 type Config struct {
 	OpenAIAPIKey string
 	BaseURL      string
+	HistoryPath  string
+	UsagePath    string // 使用量・費用の記録ファイル
+	ConfigFile   string // 読み込んだ設定ファイル（なければ空）
+	Profile      string // 適用したプロファイル名（なければ空）
+
+	// フィクスチャ（検索・音声合成で共通。空の場合は実際のAPIに送信する）
+	FixtureMode string // record または replay
+	FixtureDir  string // フィクスチャを保存・読み込むディレクトリ
+
+	// 再送（検索・音声合成で共通）
+	MaxRetries   int           // 一時的な失敗を再送する最大回数（0 の場合は再送しない）
+	RetryMaxWait time.Duration // Retry-After などで指定された待ち時間の上限
+
+	// 検索・要約
+	Backend       string // 検索バックエンド（openai または feed）
+	Model         string
+	Temperature   float64
+	SearchTimeout time.Duration
+	SystemPrompt  string   // 検索用のシステムプロンプトのテンプレート（空の場合は組み込みのもの）
+	QueryTemplate string   // 検索クエリを強化するテンプレート（空の場合は組み込みのもの）
+	Language      string   // 要約・メッセージ・読み上げの言語コード
+	Sources       []string // 優先して参照する情報源
+	Format        string   // 既定の出力形式（空の場合は各コマンドの既定値）
+
+	// フィード検索（Backend が feed の場合）
+	Feeds        []string // 取得する RSS/Atom フィードのURL
+	FeedBaseURL  string   // 要約に使う Chat Completions 互換APIのベースURL
+	FeedModel    string
+	FeedAPIKey   string // 空の場合は Authorization ヘッダーを送らない
+	FeedMaxItems int    // 要約に渡す記事の最大数
+
+	// プロンプトの変数
+	Timezone     string // 日付の基準にするタイムゾーン（空の場合はローカル）
+	RecencyDays  int    // 最新とみなす期間（日数）
+	OutputLength string // 要約の長さの目安
+	Audience     string // 想定読者
+
+	// 音声合成
+	TTSBackend     string // 音声合成バックエンド（openai, command, voicevox）
+	TTSModel       string
+	Voice          string // OpenAI の声の名前、または VOICEVOX の話者ID
+	Speed          float64
+	ResponseFormat string
+	TTSTimeout     time.Duration
+	TTSCommand     string // command バックエンドで実行するコマンドのテンプレート
+	CommandFormat  string // TTSCommand が出力する音声形式
+	VoicevoxURL    string // voicevox バックエンドのベースURL
+
+	// キャッシュ（検索結果は CacheTTL、合成した音声は SpeechCacheTTL の間だけ再利用する）
+	CacheDir       string
+	CacheTTL       time.Duration
+	SpeechCacheTTL time.Duration
+	CacheMode      string // CacheModeDefault, CacheModeOff（--no-cache）, CacheModeRefresh（--refresh）
+
+	// 費用の見積もりと上限
+	Pricing Pricing
+	Budget  Budget // 全体の上限と、適用したプロファイルの上限
+}
+
+// Flags コマンドラインで指定された設定。空文字列は未指定として扱う
+type Flags struct {
+	ConfigFile     string
+	Profile        string
+	Language       string
+	Backend        string
+	TTSBackend     string
+	Model          string
+	Temperature    string
+	SearchTimeout  string
+	TTSModel       string
+	Voice          string
+	Speed          string
+	ResponseFormat string
+	TTSTimeout     string
+	NoCache        bool // --no-cache
+	Refresh        bool // --refresh
+}
+
+// setting 文字列で与えられた1つの設定値（name はエラー表示用の指定元）
+type setting struct {
+	key   string
+	name  string
+	value string
 }
 
-// LoadConfig 環境変数から設定を読み込む
-func LoadConfig() (*Config, error) {
+// envSettings 環境変数名と設定項目の対応
+var envSettings = []struct{ env, key string }{
+	{"OPENAI_API_KEY", "api_key"},
+	{"OPENAI_BASE_URL", "base_url"},
+	{"NEWS_REPORTER_MODEL", "model"},
+	{"NEWS_REPORTER_TEMPERATURE", "temperature"},
+	{"NEWS_REPORTER_TIMEOUT", "timeout"},
+	{"NEWS_REPORTER_TTS_MODEL", "tts_model"},
+	{"NEWS_REPORTER_VOICE", "voice"},
+	{"NEWS_REPORTER_SPEED", "speed"},
+	{"NEWS_REPORTER_RESPONSE_FORMAT", "response_format"},
+	{"NEWS_REPORTER_TTS_TIMEOUT", "tts_timeout"},
+	{"NEWS_REPORTER_TTS_BACKEND", "tts_backend"},
+	{"NEWS_REPORTER_FIXTURE_MODE", "fixture_mode"},
+	{"NEWS_REPORTER_FIXTURE_DIR", "fixture_dir"},
+	{"NEWS_REPORTER_TIMEZONE", "timezone"},
+	{"NEWS_REPORTER_LANG", "language"},
+	{"NEWS_REPORTER_BACKEND", "backend"},
+	{"NEWS_REPORTER_FEED_API_KEY", "feed_api_key"},
+	{"NEWS_REPORTER_MAX_RETRIES", "max_retries"},
+}
+
+// Default 既定値だけを設定した Config を返す
+// 声は言語によって既定値が変わるため、空のままにして LoadConfig で決める
+func Default() *Config {
+	return &Config{
+		BaseURL:        DefaultBaseURL,
+		HistoryPath:    HistoryPath(),
+		UsagePath:      UsagePath(),
+		FixtureDir:     filepath.Join(DataDir(), "fixtures"),
+		Backend:        BackendOpenAI,
+		FeedBaseURL:    DefaultFeedBaseURL,
+		FeedModel:      DefaultFeedModel,
+		FeedMaxItems:   DefaultFeedMaxItems,
+		MaxRetries:     DefaultMaxRetries,
+		RetryMaxWait:   DefaultRetryMaxWait,
+		Model:          DefaultModel,
+		Temperature:    DefaultTemperature,
+		SearchTimeout:  DefaultSearchTimeout,
+		TTSBackend:     TTSBackendOpenAI,
+		TTSModel:       DefaultTTSModel,
+		CommandFormat:  DefaultCommandFormat,
+		VoicevoxURL:    DefaultVoicevoxURL,
+		Speed:          DefaultSpeed,
+		ResponseFormat: DefaultResponseFormat,
+		TTSTimeout:     DefaultTTSTimeout,
+		Language:       DefaultLanguage,
+		RecencyDays:    DefaultRecencyDays,
+		CacheDir:       CacheDir(),
+		CacheTTL:       DefaultCacheTTL,
+		SpeechCacheTTL: DefaultSpeechCacheTTL,
+		Pricing:        DefaultPricing(),
+		Budget:         Budget{WarnAt: DefaultBudgetWarnAt},
+	}
+}
+
+// LoadConfig 設定を読み込む
+// 優先順位は 既定値 < 設定ファイル < プロファイル < 環境変数 < コマンドライン の順
+func LoadConfig(flags Flags) (*Config, error) {
 	// .envファイルが存在する場合は読み込む（オプション）
 	_ = godotenv.Load()
 
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is required")
+	cfg := Default()
+
+	path, err := configFilePath(flags.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	file := &File{}
+	if path != "" {
+		if file, err = loadFile(path); err != nil {
+			return nil, err
+		}
+		if err := cfg.applyFile(file, filepath.Dir(path)); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		cfg.ConfigFile = path
 	}
 
-	baseURL := os.Getenv("OPENAI_BASE_URL")
-	if baseURL == "" {
-		baseURL = "https://api.openai.com/v1"
+	// プロファイルは コマンドライン > 環境変数 > 設定ファイルの profile の順で選ぶ
+	profileName := flags.Profile
+	if profileName == "" {
+		profileName = os.Getenv("NEWS_REPORTER_PROFILE")
+	}
+	if profileName == "" {
+		profileName = file.Profile
+	}
+	if profileName != "" {
+		profile, ok := file.Profiles[profileName]
+		if !ok {
+			if len(file.Profiles) == 0 {
+				return nil, errors.New(i18n.T("config.no_profiles", profileName))
+			}
+			return nil, errors.New(i18n.T("config.profile_not_found", profileName, strings.Join(file.ProfileNames(), ", ")))
+		}
+		if err := cfg.applyProfile(profile, filepath.Dir(path)); err != nil {
+			return nil, fmt.Errorf("%s: %w", i18n.T("config.profile", profileName), err)
+		}
+		cfg.Profile = profileName
 	}
 
-	return &Config{
-		OpenAIAPIKey: apiKey,
-		BaseURL:      baseURL,
-	}, nil
+	var env []setting
+	for _, e := range envSettings {
+		if value := os.Getenv(e.env); value != "" {
+			env = append(env, setting{key: e.key, name: e.env, value: value})
+		}
+	}
+	if err := cfg.apply(env); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.apply(flags.settings()); err != nil {
+		return nil, err
+	}
+	switch {
+	case flags.NoCache:
+		cfg.CacheMode = CacheModeOff
+	case flags.Refresh:
+		cfg.CacheMode = CacheModeRefresh
+	}
+
+	// フィード検索ではローカルのモデルで要約できるため、APIキーは音声合成で必要になるまで求めない
+	// フィクスチャを再生する場合やローカルのモックサーバーに接続する場合も不要
+	if cfg.OpenAIAPIKey == "" && cfg.Backend == BackendOpenAI && cfg.FixtureMode != FixtureReplay && !cfg.LocalAPI() {
+		return nil, ErrMissingAPIKey
+	}
+
+	// 声が指定されていなければ言語に合わせて選ぶ
+	if cfg.Voice == "" {
+		cfg.Voice = voiceFor(cfg.TTSBackend, cfg.Language, file.TTS.Voices)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate 設定値の範囲を検証
+func (c *Config) Validate() error {
+	switch c.Backend {
+	case BackendOpenAI:
+	case BackendFeed:
+		if len(c.Feeds) == 0 {
+			return errors.New(i18n.T("config.feed_urls"))
+		}
+		if c.FeedMaxItems < 1 {
+			return errors.New(i18n.T("config.feed_max_items", c.FeedMaxItems))
+		}
+	default:
+		return errors.New(i18n.T("config.backend", c.Backend, strings.Join(Backends, ", ")))
+	}
+	if c.Temperature < 0 || c.Temperature > 2 {
+		return errors.New(i18n.T("config.temperature", c.Temperature))
+	}
+	if c.Speed < 0.25 || c.Speed > 4 {
+		return errors.New(i18n.T("config.speed", c.Speed))
+	}
+	if !validResponseFormat(c.ResponseFormat) {
+		return errors.New(i18n.T("config.audio_format", c.ResponseFormat, strings.Join(ResponseFormats, ", ")))
+	}
+	switch c.TTSBackend {
+	case TTSBackendOpenAI:
+	case TTSBackendCommand:
+		if strings.TrimSpace(c.TTSCommand) == "" {
+			return errors.New(i18n.T("config.tts_command"))
+		}
+		if !validResponseFormat(c.CommandFormat) {
+			return errors.New(i18n.T("config.audio_format", c.CommandFormat, strings.Join(ResponseFormats, ", ")))
+		}
+	case TTSBackendVoicevox:
+		if c.VoicevoxURL == "" {
+			return errors.New(i18n.T("config.voicevox_url"))
+		}
+		if _, err := strconv.Atoi(c.Voice); err != nil {
+			return errors.New(i18n.T("config.voicevox_voice", c.Voice))
+		}
+	default:
+		return errors.New(i18n.T("config.tts_backend", c.TTSBackend, strings.Join(TTSBackends, ", ")))
+	}
+	if c.SearchTimeout <= 0 || c.TTSTimeout <= 0 {
+		return errors.New(i18n.T("config.timeout"))
+	}
+	switch c.FixtureMode {
+	case "", FixtureRecord, FixtureReplay:
+	default:
+		return errors.New(i18n.T("config.fixture_mode", c.FixtureMode, FixtureRecord, FixtureReplay))
+	}
+	if c.FixtureMode != "" && c.FixtureDir == "" {
+		return errors.New(i18n.T("config.fixture_dir"))
+	}
+	if c.MaxRetries < 0 {
+		return errors.New(i18n.T("config.max_retries", c.MaxRetries))
+	}
+	if c.RetryMaxWait <= 0 {
+		return errors.New(i18n.T("config.max_retry_wait"))
+	}
+	if err := c.Pricing.validate(); err != nil {
+		return err
+	}
+	if err := c.validateBudget(); err != nil {
+		return err
+	}
+	if err := c.validateCache(); err != nil {
+		return err
+	}
+	if c.Language == "" {
+		return errors.New(i18n.T("config.language", strings.Join(i18n.Languages(), ", ")))
+	}
+	if c.RecencyDays < 1 {
+		return errors.New(i18n.T("config.recency_days", c.RecencyDays))
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return errors.New(i18n.T("config.timezone", c.Timezone))
+	}
+	if _, err := prompt.Parse("system", c.SystemPrompt); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("config.system_prompt"), err)
+	}
+	if _, err := prompt.Parse("query", c.QueryTemplate); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("config.query_template"), err)
+	}
+	return nil
+}
+
+// LocalAPI BaseURL がローカルホスト（mockserver など）を指しているか
+func (c *Config) LocalAPI() bool {
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// AudioFormat 音声合成バックエンドが出力する音声形式（保存するファイルの拡張子にも使う）
+func (c *Config) AudioFormat() string {
+	switch c.TTSBackend {
+	case TTSBackendCommand:
+		return c.CommandFormat
+	case TTSBackendVoicevox:
+		return "wav"
+	default:
+		return c.ResponseFormat
+	}
+}
+
+// Location プロンプトの日付に使うタイムゾーン
+func (c *Config) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// settings 指定されたフラグを設定値の一覧に変換
+func (f Flags) settings() []setting {
+	candidates := []setting{
+		{"language", "--lang", f.Language},
+		{"backend", "--backend", f.Backend},
+		{"model", "--model", f.Model},
+		{"temperature", "--temperature", f.Temperature},
+		{"timeout", "--timeout", f.SearchTimeout},
+		{"tts_backend", "--tts-backend", f.TTSBackend},
+		{"tts_model", "--tts-model", f.TTSModel},
+		{"voice", "--voice", f.Voice},
+		{"speed", "--speed", f.Speed},
+		{"response_format", "--audio-format", f.ResponseFormat},
+		{"tts_timeout", "--tts-timeout", f.TTSTimeout},
+	}
+
+	var settings []setting
+	for _, s := range candidates {
+		if s.value != "" {
+			settings = append(settings, s)
+		}
+	}
+	return settings
+}
+
+// apply 文字列の設定値を解釈して反映
+func (c *Config) apply(settings []setting) error {
+	for _, s := range settings {
+		var err error
+		switch s.key {
+		case "api_key":
+			c.OpenAIAPIKey = s.value
+		case "base_url":
+			c.BaseURL = strings.TrimRight(s.value, "/")
+		case "model":
+			c.Model = s.value
+		case "temperature":
+			c.Temperature, err = strconv.ParseFloat(s.value, 64)
+		case "timeout":
+			c.SearchTimeout, err = time.ParseDuration(s.value)
+		case "tts_backend":
+			c.TTSBackend = strings.ToLower(s.value)
+		case "tts_model":
+			c.TTSModel = s.value
+		case "voice":
+			c.Voice = s.value
+		case "speed":
+			c.Speed, err = strconv.ParseFloat(s.value, 64)
+		case "response_format":
+			c.ResponseFormat = strings.ToLower(s.value)
+		case "tts_timeout":
+			c.TTSTimeout, err = time.ParseDuration(s.value)
+		case "max_retries":
+			c.MaxRetries, err = strconv.Atoi(s.value)
+		case "timezone":
+			c.Timezone = s.value
+		case "language":
+			c.Language = i18n.Normalize(s.value)
+		case "backend":
+			c.Backend = strings.ToLower(s.value)
+		case "fixture_mode":
+			c.FixtureMode = strings.ToLower(s.value)
+		case "fixture_dir":
+			c.FixtureDir = s.value
+		case "feed_api_key":
+			c.FeedAPIKey = s.value
+		}
+		if err != nil {
+			return errors.New(i18n.T("config.invalid_value", s.name, s.value))
+		}
+	}
+	return nil
+}
+
+// voiceFor 言語に対応する既定の声を返す（設定ファイルの tts.voices を優先）
+// tts.voices は OpenAI の声の名前のため、他のバックエンドではバックエンドの既定値を返す
+func voiceFor(backend, lang string, voices map[string]string) string {
+	switch backend {
+	case TTSBackendVoicevox:
+		return DefaultVoicevoxVoice
+	case TTSBackendCommand:
+		return "" // コマンドのテンプレートで指定する
+	}
+	if voice, ok := voices[lang]; ok && voice != "" {
+		return voice
+	}
+	if voice, ok := DefaultVoices[lang]; ok {
+		return voice
+	}
+	return DefaultVoice
+}
+
+// validResponseFormat 音声合成APIが対応する形式か判定
+func validResponseFormat(format string) bool {
+	for _, f := range ResponseFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// DataDir 履歴などのデータを保存するディレクトリを返す
+func DataDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".news_reporter"
+	}
+	return filepath.Join(home, ".news_reporter")
+}
+
+// HistoryPath 検索履歴ファイルのパスを返す
+// 履歴の参照にはAPIキーが不要なため、LoadConfig とは独立して呼び出せる
+func HistoryPath() string {
+	if path := os.Getenv("NEWS_REPORTER_HISTORY"); path != "" {
+		return path
+	}
+	return filepath.Join(DataDir(), "history.jsonl")
+}
+
+// UsagePath 使用量・費用の記録ファイルのパスを返す
+// 集計にはAPIキーが不要なため、LoadConfig とは独立して呼び出せる
+func UsagePath() string {
+	if path := os.Getenv("NEWS_REPORTER_USAGE"); path != "" {
+		return path
+	}
+	return filepath.Join(DataDir(), "usage.jsonl")
+}
+
+// DaemonStatePath デーモンの状態ファイルのパスを返す
+func DaemonStatePath() string {
+	if path := os.Getenv("NEWS_REPORTER_DAEMON_STATE"); path != "" {
+		return path
+	}
+	return filepath.Join(DataDir(), "daemon_state.json")
 }
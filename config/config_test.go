@@ -0,0 +1,123 @@
+package config
+
+import (
+	"testing"
+
+	"news_reporter/i18n"
+)
+
+const profilesConfig = `
+openai:
+  api_key: sk-file
+  model: gpt-4o
+  temperature: 0.5
+tts:
+  voice: nova
+  speed: 1.1
+budget:
+  daily: 2
+  monthly: 30
+  fallback_model: gpt-4o-mini
+profile: standard
+profiles:
+  standard:
+    voice: onyx
+  economy:
+    model: gpt-4o-mini
+    voice: shimmer
+    budget:
+      monthly: 5
+  cautious:
+    budget:
+      warn_at: 0.5
+`
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	// 全体の budget は、上限の一部だけを指定したプロファイルの budget と両方が残る
+	fileBudget := Budget{Daily: 2, Monthly: 30, WarnAt: DefaultBudgetWarnAt, FallbackModel: "gpt-4o-mini"}
+	economyBudget := fileBudget
+	economyBudget.ProfileMonthly = 5
+	cautiousBudget := fileBudget
+	cautiousBudget.WarnAt = 0.5
+
+	type want struct {
+		profile     string
+		model       string
+		temperature float64
+		voice       string
+		speed       float64
+		budget      Budget
+	}
+	tests := []struct {
+		name   string
+		noFile bool
+		env    map[string]string
+		flags  Flags
+		want   want
+	}{
+		{"defaults", true, map[string]string{"OPENAI_API_KEY": "sk-env"}, Flags{},
+			want{"", DefaultModel, DefaultTemperature, voiceFor(TTSBackendOpenAI, DefaultLanguage, nil), DefaultSpeed, Budget{WarnAt: DefaultBudgetWarnAt}}},
+		{"file and its default profile", false, nil, Flags{},
+			want{"standard", "gpt-4o", 0.5, "onyx", 1.1, fileBudget}},
+		{"profile from env", false, map[string]string{"NEWS_REPORTER_PROFILE": "economy"}, Flags{},
+			want{"economy", "gpt-4o-mini", 0.5, "shimmer", 1.1, economyBudget}},
+		{"profile flag over env", false, map[string]string{"NEWS_REPORTER_PROFILE": "economy"}, Flags{Profile: "cautious"},
+			want{"cautious", "gpt-4o", 0.5, "nova", 1.1, cautiousBudget}},
+		{"env over profile", false, map[string]string{"NEWS_REPORTER_MODEL": "gpt-4o", "NEWS_REPORTER_VOICE": "echo"}, Flags{Profile: "economy"},
+			want{"economy", "gpt-4o", 0.5, "echo", 1.1, economyBudget}},
+		{"flags over env", false, map[string]string{"NEWS_REPORTER_MODEL": "gpt-4o", "NEWS_REPORTER_TEMPERATURE": "0.2", "NEWS_REPORTER_SPEED": "0.9"},
+			Flags{Model: "gpt-4o-2024-08-06", Voice: "fable", Speed: "1.5"},
+			want{"standard", "gpt-4o-2024-08-06", 0.2, "fable", 1.5, fileBudget}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			flags := tt.flags
+			if !tt.noFile {
+				flags.ConfigFile = writeConfig(t, "config.yaml", profilesConfig)
+			}
+
+			cfg, err := LoadConfig(flags)
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			got := want{cfg.Profile, cfg.Model, cfg.Temperature, cfg.Voice, cfg.Speed, cfg.Budget}
+			if got != tt.want {
+				t.Errorf("LoadConfig = %+v\nwant          %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigProfileErrors(t *testing.T) {
+	isolateEnv(t)
+	path := writeConfig(t, "config.yaml", profilesConfig)
+	_, err := LoadConfig(Flags{ConfigFile: path, Profile: "premium"})
+	if want := i18n.T("config.profile_not_found", "premium", "cautious, economy, standard"); err == nil || err.Error() != want {
+		t.Errorf("unknown profile: error = %v, want %q", err, want)
+	}
+
+	// 設定ファイルがなければプロファイルは選べない
+	t.Setenv("OPENAI_API_KEY", "sk-env")
+	t.Setenv("NEWS_REPORTER_PROFILE", "economy")
+	_, err = LoadConfig(Flags{})
+	if want := i18n.T("config.no_profiles", "economy"); err == nil || err.Error() != want {
+		t.Errorf("no profiles: error = %v, want %q", err, want)
+	}
+
+	// 環境変数の不正な値は指定元の名前を示す
+	t.Setenv("NEWS_REPORTER_PROFILE", "")
+	t.Setenv("NEWS_REPORTER_TEMPERATURE", "warm")
+	_, err = LoadConfig(Flags{ConfigFile: path})
+	if want := i18n.T("config.invalid_value", "NEWS_REPORTER_TEMPERATURE", "warm"); err == nil || err.Error() != want {
+		t.Errorf("invalid env value: error = %v, want %q", err, want)
+	}
+	t.Setenv("NEWS_REPORTER_TEMPERATURE", "")
+	_, err = LoadConfig(Flags{ConfigFile: path, Speed: "fast"})
+	if want := i18n.T("config.invalid_value", "--speed", "fast"); err == nil || err.Error() != want {
+		t.Errorf("invalid flag value: error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,418 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"news_reporter/i18n"
+)
+
+// configFileNames 設定ファイルを自動で探す際のファイル名（先に見つかったものを使う）
+var configFileNames = []string{"config.yaml", "config.yml", "config.toml"}
+
+// File 設定ファイルの内容。省略した項目は既定値のまま
+//
+//	backend: openai
+//	openai:
+//	  model: gpt-4o-mini
+//	  temperature: 0.3
+//	  timeout: 60s
+//	  max_retries: 3
+//	tts:
+//	  backend: openai
+//	  model: tts-1
+//	  voice: alloy
+//	  speed: 1.0
+//	  response_format: mp3
+//	  timeout: 120s
+//	prompt:
+//	  system_file: prompts/system.tmpl
+//	  timezone: Asia/Tokyo
+//	profile: economy
+//	profiles:
+//	  economy:
+//	    model: gpt-4o
+//	    voice: onyx
+type File struct {
+	Language string              `yaml:"language" toml:"language"`
+	Backend  string              `yaml:"backend" toml:"backend"`
+	OpenAI   OpenAIFile          `yaml:"openai" toml:"openai"`
+	Feed     FeedFile            `yaml:"feed" toml:"feed"`
+	TTS      TTSFile             `yaml:"tts" toml:"tts"`
+	Prompt   PromptFile          `yaml:"prompt" toml:"prompt"`
+	Fixtures FixturesFile        `yaml:"fixtures" toml:"fixtures"`
+	Pricing  PricingFile         `yaml:"pricing" toml:"pricing"`
+	Budget   BudgetFile          `yaml:"budget" toml:"budget"`
+	Cache    CacheFile           `yaml:"cache" toml:"cache"`
+	Profile  string              `yaml:"profile" toml:"profile"`
+	Profiles map[string]*Profile `yaml:"profiles" toml:"profiles"`
+}
+
+// FeedFile 設定ファイルの feed セクション（backend: feed の場合に使う）
+type FeedFile struct {
+	URLs     []string `yaml:"urls" toml:"urls"`
+	BaseURL  string   `yaml:"base_url" toml:"base_url"`
+	Model    string   `yaml:"model" toml:"model"`
+	APIKey   string   `yaml:"api_key" toml:"api_key"`
+	MaxItems int      `yaml:"max_items" toml:"max_items"`
+}
+
+// FixturesFile 設定ファイルの fixtures セクション
+// dir は設定ファイルからの相対パスでも指定できる
+//
+//	fixtures:
+//	  mode: replay
+//	  dir: testdata/fixtures
+type FixturesFile struct {
+	Mode string `yaml:"mode" toml:"mode"`
+	Dir  string `yaml:"dir" toml:"dir"`
+}
+
+// PromptFile 設定ファイルの prompt セクション
+// テンプレートは text/template 形式で、ファイルのパスは設定ファイルからの相対パスでも指定できる
+type PromptFile struct {
+	System       string `yaml:"system" toml:"system"`
+	SystemFile   string `yaml:"system_file" toml:"system_file"`
+	Query        string `yaml:"query" toml:"query"`
+	QueryFile    string `yaml:"query_file" toml:"query_file"`
+	Timezone     string `yaml:"timezone" toml:"timezone"`
+	RecencyDays  int    `yaml:"recency_days" toml:"recency_days"`
+	OutputLength string `yaml:"output_length" toml:"output_length"`
+	Audience     string `yaml:"audience" toml:"audience"`
+}
+
+// Profile 用途ごとにまとめた設定。--profile で選択し、指定した項目だけを上書きする
+type Profile struct {
+	Backend           string   `yaml:"backend" toml:"backend"`
+	TTSBackend        string   `yaml:"tts_backend" toml:"tts_backend"`
+	Feeds             []string `yaml:"feeds" toml:"feeds"`
+	Model             string   `yaml:"model" toml:"model"`
+	SystemPrompt      string   `yaml:"system_prompt" toml:"system_prompt"`
+	SystemPromptFile  string   `yaml:"system_prompt_file" toml:"system_prompt_file"`
+	QueryTemplate     string   `yaml:"query_template" toml:"query_template"`
+	QueryTemplateFile string   `yaml:"query_template_file" toml:"query_template_file"`
+	Language          string   `yaml:"language" toml:"language"`
+	Voice             string   `yaml:"voice" toml:"voice"`
+	Format            string   `yaml:"format" toml:"format"`
+	Sources           []string `yaml:"sources" toml:"sources"`
+	RecencyDays       int      `yaml:"recency_days" toml:"recency_days"`
+	OutputLength      string   `yaml:"output_length" toml:"output_length"`
+	Audience          string   `yaml:"audience" toml:"audience"`
+
+	Budget   *BudgetFile `yaml:"budget" toml:"budget"`       // このプロファイルの使用額の上限（全体の上限と両方を確認する）
+	CacheTTL *Duration   `yaml:"cache_ttl" toml:"cache_ttl"` // このプロファイルの検索結果を再利用する期間
+}
+
+// OpenAIFile 設定ファイルの openai セクション
+type OpenAIFile struct {
+	APIKey      string   `yaml:"api_key" toml:"api_key"`
+	BaseURL     string   `yaml:"base_url" toml:"base_url"`
+	Model       string   `yaml:"model" toml:"model"`
+	Temperature *float64 `yaml:"temperature" toml:"temperature"`
+	Timeout     Duration `yaml:"timeout" toml:"timeout"`
+
+	// MaxRetries 429・5xx・接続エラー時の再送回数（音声合成にも適用）
+	MaxRetries   *int     `yaml:"max_retries" toml:"max_retries"`
+	MaxRetryWait Duration `yaml:"max_retry_wait" toml:"max_retry_wait"`
+}
+
+// TTSFile 設定ファイルの tts セクション
+type TTSFile struct {
+	Backend        string   `yaml:"backend" toml:"backend"`
+	Model          string   `yaml:"model" toml:"model"`
+	Voice          string   `yaml:"voice" toml:"voice"`
+	Speed          *float64 `yaml:"speed" toml:"speed"`
+	ResponseFormat string   `yaml:"response_format" toml:"response_format"`
+	Timeout        Duration `yaml:"timeout" toml:"timeout"`
+
+	// Voices 声を指定しなかった場合に使う言語ごとの声（例: {ja: alloy, en: onyx}）
+	Voices map[string]string `yaml:"voices" toml:"voices"`
+
+	Command  CommandFile  `yaml:"command" toml:"command"`
+	Voicevox VoicevoxFile `yaml:"voicevox" toml:"voicevox"`
+}
+
+// CommandFile 設定ファイルの tts.command セクション（backend: command の場合に使う）
+// run には {text} {input} {output} {voice} {speed} を埋め込める
+//
+//	run: open_jtalk -x /var/lib/mecab/dic/open-jtalk/naist-jdic -m voice.htsvoice -ow {output} {input}
+//	format: wav
+type CommandFile struct {
+	Run    string `yaml:"run" toml:"run"`
+	Format string `yaml:"format" toml:"format"`
+}
+
+// VoicevoxFile 設定ファイルの tts.voicevox セクション（backend: voicevox の場合に使う）
+type VoicevoxFile struct {
+	URL string `yaml:"url" toml:"url"`
+}
+
+// Duration "90s" や "2m" のような文字列で書ける時間
+type Duration time.Duration
+
+// UnmarshalText time.ParseDuration の形式を解釈する（YAML・TOML共通）
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", text)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// configFilePath 読み込む設定ファイルを決める
+// 明示的な指定 > NEWS_REPORTER_CONFIG > ~/.news_reporter/config.{yaml,yml,toml} の順
+func configFilePath(explicit string) (string, error) {
+	if explicit == "" {
+		explicit = os.Getenv("NEWS_REPORTER_CONFIG")
+	}
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", errors.New(i18n.T("config.file_not_found", explicit))
+		}
+		return explicit, nil
+	}
+
+	for _, name := range configFileNames {
+		path := filepath.Join(DataDir(), name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// loadFile 拡張子に応じてYAMLまたはTOMLの設定ファイルを読み込む
+func loadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", i18n.T("config.file_read"), err)
+	}
+
+	var file File
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		meta, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		// 書き間違えた項目が黙って無視されないようにする
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return nil, errors.New(i18n.T("config.unknown_key", path, undecoded[0]))
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	default:
+		return nil, errors.New(i18n.T("config.file_format", path))
+	}
+
+	return &file, nil
+}
+
+// ProfileNames 定義されているプロファイル名を名前順に返す
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyFile 設定ファイルの値を反映（省略された項目は変更しない）
+func (c *Config) applyFile(file *File, baseDir string) error {
+	if file.Language != "" {
+		c.Language = i18n.Normalize(file.Language)
+	}
+	if file.Backend != "" {
+		c.Backend = strings.ToLower(file.Backend)
+	}
+	if file.OpenAI.APIKey != "" {
+		c.OpenAIAPIKey = file.OpenAI.APIKey
+	}
+	if file.OpenAI.BaseURL != "" {
+		c.BaseURL = strings.TrimRight(file.OpenAI.BaseURL, "/")
+	}
+	if file.OpenAI.Model != "" {
+		c.Model = file.OpenAI.Model
+	}
+	if file.OpenAI.Temperature != nil {
+		c.Temperature = *file.OpenAI.Temperature
+	}
+	if file.OpenAI.Timeout != 0 {
+		c.SearchTimeout = time.Duration(file.OpenAI.Timeout)
+	}
+	if file.OpenAI.MaxRetries != nil {
+		c.MaxRetries = *file.OpenAI.MaxRetries
+	}
+	if file.OpenAI.MaxRetryWait != 0 {
+		c.RetryMaxWait = time.Duration(file.OpenAI.MaxRetryWait)
+	}
+
+	if file.Fixtures.Mode != "" {
+		c.FixtureMode = strings.ToLower(file.Fixtures.Mode)
+	}
+	if file.Fixtures.Dir != "" {
+		c.FixtureDir = file.Fixtures.Dir
+		if !filepath.IsAbs(c.FixtureDir) {
+			c.FixtureDir = filepath.Join(baseDir, c.FixtureDir)
+		}
+	}
+
+	c.applyPricing(file.Pricing)
+	c.Budget.apply(&file.Budget)
+	c.applyCache(file.Cache, baseDir)
+
+	if len(file.Feed.URLs) > 0 {
+		c.Feeds = file.Feed.URLs
+	}
+	if file.Feed.BaseURL != "" {
+		c.FeedBaseURL = strings.TrimRight(file.Feed.BaseURL, "/")
+	}
+	if file.Feed.Model != "" {
+		c.FeedModel = file.Feed.Model
+	}
+	if file.Feed.APIKey != "" {
+		c.FeedAPIKey = file.Feed.APIKey
+	}
+	if file.Feed.MaxItems != 0 {
+		c.FeedMaxItems = file.Feed.MaxItems
+	}
+
+	if file.TTS.Backend != "" {
+		c.TTSBackend = strings.ToLower(file.TTS.Backend)
+	}
+	if file.TTS.Model != "" {
+		c.TTSModel = file.TTS.Model
+	}
+	if file.TTS.Voice != "" {
+		c.Voice = file.TTS.Voice
+	}
+	if file.TTS.Speed != nil {
+		c.Speed = *file.TTS.Speed
+	}
+	if file.TTS.ResponseFormat != "" {
+		c.ResponseFormat = strings.ToLower(file.TTS.ResponseFormat)
+	}
+	if file.TTS.Timeout != 0 {
+		c.TTSTimeout = time.Duration(file.TTS.Timeout)
+	}
+	if file.TTS.Command.Run != "" {
+		c.TTSCommand = file.TTS.Command.Run
+	}
+	if file.TTS.Command.Format != "" {
+		c.CommandFormat = strings.ToLower(file.TTS.Command.Format)
+	}
+	if file.TTS.Voicevox.URL != "" {
+		c.VoicevoxURL = strings.TrimRight(file.TTS.Voicevox.URL, "/")
+	}
+
+	var err error
+	if c.SystemPrompt, err = templateText(c.SystemPrompt, file.Prompt.System, file.Prompt.SystemFile, baseDir); err != nil {
+		return err
+	}
+	if c.QueryTemplate, err = templateText(c.QueryTemplate, file.Prompt.Query, file.Prompt.QueryFile, baseDir); err != nil {
+		return err
+	}
+	if file.Prompt.Timezone != "" {
+		c.Timezone = file.Prompt.Timezone
+	}
+	if file.Prompt.RecencyDays != 0 {
+		c.RecencyDays = file.Prompt.RecencyDays
+	}
+	if file.Prompt.OutputLength != "" {
+		c.OutputLength = file.Prompt.OutputLength
+	}
+	if file.Prompt.Audience != "" {
+		c.Audience = file.Prompt.Audience
+	}
+	return nil
+}
+
+// applyProfile プロファイルの値を反映（省略された項目は変更しない）
+func (c *Config) applyProfile(profile *Profile, baseDir string) error {
+	if profile == nil {
+		return nil
+	}
+	if profile.Backend != "" {
+		c.Backend = strings.ToLower(profile.Backend)
+	}
+	if profile.TTSBackend != "" {
+		c.TTSBackend = strings.ToLower(profile.TTSBackend)
+	}
+	if len(profile.Feeds) > 0 {
+		c.Feeds = profile.Feeds
+	}
+	if profile.Model != "" {
+		c.Model = profile.Model
+	}
+	var err error
+	if c.SystemPrompt, err = templateText(c.SystemPrompt, profile.SystemPrompt, profile.SystemPromptFile, baseDir); err != nil {
+		return err
+	}
+	if c.QueryTemplate, err = templateText(c.QueryTemplate, profile.QueryTemplate, profile.QueryTemplateFile, baseDir); err != nil {
+		return err
+	}
+	if profile.Language != "" {
+		c.Language = i18n.Normalize(profile.Language)
+	}
+	if profile.Voice != "" {
+		c.Voice = profile.Voice
+	}
+	if profile.Format != "" {
+		c.Format = profile.Format
+	}
+	if len(profile.Sources) > 0 {
+		c.Sources = profile.Sources
+	}
+	if profile.RecencyDays != 0 {
+		c.RecencyDays = profile.RecencyDays
+	}
+	if profile.OutputLength != "" {
+		c.OutputLength = profile.OutputLength
+	}
+	if profile.Audience != "" {
+		c.Audience = profile.Audience
+	}
+	c.Budget.applyProfile(profile.Budget)
+	if profile.CacheTTL != nil {
+		c.CacheTTL = time.Duration(*profile.CacheTTL)
+	}
+	return nil
+}
+
+// templateText インラインのテンプレートかテンプレートファイルの内容を返す（どちらもなければ current のまま）
+func templateText(current, inline, path, baseDir string) (string, error) {
+	if inline != "" && path != "" {
+		return "", errors.New(i18n.T("config.template_conflict", path))
+	}
+	if inline != "" {
+		return inline, nil
+	}
+	if path == "" {
+		return current, nil
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("config.template_read"), err)
+	}
+	return string(data), nil
+}
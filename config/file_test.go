@@ -0,0 +1,192 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"news_reporter/i18n"
+)
+
+// isolateEnv 設定に関わる環境変数を空にし、ホームディレクトリの設定ファイルや .env を読まないようにする
+func isolateEnv(t *testing.T) {
+	t.Helper()
+	for _, e := range envSettings {
+		t.Setenv(e.env, "")
+	}
+	t.Setenv("NEWS_REPORTER_CONFIG", "")
+	t.Setenv("NEWS_REPORTER_PROFILE", "")
+	t.Setenv("HOME", t.TempDir())
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// writeConfig 一時ディレクトリに設定ファイルを書き出してパスを返す
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const yamlConfig = `
+language: en
+openai:
+  api_key: sk-file
+  model: gpt-4o
+  temperature: 0.5
+  timeout: 90s
+  max_retries: 1
+tts:
+  voice: nova
+  speed: 1.25
+  response_format: WAV
+  timeout: 2m
+prompt:
+  system_file: system.tmpl
+  recency_days: 3
+budget:
+  daily: 1.5
+  warn_at: 0.5
+cache:
+  ttl: 30m
+feed:
+  urls: [https://example.com/rss]
+`
+
+const tomlConfig = `
+language = "en"
+
+[openai]
+api_key = "sk-file"
+model = "gpt-4o"
+temperature = 0.5
+timeout = "90s"
+max_retries = 1
+
+[tts]
+voice = "nova"
+speed = 1.25
+response_format = "WAV"
+timeout = "2m"
+
+[prompt]
+system_file = "system.tmpl"
+recency_days = 3
+
+[budget]
+daily = 1.5
+warn_at = 0.5
+
+[cache]
+ttl = "30m"
+
+[feed]
+urls = ["https://example.com/rss"]
+`
+
+func TestLoadConfigFile(t *testing.T) {
+	for _, tt := range []struct{ name, content string }{
+		{"config.yaml", yamlConfig},
+		{"config.toml", tomlConfig},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t)
+			path := writeConfig(t, tt.name, tt.content)
+			// テンプレートファイルは設定ファイルからの相対パスで探す
+			if err := os.WriteFile(filepath.Join(filepath.Dir(path), "system.tmpl"), []byte("system prompt"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadConfig(Flags{ConfigFile: path})
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			checks := []struct {
+				field     string
+				got, want interface{}
+			}{
+				{"ConfigFile", cfg.ConfigFile, path},
+				{"Language", cfg.Language, "en"},
+				{"OpenAIAPIKey", cfg.OpenAIAPIKey, "sk-file"},
+				{"Model", cfg.Model, "gpt-4o"},
+				{"Temperature", cfg.Temperature, 0.5},
+				{"SearchTimeout", cfg.SearchTimeout, 90 * time.Second},
+				{"MaxRetries", cfg.MaxRetries, 1},
+				{"Voice", cfg.Voice, "nova"},
+				{"Speed", cfg.Speed, 1.25},
+				{"ResponseFormat", cfg.ResponseFormat, "wav"},
+				{"TTSTimeout", cfg.TTSTimeout, 2 * time.Minute},
+				{"SystemPrompt", cfg.SystemPrompt, "system prompt"},
+				{"RecencyDays", cfg.RecencyDays, 3},
+				{"Budget", cfg.Budget, Budget{Daily: 1.5, WarnAt: 0.5}},
+				{"CacheTTL", cfg.CacheTTL, 30 * time.Minute},
+				{"Feeds", strings.Join(cfg.Feeds, ","), "https://example.com/rss"},
+				// 省略した項目は既定値のまま
+				{"TTSModel", cfg.TTSModel, DefaultTTSModel},
+				{"Backend", cfg.Backend, BackendOpenAI},
+			}
+			for _, c := range checks {
+				if c.got != c.want {
+					t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    func(path string) string
+	}{
+		{"unknown toml key", "config.toml", "[openai]\nmodle = \"gpt-4o\"\n", func(path string) string {
+			return i18n.T("config.unknown_key", path, "openai.modle")
+		}},
+		{"unknown yaml key", "config.yaml", "openai:\n  modle: gpt-4o\n", func(path string) string {
+			return path + ": yaml: unmarshal errors:\n  line 2: field modle not found in type config.OpenAIFile"
+		}},
+		{"invalid duration", "config.yaml", "openai:\n  timeout: soon\n", func(path string) string {
+			return `invalid duration "soon"`
+		}},
+		{"unsupported format", "config.json", "{}", func(path string) string {
+			return i18n.T("config.file_format", path)
+		}},
+		{"missing template file", "config.yaml", "prompt:\n  system_file: missing.tmpl\n", func(path string) string {
+			return path + ": " + i18n.T("config.template_read")
+		}},
+		{"template conflict", "config.yaml", "prompt:\n  query: \"{{.Query}}\"\n  query_file: query.tmpl\n", func(path string) string {
+			return path + ": " + i18n.T("config.template_conflict", "query.tmpl")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t)
+			t.Setenv("OPENAI_API_KEY", "sk-env")
+			path := writeConfig(t, tt.file, tt.content)
+			_, err := LoadConfig(Flags{ConfigFile: path})
+			if err == nil || !strings.Contains(err.Error(), tt.want(path)) {
+				t.Errorf("LoadConfig error = %v, want %q", err, tt.want(path))
+			}
+		})
+	}
+
+	isolateEnv(t)
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	_, err := LoadConfig(Flags{ConfigFile: missing})
+	if err == nil || err.Error() != i18n.T("config.file_not_found", missing) {
+		t.Errorf("LoadConfig with a missing file = %v", err)
+	}
+}
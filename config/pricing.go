@@ -0,0 +1,220 @@
+package config
+
+import (
+	"errors"
+	"strings"
+
+	"news_reporter/i18n"
+)
+
+// ModelPrice モデルのトークン単価（USD / 100万トークン）
+type ModelPrice struct {
+	Input  float64 `yaml:"input" toml:"input"`
+	Output float64 `yaml:"output" toml:"output"`
+}
+
+// Pricing 費用の見積もりに使う料金表
+// モデル名は前方一致で引くため、gpt-4o-mini-2024-07-18 のような日付付きの名前にも gpt-4o-mini の単価を使う
+type Pricing struct {
+	Models           map[string]ModelPrice // モデルごとのトークン単価
+	WebSearchPerCall float64               // Web検索1回あたりの料金（USD）
+	Speech           map[string]float64    // 音声合成モデルごとの単価（USD / 100万文字）
+}
+
+// DefaultBudgetWarnAt 予算の上限に近づいたとみなす使用額の割合の既定値
+const DefaultBudgetWarnAt = 0.8
+
+// Budget 1日・1か月の使用額の上限（USD。0 の場合は上限なし）
+// Daily・Monthly は設定ファイル全体の budget で指定し、すべてのプロファイルの使用額の合計に適用する
+// ProfileDaily・ProfileMonthly はプロファイルの budget で指定し、そのプロファイルの使用額だけに適用する
+// いずれかの上限の WarnAt 倍を超えると FallbackModel に切り替えて音声合成を省略し、上限に達すると検索しない
+type Budget struct {
+	Daily          float64
+	Monthly        float64
+	ProfileDaily   float64
+	ProfileMonthly float64
+	WarnAt         float64 // 上限に近づいたとみなす割合（0〜1）
+	FallbackModel  string  // 上限に近づいたときに使う安価なモデル（空の場合は切り替えない）
+}
+
+// Enabled 上限が設定されているか
+func (b Budget) Enabled() bool {
+	return b.Daily > 0 || b.Monthly > 0 || b.ProfileDaily > 0 || b.ProfileMonthly > 0
+}
+
+// BudgetFile 設定ファイルの budget セクション
+// プロファイルごとにも指定できる。プロファイルの daily・monthly は全体の上限とは別に、そのプロファイルの使用額に適用する
+//
+//	budget:
+//	  daily: 1.00
+//	  monthly: 20.00
+//	  warn_at: 0.8
+//	  fallback_model: gpt-4o-mini
+type BudgetFile struct {
+	Daily         *float64 `yaml:"daily" toml:"daily"`
+	Monthly       *float64 `yaml:"monthly" toml:"monthly"`
+	WarnAt        *float64 `yaml:"warn_at" toml:"warn_at"`
+	FallbackModel string   `yaml:"fallback_model" toml:"fallback_model"`
+}
+
+// apply 設定ファイル全体の budget を反映（省略された項目は変更しない）
+func (b *Budget) apply(file *BudgetFile) {
+	if file == nil {
+		return
+	}
+	if file.Daily != nil {
+		b.Daily = *file.Daily
+	}
+	if file.Monthly != nil {
+		b.Monthly = *file.Monthly
+	}
+	b.applyOptions(file)
+}
+
+// applyProfile プロファイルの budget を反映（上限はプロファイル用の上限になり、全体の上限は変更しない）
+func (b *Budget) applyProfile(file *BudgetFile) {
+	if file == nil {
+		return
+	}
+	if file.Daily != nil {
+		b.ProfileDaily = *file.Daily
+	}
+	if file.Monthly != nil {
+		b.ProfileMonthly = *file.Monthly
+	}
+	b.applyOptions(file)
+}
+
+// applyOptions 上限以外の項目を反映（省略された項目は変更しない）
+func (b *Budget) applyOptions(file *BudgetFile) {
+	if file.WarnAt != nil {
+		b.WarnAt = *file.WarnAt
+	}
+	if file.FallbackModel != "" {
+		b.FallbackModel = file.FallbackModel
+	}
+}
+
+// validate 上限と割合の範囲を検証
+func (b Budget) validate() error {
+	if b.Daily < 0 || b.Monthly < 0 || b.ProfileDaily < 0 || b.ProfileMonthly < 0 {
+		return errors.New(i18n.T("config.budget_limit"))
+	}
+	if b.WarnAt <= 0 || b.WarnAt > 1 {
+		return errors.New(i18n.T("config.budget_warn_at", b.WarnAt))
+	}
+	return nil
+}
+
+// validateBudget 上限を設定した場合に、使うモデルの単価が料金表にあるか検証
+// 単価がないモデルの使用額は0として記録されるため、上限が働かなくなる
+func (c *Config) validateBudget() error {
+	if err := c.Budget.validate(); err != nil {
+		return err
+	}
+	if !c.Budget.Enabled() {
+		return nil
+	}
+	for _, model := range []string{c.Model, c.Budget.FallbackModel} {
+		if model == "" {
+			continue
+		}
+		if _, ok := c.Pricing.ModelPrice(model); !ok {
+			return errors.New(i18n.T("config.budget_pricing", model))
+		}
+	}
+	return nil
+}
+
+// PricingFile 設定ファイルの pricing セクション（指定したモデルだけ既定の料金表を上書きする）
+//
+//	pricing:
+//	  web_search_per_call: 0.025
+//	  models:
+//	    gpt-4o-mini: {input: 0.15, output: 0.60}
+//	  speech:
+//	    tts-1: 15
+type PricingFile struct {
+	WebSearchPerCall *float64              `yaml:"web_search_per_call" toml:"web_search_per_call"`
+	Models           map[string]ModelPrice `yaml:"models" toml:"models"`
+	Speech           map[string]float64    `yaml:"speech" toml:"speech"`
+}
+
+// DefaultPricing 既定の料金表（OpenAI の公開価格。改定された場合は設定ファイルで上書きする）
+func DefaultPricing() Pricing {
+	return Pricing{
+		Models: map[string]ModelPrice{
+			"gpt-4o-mini":  {Input: 0.15, Output: 0.60},
+			"gpt-4o":       {Input: 2.50, Output: 10.00},
+			"gpt-4.1-nano": {Input: 0.10, Output: 0.40},
+			"gpt-4.1-mini": {Input: 0.40, Output: 1.60},
+			"gpt-4.1":      {Input: 2.00, Output: 8.00},
+			"gpt-5-nano":   {Input: 0.05, Output: 0.40},
+			"gpt-5-mini":   {Input: 0.25, Output: 2.00},
+			"gpt-5":        {Input: 1.25, Output: 10.00},
+			"o4-mini":      {Input: 1.10, Output: 4.40},
+		},
+		WebSearchPerCall: 0.025,
+		Speech: map[string]float64{
+			"tts-1":           15.00,
+			"tts-1-hd":        30.00,
+			"gpt-4o-mini-tts": 12.00,
+		},
+	}
+}
+
+// ModelPrice モデルのトークン単価（料金表にない場合は false）
+// 最も長く前方一致するモデルの単価を使う（gpt-4o-mini は gpt-4o より優先される）
+func (p Pricing) ModelPrice(model string) (ModelPrice, bool) {
+	var price ModelPrice
+	best := ""
+	for key, value := range p.Models {
+		if strings.HasPrefix(model, key) && len(key) > len(best) {
+			best, price = key, value
+		}
+	}
+	return price, best != ""
+}
+
+// SpeechPrice 音声合成モデルの100万文字あたりの単価（料金表にない場合は false）
+func (p Pricing) SpeechPrice(model string) (float64, bool) {
+	var price float64
+	best := ""
+	for key, value := range p.Speech {
+		if strings.HasPrefix(model, key) && len(key) > len(best) {
+			best, price = key, value
+		}
+	}
+	return price, best != ""
+}
+
+// validate 単価が負の値でないか検証
+func (p Pricing) validate() error {
+	if p.WebSearchPerCall < 0 {
+		return errors.New(i18n.T("config.web_search_price", p.WebSearchPerCall))
+	}
+	for model, price := range p.Models {
+		if price.Input < 0 || price.Output < 0 {
+			return errors.New(i18n.T("config.model_price", model))
+		}
+	}
+	for model, price := range p.Speech {
+		if price < 0 {
+			return errors.New(i18n.T("config.speech_price", model, price))
+		}
+	}
+	return nil
+}
+
+// applyPricing 設定ファイルの料金表を既定の料金表に重ねる
+func (c *Config) applyPricing(file PricingFile) {
+	if file.WebSearchPerCall != nil {
+		c.Pricing.WebSearchPerCall = *file.WebSearchPerCall
+	}
+	for model, price := range file.Models {
+		c.Pricing.Models[model] = price
+	}
+	for model, price := range file.Speech {
+		c.Pricing.Speech[model] = price
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateBudgetPricing(t *testing.T) {
+	cfg := Default()
+	cfg.Model = "llama3.1"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate without a budget: %v", err)
+	}
+
+	// 上限を設定した場合、単価のないモデルは使用額が0になり上限が働かないためエラーにする
+	cfg.Budget.Daily = 1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "llama3.1") {
+		t.Errorf("Validate with an unpriced model = %v", err)
+	}
+
+	cfg.Model = "gpt-4o-2024-08-06"
+	cfg.Budget.FallbackModel = "my-cheap-model"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "my-cheap-model") {
+		t.Errorf("Validate with an unpriced fallback model = %v", err)
+	}
+
+	cfg.Pricing.Models["my-cheap-model"] = ModelPrice{Input: 0.1, Output: 0.2}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate with priced models: %v", err)
+	}
+}
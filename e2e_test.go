@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/go-mp3"
+
+	"news_reporter/mockserver"
+	"news_reporter/models"
+	"news_reporter/usage"
+)
+
+// e2eMainEnv 設定されている場合、テストバイナリを CLI として動かす
+const e2eMainEnv = "NEWS_REPORTER_E2E_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(e2eMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// e2e モックサーバーに接続する CLI の実行環境
+type e2e struct {
+	t      *testing.T
+	server *mockserver.Server
+	url    string
+	dir    string // 作業ディレクトリ（HOME も兼ねる）
+}
+
+// newE2E モックサーバーを起動して、設定ファイルや .env の影響を受けない実行環境を用意する
+func newE2E(t *testing.T, opts mockserver.Options) *e2e {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("end-to-end test")
+	}
+	server := mockserver.New(opts)
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return &e2e{t: t, server: server, url: httpServer.URL + "/v1", dir: t.TempDir()}
+}
+
+// run CLI を実行して、標準出力と標準エラー出力をまとめた出力と終了コードを返す
+func (e *e2e) run(apiKey string, args ...string) (string, int) {
+	e.t.Helper()
+	var out bytes.Buffer
+	code := e.exec(apiKey, &out, &out, args...)
+	return out.String(), code
+}
+
+// runSplit CLI を実行して、標準出力・標準エラー出力と終了コードを別々に返す
+func (e *e2e) runSplit(apiKey string, args ...string) (string, string, int) {
+	e.t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := e.exec(apiKey, &stdout, &stderr, args...)
+	return stdout.String(), stderr.String(), code
+}
+
+// exec CLI を実行して終了コードを返す
+func (e *e2e) exec(apiKey string, stdout, stderr io.Writer, args ...string) int {
+	e.t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = e.dir
+
+	// 開発者の環境変数（APIキーやプロファイル）は引き継がない
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "OPENAI_") || strings.HasPrefix(kv, "NEWS_REPORTER_") || strings.HasPrefix(kv, "HOME=") {
+			continue
+		}
+		cmd.Env = append(cmd.Env, kv)
+	}
+	cmd.Env = append(cmd.Env,
+		e2eMainEnv+"=1",
+		"HOME="+e.dir,
+		"OPENAI_BASE_URL="+e.url,
+		"OPENAI_API_KEY="+apiKey,
+		"NEWS_REPORTER_LANG=ja",
+	)
+
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		e.t.Fatalf("failed to run CLI: %v", err)
+	}
+	return 0
+}
+
+// requests path へのリクエスト数
+func (e *e2e) requests(path string) int {
+	n := 0
+	for _, r := range e.server.Requests() {
+		if r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+var e2eCitations = []mockserver.Citation{
+	{Title: "新モデル発表", URL: "https://example.com/model"},
+	{Title: "業務利用が拡大", URL: "https://example.jp/business"},
+}
+
+func TestE2ESearch(t *testing.T) {
+	e := newE2E(t, mockserver.Options{
+		Deltas:    []string{"OpenAIが新しいモデルを発表しました。", "国内では業務利用が広がっています。"},
+		Citations: e2eCitations,
+	})
+
+	out, code := e.run("sk-mock", "AI ニュース")
+	if code != 0 {
+		t.Fatalf("exit code = %d\n%s", code, out)
+	}
+	for _, want := range []string{"OpenAIが新しいモデルを発表しました。国内では業務利用が広がっています。", "2. 業務利用が拡大", "https://example.com/model"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestE2ESearchJSON(t *testing.T) {
+	e := newE2E(t, mockserver.Options{Citations: e2eCitations})
+
+	// ローカルのモックサーバーに接続する場合はAPIキーがなくてもよい
+	out, code := e.run("", "--format", "json", "--no-history", "AI 規制")
+	if code != 0 {
+		t.Fatalf("exit code = %d\n%s", code, out)
+	}
+	start := strings.Index(out, "{")
+	if start < 0 {
+		t.Fatalf("no JSON in output:\n%s", out)
+	}
+	var result models.SearchResult
+	if err := json.NewDecoder(strings.NewReader(out[start:])).Decode(&result); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if !strings.Contains(result.Summary, "AI 規制") || len(result.Results) != 2 || result.Usage == nil {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestE2ESearchJSONWithAudio(t *testing.T) {
+	e := newE2E(t, mockserver.Options{Citations: e2eCitations})
+
+	// 音声の保存を含めても、標準出力はJSONだけにする
+	stdout, stderr, code := e.runSplit("sk-mock", "--format", "json", "--save", "summary.mp3", "AI ニュース")
+	if code != 0 {
+		t.Fatalf("exit code = %d\n%s%s", code, stdout, stderr)
+	}
+	var result models.SearchResult
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("stdout is not a single JSON document: %v\n%s", err, stdout)
+	}
+	if len(result.Results) != 2 {
+		t.Errorf("result = %+v", result)
+	}
+	if !strings.Contains(stderr, "summary.mp3") {
+		t.Errorf("status messages are not on stderr:\n%s", stderr)
+	}
+	if _, err := os.Stat(filepath.Join(e.dir, "summary.mp3")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestE2EStreamAndSave(t *testing.T) {
+	e := newE2E(t, mockserver.Options{})
+
+	out, code := e.run("sk-mock", "--stream", "--save", "summary.mp3", "今日のニュース")
+	if code != 0 {
+		t.Fatalf("exit code = %d\n%s", code, out)
+	}
+	if !strings.Contains(out, "モックの要約です") {
+		t.Errorf("streamed summary not found:\n%s", out)
+	}
+
+	// 保存した音声は MP3 として復号できる
+	f, err := os.Open(filepath.Join(e.dir, "summary.mp3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	decoder, err := mp3.NewDecoder(f)
+	if err != nil {
+		t.Fatalf("saved audio is not MP3: %v", err)
+	}
+	if n, err := io.Copy(io.Discard, decoder); err != nil || n == 0 {
+		t.Errorf("decoded %d bytes: %v", n, err)
+	}
+	if e.requests("/v1/audio/speech") == 0 {
+		t.Error("no /v1/audio/speech request")
+	}
+}
+
+func TestE2EInvalidAPIKey(t *testing.T) {
+	e := newE2E(t, mockserver.Options{APIKey: "sk-valid"})
+
+	out, code := e.run("sk-wrong", "AI ニュース")
+	if code != exitInvalidAPIKey {
+		t.Fatalf("exit code = %d, want %d\n%s", code, exitInvalidAPIKey, out)
+	}
+	if !strings.Contains(out, "req_mock_1") {
+		t.Errorf("request ID not shown:\n%s", out)
+	}
+}
+
+func TestE2EBatch(t *testing.T) {
+	e := newE2E(t, mockserver.Options{Citations: e2eCitations})
+	if err := os.WriteFile(filepath.Join(e.dir, "queries.txt"), []byte("AI ニュース\n半導体\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, code := e.run("sk-mock", "batch", "queries.txt", "--interval", "0s", "--out", "results", "--format", "markdown")
+	if code != 0 {
+		t.Fatalf("exit code = %d\n%s", code, out)
+	}
+	files, _ := filepath.Glob(filepath.Join(e.dir, "results", "*.md"))
+	if len(files) != 2 {
+		t.Fatalf("result files = %v\n%s", files, out)
+	}
+	data, err := os.ReadFile(files[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "半導体") || !strings.Contains(string(data), "[新モデル発表](https://example.com/model)") {
+		t.Errorf("%s:\n%s", files[1], data)
+	}
+}
+
+func TestE2EBriefing(t *testing.T) {
+	e := newE2E(t, mockserver.Options{})
+
+	out, code := e.run("sk-mock", "briefing", "--out", "briefing.mp3", "AI", "経済")
+	if code != 0 {
+		t.Fatalf("exit code = %d\n%s", code, out)
+	}
+	for _, name := range []string{"briefing.mp3", "briefing.txt"} {
+		if info, err := os.Stat(filepath.Join(e.dir, name)); err != nil || info.Size() == 0 {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if got := e.requests("/v1/responses"); got < 2 {
+		t.Errorf("/v1/responses requests = %d, want at least 2", got)
+	}
+
+	// 2件の検索・原稿の生成・音声合成の費用がトピックごとに集計される
+	out, code = e.run("", "usage", "--by", "topic", "--format", "json")
+	if code != 0 {
+		t.Fatalf("usage exit code = %d\n%s", code, out)
+	}
+	var report struct {
+		Rows  []struct{ Key string }
+		Total struct {
+			Searches int
+			Speeches int
+			Cost     float64 `json:"cost_usd"`
+		}
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("usage output is not JSON: %v\n%s", err, out)
+	}
+	if len(report.Rows) != 3 || report.Total.Searches != 2 || report.Total.Speeches != 1 || report.Total.Cost <= 0 {
+		t.Errorf("usage report = %+v", report)
+	}
+}
+
+func TestE2EUsageProfile(t *testing.T) {
+	e := newE2E(t, mockserver.Options{})
+
+	ledger := usage.NewLedger(filepath.Join(e.dir, ".news_reporter", "usage.jsonl"))
+	now := time.Now()
+	for _, entry := range []usage.Entry{
+		{Time: now, Kind: usage.KindSearch, Topic: "為替", Profile: "econ", Cost: 1},
+		{Time: now, Kind: usage.KindSearch, Topic: "AI", Profile: "tech", Cost: 2},
+	} {
+		if err := ledger.Append(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want float64
+	}{
+		{[]string{"usage", "--format", "json"}, 3},
+		{[]string{"usage", "--profile", "econ", "--format", "json"}, 1},
+		{[]string{"--profile", "tech", "usage", "--by", "topic", "--format", "json"}, 2},
+	} {
+		stdout, stderr, code := e.runSplit("", tc.args...)
+		if code != 0 {
+			t.Fatalf("%v: exit code = %d\n%s%s", tc.args, code, stdout, stderr)
+		}
+		var report struct {
+			Total usage.Row `json:"total"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+			t.Fatalf("%v: output is not JSON: %v\n%s", tc.args, err, stdout)
+		}
+		if report.Total.Cost != tc.want {
+			t.Errorf("%v: total cost = %v, want %v", tc.args, report.Total.Cost, tc.want)
+		}
+	}
+}
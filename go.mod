@@ -5,8 +5,10 @@ go 1.21
 require github.com/joho/godotenv v1.5.1
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/hajimehoshi/go-mp3 v0.3.4
 	github.com/hajimehoshi/oto/v2 v2.4.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"news_reporter/i18n"
+	"news_reporter/models"
+)
+
+// BatchQuery バッチ検索の1件分
+type BatchQuery struct {
+	Query string `json:"query"`
+	// Name 出力ファイル名に使う名前（省略時はクエリから生成）
+	Name string `json:"name,omitempty"`
+}
+
+// BatchOptions バッチ検索の実行オプション
+type BatchOptions struct {
+	Workers   int           // 同時に実行する検索の数
+	Interval  time.Duration // 検索を開始する最小間隔（0 の場合は制限なし）
+	OutputDir string        // クエリごとの結果ファイルの出力先
+	Format    string        // 結果ファイルの出力形式
+}
+
+// BatchResult バッチ検索1件分の実行結果
+type BatchResult struct {
+	Query      BatchQuery
+	Result     *models.SearchResult
+	OutputPath string
+	Duration   time.Duration
+	Err        error
+}
+
+type BatchHandler struct {
+	searchHandler *SearchHandler
+	out           io.Writer
+}
+
+// NewBatchHandler 新しいバッチハンドラーを作成
+func NewBatchHandler(searchHandler *SearchHandler) *BatchHandler {
+	return &BatchHandler{
+		searchHandler: searchHandler,
+		out:           os.Stdout,
+	}
+}
+
+// ReadBatchQueries ファイルからクエリを読み込む
+// 拡張子が .jsonl の場合は1行1件の {"query": "...", "name": "..."} として、
+// それ以外は1行1クエリのテキストとして扱う（空行と # で始まる行は無視）
+func ReadBatchQueries(path string) ([]BatchQuery, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("クエリファイルを開けません: %w", err)
+	}
+	defer file.Close()
+
+	isJSONL := strings.EqualFold(filepath.Ext(path), ".jsonl")
+
+	var queries []BatchQuery
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		query := BatchQuery{Query: line}
+		if isJSONL {
+			query = BatchQuery{}
+			if err := json.Unmarshal([]byte(line), &query); err != nil {
+				return nil, fmt.Errorf("%s:%d: 不正なJSONです: %w", path, lineNumber, err)
+			}
+			query.Query = strings.TrimSpace(query.Query)
+			if query.Query == "" {
+				return nil, fmt.Errorf("%s:%d: query が空です", path, lineNumber)
+			}
+		}
+		queries = append(queries, query)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("クエリファイルの読み込みに失敗しました: %w", err)
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("クエリファイルにクエリがありません: %s", path)
+	}
+
+	return queries, nil
+}
+
+// Run クエリを並列に検索し、結果をクエリごとのファイルに書き出す
+// 個々の検索の失敗はバッチ全体を中断せず、戻り値の BatchResult.Err に記録する
+// ctx がキャンセルされると実行中の検索を中断し、未実行のクエリには ctx のエラーを記録する
+func (b *BatchHandler) Run(ctx context.Context, queries []BatchQuery, opts BatchOptions) ([]BatchResult, error) {
+	renderer, err := NewRenderer(opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("出力ディレクトリを作成できません: %w", err)
+	}
+
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(queries) {
+		workers = len(queries)
+	}
+
+	// 検索開始のペースを制限
+	var throttle <-chan time.Time
+	if opts.Interval > 0 {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	fmt.Fprintln(b.out, i18n.T("batch.start", len(queries), workers))
+	fmt.Fprintln(b.out, strings.Repeat("-", 50))
+
+	results := make([]BatchResult, len(queries))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	completed := 0
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				result := b.runOne(ctx, index, queries[index], renderer, opts)
+				results[index] = result
+
+				mu.Lock()
+				completed++
+				b.reportProgress(completed, len(queries), result)
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for index := range queries {
+		if throttle != nil && index > 0 {
+			select {
+			case <-throttle:
+			case <-ctx.Done():
+			}
+		}
+		select {
+		case jobs <- index:
+		case <-ctx.Done():
+			// 中断後のクエリは実行せずに中断として記録
+			for rest := index; rest < len(queries); rest++ {
+				results[rest] = BatchResult{Query: queries[rest], Err: ctx.Err()}
+			}
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	b.reportSummary(results)
+
+	return results, nil
+}
+
+// runOne 1件のクエリを検索して結果ファイルを書き出す
+func (b *BatchHandler) runOne(ctx context.Context, index int, query BatchQuery, renderer Renderer, opts BatchOptions) BatchResult {
+	start := time.Now()
+	batchResult := BatchResult{Query: query}
+
+	result, err := b.searchHandler.Search(ctx, query.Query)
+	batchResult.Duration = time.Since(start)
+	if err != nil {
+		batchResult.Err = err
+		return batchResult
+	}
+	batchResult.Result = result
+
+	name := query.Name
+	if name == "" {
+		name = query.Query
+	}
+	filename := fmt.Sprintf("%03d-%s%s", index+1, slugify(name), FileExtension(opts.Format))
+	outputPath := filepath.Join(opts.OutputDir, filename)
+
+	if err := writeRendered(outputPath, renderer, result); err != nil {
+		batchResult.Err = err
+		return batchResult
+	}
+	batchResult.OutputPath = outputPath
+
+	return batchResult
+}
+
+// reportProgress 1件完了ごとの進捗を表示
+func (b *BatchHandler) reportProgress(completed, total int, result BatchResult) {
+	elapsed := result.Duration.Round(100 * time.Millisecond)
+	if result.Err != nil {
+		fmt.Fprintf(b.out, "[%d/%d] ❌ %s (%s): %v\n", completed, total, result.Query.Query, elapsed, result.Err)
+		return
+	}
+	fmt.Fprintf(b.out, "[%d/%d] ✅ %s (%s) → %s\n", completed, total, result.Query.Query, elapsed, result.OutputPath)
+}
+
+// reportSummary バッチ全体の結果を表示
+func (b *BatchHandler) reportSummary(results []BatchResult) {
+	var failed []BatchResult
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	fmt.Fprintln(b.out, strings.Repeat("=", 50))
+	fmt.Fprintln(b.out, i18n.T("batch.summary", len(results)-len(failed), len(failed)))
+	for _, result := range failed {
+		fmt.Fprintf(b.out, "  ❌ %s: %v\n", result.Query.Query, result.Err)
+	}
+}
+
+// writeRendered 検索結果をファイルに書き出す
+func writeRendered(path string, renderer Renderer, result *models.SearchResult) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("結果ファイルを作成できません: %w", err)
+	}
+
+	if err := renderer.Render(file, result); err != nil {
+		file.Close()
+		return fmt.Errorf("結果の出力に失敗しました: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("結果ファイルの保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// slugify 文字列をファイル名に使える形に変換
+func slugify(text string) string {
+	var b strings.Builder
+	lastDash := false
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			lastDash = false
+		case !lastDash && b.Len() > 0:
+			b.WriteRune('-')
+			lastDash = true
+		}
+	}
+
+	slug := []rune(strings.TrimSuffix(b.String(), "-"))
+	if len(slug) == 0 {
+		return "query"
+	}
+	if len(slug) > 40 {
+		slug = slug[:40]
+	}
+	return string(slug)
+}
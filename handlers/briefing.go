@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"news_reporter/i18n"
+	"news_reporter/models"
+	"news_reporter/prompt"
+	"news_reporter/usage"
+)
+
+// BriefingOptions ブリーフィング生成のオプション
+type BriefingOptions struct {
+	Title      string // 番組名
+	OutputPath string // 音声の保存先
+	ScriptPath string // 原稿テキストの保存先（空の場合は保存しない）
+	Workers    int    // 同時に実行する検索の数
+}
+
+// Briefing 生成されたブリーフィング
+type Briefing struct {
+	Title   string
+	Date    time.Time
+	Results []*models.SearchResult
+	Script  string
+}
+
+type BriefingHandler struct {
+	searchHandler *SearchHandler
+	out           io.Writer
+}
+
+// NewBriefingHandler 新しいブリーフィングハンドラーを作成
+func NewBriefingHandler(searchHandler *SearchHandler) *BriefingHandler {
+	return &BriefingHandler{
+		searchHandler: searchHandler,
+		out:           os.Stdout,
+	}
+}
+
+// Run 複数トピックを検索して1本の原稿にまとめ、音声として保存
+// ctx がキャンセルされると検索・原稿作成・音声合成を中断する
+func (b *BriefingHandler) Run(ctx context.Context, queries []string, opts BriefingOptions) (*Briefing, error) {
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("トピックが指定されていません")
+	}
+
+	briefing := &Briefing{
+		Title: opts.Title,
+		Date:  time.Now(),
+	}
+
+	fmt.Fprintln(b.out, i18n.T("briefing.start", opts.Title, len(queries)))
+	fmt.Fprintln(b.out, strings.Repeat("-", 50))
+
+	results, err := b.searchAll(ctx, queries, opts.Workers)
+	if err != nil {
+		return nil, err
+	}
+	briefing.Results = results
+
+	fmt.Fprintln(b.out, "\n"+i18n.T("briefing.writing"))
+	// 原稿の指示は言語ごとの組み込みテンプレートから作成
+	instructions, err := prompt.Render(prompt.KindBriefing, "", b.searchHandler.searcher.PromptVars(""))
+	if err != nil {
+		return nil, err
+	}
+	generated, err := b.searchHandler.searcher.Generate(ctx, instructions, buildBriefingInput(briefing))
+	if err != nil {
+		return nil, fmt.Errorf("原稿の作成に失敗しました: %w", err)
+	}
+	b.searchHandler.recordUsage(usage.KindGenerate, opts.Title, generated)
+	script := strings.TrimSpace(generated.Summary)
+	if script == "" {
+		return nil, fmt.Errorf("原稿が空でした")
+	}
+	briefing.Script = script
+
+	fmt.Fprintln(b.out, "\n"+i18n.T("briefing.script"))
+	fmt.Fprintln(b.out, strings.Repeat("-", 30))
+	fmt.Fprintln(b.out, script)
+	fmt.Fprintln(b.out, strings.Repeat("-", 30))
+
+	if opts.ScriptPath != "" {
+		if err := os.WriteFile(opts.ScriptPath, []byte(script+"\n"), 0644); err != nil {
+			return nil, fmt.Errorf("原稿の保存に失敗しました: %w", err)
+		}
+		fmt.Fprintln(b.out, i18n.T("briefing.script_saved", opts.ScriptPath))
+	}
+
+	// 原稿は話し言葉で書かせているが、残った記号や数値表記も読み上げ用に整える
+	speech := b.searchHandler.speechText(script)
+
+	// 上限に近づいている場合は原稿だけを残して音声合成を省略する（キャッシュされた音声は使う）
+	cached, err := b.searchHandler.checkSpeech(speech)
+	if err != nil {
+		if !errors.Is(err, usage.ErrSpeechSkipped) {
+			return nil, err
+		}
+		fmt.Fprintln(b.out, i18n.T("audio.budget_skipped", err))
+		return briefing, nil
+	}
+	if err := b.searchHandler.ttsClient.SaveToFile(ctx, speech, opts.OutputPath); err != nil {
+		return nil, err
+	}
+	if !cached {
+		b.searchHandler.recordSpeech(opts.Title, speech)
+	}
+
+	return briefing, nil
+}
+
+// searchAll トピックを並列に検索する（失敗したトピックは除外して続行）
+func (b *BriefingHandler) searchAll(ctx context.Context, queries []string, workers int) ([]*models.SearchResult, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]*models.SearchResult, len(queries))
+	semaphore := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for i, query := range queries {
+		wg.Add(1)
+		go func(i int, query string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result, err := b.searchHandler.Search(ctx, query)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(b.out, "❌ %s: %v\n", query, err)
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			fmt.Fprintln(b.out, i18n.T("briefing.topic_done", query, len(result.Results)))
+			results[i] = result
+		}(i, query)
+	}
+	wg.Wait()
+
+	// 中断された場合は成功したトピックだけで原稿を作らない
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// 入力順を保ったまま成功した結果だけを残す
+	succeeded := make([]*models.SearchResult, 0, len(results))
+	for _, result := range results {
+		if result != nil && result.Summary != "" {
+			succeeded = append(succeeded, result)
+		}
+	}
+	if len(succeeded) == 0 {
+		// 原因（APIキーや利用上限など）を呼び出し元で判別できるよう最初のエラーを含める
+		if firstErr != nil {
+			return nil, fmt.Errorf("すべてのトピックの検索に失敗しました: %w", firstErr)
+		}
+		return nil, fmt.Errorf("すべてのトピックの検索に失敗しました")
+	}
+
+	return succeeded, nil
+}
+
+// buildBriefingInput 原稿作成用に検索結果をまとめた入力を組み立てる
+func buildBriefingInput(briefing *Briefing) string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, i18n.T("briefing.input.title", briefing.Title))
+	fmt.Fprintln(&b, i18n.T("briefing.input.date", briefing.Date.Format(i18n.T("layout.date"))))
+	fmt.Fprintln(&b, i18n.T("briefing.input.topics", len(briefing.Results)))
+
+	for i, result := range briefing.Results {
+		fmt.Fprintln(&b, "\n"+i18n.T("briefing.input.topic", i+1, result.Query))
+		b.WriteString(strings.TrimSpace(result.Summary))
+		b.WriteString("\n")
+
+		if len(result.Results) > 0 {
+			b.WriteString(i18n.T("briefing.input.sources") + "\n")
+			for _, source := range result.Results {
+				fmt.Fprintf(&b, "- %s\n", source.Title)
+			}
+		}
+	}
+
+	return b.String()
+}
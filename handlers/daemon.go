@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"news_reporter/i18n"
+	"news_reporter/scheduler"
+	"news_reporter/usage"
+)
+
+const (
+	// daemonHeartbeat 予定がなくても状態ファイルを更新する間隔
+	daemonHeartbeat = time.Minute
+	// daemonAliveWindow status コマンドで稼働中とみなす最終更新からの経過時間
+	daemonAliveWindow = 3 * daemonHeartbeat
+)
+
+type DaemonHandler struct {
+	searchHandler *SearchHandler
+	schedule      *scheduler.Schedule
+	scheduleFile  string
+	statePath     string
+	state         *scheduler.State
+	out           io.Writer
+}
+
+// NewDaemonHandler 新しいデーモンハンドラーを作成
+func NewDaemonHandler(searchHandler *SearchHandler, schedule *scheduler.Schedule, scheduleFile, statePath string) *DaemonHandler {
+	return &DaemonHandler{
+		searchHandler: searchHandler,
+		schedule:      schedule,
+		scheduleFile:  scheduleFile,
+		statePath:     statePath,
+		out:           os.Stdout,
+	}
+}
+
+// Run スケジュールに従って検索を定期実行する。ctx がキャンセルされると
+// 実行中の検索の完了を待ってから状態を保存して終了する
+func (d *DaemonHandler) Run(ctx context.Context) error {
+	state, err := scheduler.LoadState(d.statePath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("daemon.state_load_failed"), err)
+	}
+	d.state = state
+
+	now := d.now()
+	d.state.PID = os.Getpid()
+	d.state.ScheduleFile = d.scheduleFile
+	d.state.StartedAt = now
+	d.state.StoppedAt = time.Time{}
+
+	d.logf("daemon.started", len(d.schedule.Topics), d.schedule.OutputDir)
+
+	// 停止中に取りこぼした回を確認
+	var catchUp []*scheduler.Topic
+	active := make(map[string]bool)
+	for _, topic := range d.schedule.Topics {
+		active[topic.Name] = true
+		topicState := d.topicState(topic)
+		if d.shouldCatchUp(topic, topicState, now) {
+			catchUp = append(catchUp, topic)
+		}
+		topicState.NextRun = topic.Next(now)
+		d.logf("daemon.topic", topic.Name, topic.Cron, topicState.NextRun.Format("2006-01-02 15:04"))
+	}
+
+	// スケジュールから削除されたトピックの状態は破棄
+	for name := range d.state.Topics {
+		if !active[name] {
+			delete(d.state.Topics, name)
+		}
+	}
+	d.saveState()
+
+	for _, topic := range catchUp {
+		if ctx.Err() != nil {
+			break
+		}
+		d.logf("daemon.catch_up", topic.Name)
+		d.runTopic(ctx, topic)
+	}
+
+	for {
+		wait := time.Until(d.nextWakeup())
+		if wait < 0 {
+			wait = 0
+		}
+		if wait > daemonHeartbeat {
+			wait = daemonHeartbeat
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			d.state.StoppedAt = d.now()
+			d.saveState()
+			d.logf("daemon.stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		now := d.now()
+		for _, topic := range d.schedule.Topics {
+			if ctx.Err() != nil {
+				break
+			}
+
+			topicState := d.topicState(topic)
+			if topicState.NextRun.IsZero() || topicState.NextRun.After(now) {
+				continue
+			}
+
+			// スリープ復帰などで大きく遅れた回は取り戻し期間を超えていればスキップ
+			if now.Sub(topicState.NextRun) > d.schedule.CatchUpSpan() {
+				d.logf("daemon.skipped_late", topic.Name, topicState.NextRun.Format("2006-01-02 15:04"))
+				topicState.Missed++
+			} else {
+				d.runTopic(ctx, topic)
+			}
+
+			// 実行中に過ぎた回は Missed に数え、最後の回だけを次に実行する
+			next, skipped := topic.NextAfter(topicState.NextRun, d.now())
+			if skipped > 0 {
+				d.logf("daemon.skipped_overrun", topic.Name, skipped)
+				topicState.Missed += skipped
+			}
+			topicState.NextRun = next
+		}
+
+		d.saveState()
+	}
+}
+
+// shouldCatchUp 前回実行後に予定された回が取り戻し期間内にあるか判定
+func (d *DaemonHandler) shouldCatchUp(topic *scheduler.Topic, topicState *scheduler.TopicState, now time.Time) bool {
+	if topicState.LastRun.IsZero() {
+		return false
+	}
+
+	// 前回実行後、現在までに予定されていた最後の回を求める
+	latest, skipped := topic.NextAfter(topicState.LastRun, now)
+	if latest.IsZero() || latest.After(now) {
+		return false
+	}
+
+	if now.Sub(latest) > d.schedule.CatchUpSpan() {
+		topicState.Missed += skipped + 1
+		d.logf("daemon.skipped_offline", topic.Name)
+		return false
+	}
+
+	topicState.Missed += skipped
+	return true
+}
+
+// runTopic トピックの検索を実行し、結果ファイル（と音声）を保存
+// 停止の指示を受けても実行中の回は最後まで実行し、結果を取りこぼさない
+func (d *DaemonHandler) runTopic(ctx context.Context, topic *scheduler.Topic) {
+	ctx = context.WithoutCancel(ctx)
+
+	topicState := d.topicState(topic)
+	start := d.now()
+	topicState.LastRun = start
+	topicState.LastStatus = scheduler.StatusRunning
+	topicState.LastError = ""
+	d.saveState()
+
+	d.logf("daemon.searching", topic.Name, topic.Query)
+
+	outputPath, err := d.searchAndSave(ctx, topic, start)
+	if err != nil {
+		topicState.LastStatus = scheduler.StatusFailed
+		topicState.LastError = err.Error()
+		d.logf("daemon.failed", topic.Name, err)
+		return
+	}
+
+	topicState.LastStatus = scheduler.StatusSuccess
+	topicState.LastOutput = outputPath
+	d.logf("daemon.done", topic.Name, outputPath, time.Since(start).Round(100*time.Millisecond))
+}
+
+// searchAndSave 検索して結果を日付ごとのディレクトリに保存
+func (d *DaemonHandler) searchAndSave(ctx context.Context, topic *scheduler.Topic, start time.Time) (string, error) {
+	renderer, err := NewRenderer(topic.Format)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := d.searchHandler.Search(ctx, topic.Query)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(d.schedule.OutputDir, start.Format("2006-01-02"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("daemon.output_dir_failed"), err)
+	}
+
+	base := filepath.Join(dir, start.Format("1504")+"-"+slugify(topic.Name))
+	outputPath := base + FileExtension(topic.Format)
+	if err := writeRendered(outputPath, renderer, result); err != nil {
+		return "", err
+	}
+
+	if topic.Audio {
+		err := d.searchHandler.saveResultAudio(ctx, result, base+"."+d.searchHandler.AudioFormat())
+		switch {
+		case errors.Is(err, usage.ErrSpeechSkipped):
+			d.logf("daemon.speech_skipped", topic.Name, err)
+		case err != nil:
+			// 音声の失敗は検索結果の保存を無効にしない
+			d.logf("daemon.speech_failed", topic.Name, err)
+		}
+	}
+
+	return outputPath, nil
+}
+
+// nextWakeup 最も早く予定されている実行時刻を返す
+func (d *DaemonHandler) nextWakeup() time.Time {
+	var earliest time.Time
+	for _, topic := range d.schedule.Topics {
+		next := d.topicState(topic).NextRun
+		if next.IsZero() {
+			continue
+		}
+		if earliest.IsZero() || next.Before(earliest) {
+			earliest = next
+		}
+	}
+	if earliest.IsZero() {
+		return d.now().Add(daemonHeartbeat)
+	}
+	return earliest
+}
+
+// topicState トピックの状態を取得（なければ作成）
+func (d *DaemonHandler) topicState(topic *scheduler.Topic) *scheduler.TopicState {
+	topicState, ok := d.state.Topics[topic.Name]
+	if !ok {
+		topicState = &scheduler.TopicState{}
+		d.state.Topics[topic.Name] = topicState
+	}
+	topicState.Cron = topic.Cron
+	return topicState
+}
+
+// saveState 状態ファイルを更新（失敗してもデーモンは継続）
+func (d *DaemonHandler) saveState() {
+	d.state.UpdatedAt = d.now()
+	if err := d.state.Save(d.statePath); err != nil {
+		d.logf("daemon.state_save_failed", err)
+	}
+}
+
+// now スケジュールのタイムゾーンでの現在時刻
+func (d *DaemonHandler) now() time.Time {
+	return time.Now().In(d.schedule.Location())
+}
+
+// logf メッセージIDのメッセージを現在の言語で時刻付きで出力
+func (d *DaemonHandler) logf(id string, args ...interface{}) {
+	fmt.Fprintf(d.out, "[%s] %s\n", d.now().Format("2006-01-02 15:04:05"), i18n.T(id, args...))
+}
+
+// ShowDaemonStatus 状態ファイルからデーモンとトピックの実行状況を表示
+func ShowDaemonStatus(w io.Writer, statePath string) error {
+	state, err := scheduler.LoadState(statePath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("daemon.state_load_failed"), err)
+	}
+
+	if state.StartedAt.IsZero() {
+		fmt.Fprintln(w, i18n.T("daemon.status.none", statePath))
+		return nil
+	}
+
+	fmt.Fprintln(w, i18n.T("daemon.status.title"))
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	switch {
+	case !state.StoppedAt.IsZero():
+		fmt.Fprintln(w, i18n.T("daemon.status.stopped", state.StoppedAt.Format("2006-01-02 15:04:05")))
+	case time.Since(state.UpdatedAt) > daemonAliveWindow:
+		fmt.Fprintln(w, i18n.T("daemon.status.unresponsive", state.UpdatedAt.Format("2006-01-02 15:04:05"), state.PID))
+	default:
+		fmt.Fprintln(w, i18n.T("daemon.status.running", state.PID, state.StartedAt.Format("2006-01-02 15:04:05")))
+	}
+	fmt.Fprintln(w, i18n.T("daemon.status.schedule", state.ScheduleFile))
+
+	names := make([]string, 0, len(state.Topics))
+	for name := range state.Topics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		topicState := state.Topics[name]
+		fmt.Fprintf(w, "\n• %s [%s]\n", name, topicState.Cron)
+		if topicState.LastRun.IsZero() {
+			fmt.Fprintln(w, i18n.T("daemon.status.last", "-"))
+		} else {
+			status := "✅"
+			switch topicState.LastStatus {
+			case scheduler.StatusFailed:
+				status = "❌"
+			case scheduler.StatusRunning:
+				status = "⏳"
+			}
+			fmt.Fprintln(w, i18n.T("daemon.status.last", topicState.LastRun.Format("2006-01-02 15:04")+" "+status))
+		}
+		if topicState.LastError != "" {
+			fmt.Fprintln(w, i18n.T("daemon.status.error", topicState.LastError))
+		}
+		if topicState.LastOutput != "" {
+			fmt.Fprintln(w, i18n.T("daemon.status.output", topicState.LastOutput))
+		}
+		if !topicState.NextRun.IsZero() && state.StoppedAt.IsZero() {
+			fmt.Fprintln(w, i18n.T("daemon.status.next", topicState.NextRun.Format("2006-01-02 15:04")))
+		}
+		if topicState.Missed > 0 {
+			fmt.Fprintln(w, i18n.T("daemon.status.missed", topicState.Missed))
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"news_reporter/history"
+	"news_reporter/i18n"
+)
+
+type HistoryHandler struct {
+	store *history.Store
+	out   io.Writer
+}
+
+// NewHistoryHandler 新しい履歴ハンドラーを作成
+func NewHistoryHandler(store *history.Store) *HistoryHandler {
+	return &HistoryHandler{
+		store: store,
+		out:   os.Stdout,
+	}
+}
+
+// List 履歴を新しい順に一覧表示
+// filter が空でなければクエリに filter を含む履歴のみ、limit が正なら最大 limit 件を表示
+func (h *HistoryHandler) List(limit int, filter string) error {
+	entries, err := h.store.List()
+	if err != nil {
+		return fmt.Errorf("履歴の読み込みに失敗しました: %w", err)
+	}
+
+	var matched []history.Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if filter != "" && !strings.Contains(entries[i].Query, filter) {
+			continue
+		}
+		matched = append(matched, entries[i])
+		if limit > 0 && len(matched) >= limit {
+			break
+		}
+	}
+
+	if len(matched) == 0 {
+		fmt.Fprintln(h.out, i18n.T("history.empty"))
+		return nil
+	}
+
+	fmt.Fprintln(h.out, i18n.T("history.list", len(matched)))
+	fmt.Fprintln(h.out, strings.Repeat("=", 50))
+	for _, entry := range matched {
+		tokens := "-"
+		if entry.Usage != nil {
+			tokens = fmt.Sprint(entry.Usage.TotalTokens)
+		}
+		fmt.Fprintf(h.out, "%s  %s  🔗%2d  🪙%6s  %s\n",
+			entry.ID,
+			entry.Timestamp.Format("2006-01-02 15:04"),
+			len(entry.Results),
+			tokens,
+			truncate(entry.Query, 40),
+		)
+	}
+
+	return nil
+}
+
+// Show 履歴の詳細（メタデータと引用元）を表示
+func (h *HistoryHandler) Show(id string) error {
+	entry, err := h.store.Get(id)
+	if err != nil {
+		return fmt.Errorf("履歴の取得に失敗しました: %w", err)
+	}
+
+	fmt.Fprintln(h.out, i18n.T("history.entry", entry.ID))
+	fmt.Fprintln(h.out, strings.Repeat("=", 50))
+	fmt.Fprintln(h.out, i18n.T("history.query", entry.Query))
+	fmt.Fprintln(h.out, i18n.T("history.time", entry.Timestamp.Format("2006-01-02 15:04:05")))
+	if entry.Model != "" {
+		fmt.Fprintln(h.out, i18n.T("history.model", entry.Model))
+	}
+	if entry.Usage != nil {
+		fmt.Fprintln(h.out, i18n.T("history.tokens",
+			entry.Usage.InputTokens, entry.Usage.OutputTokens, entry.Usage.TotalTokens))
+	}
+	if entry.Cost != nil {
+		fmt.Fprintln(h.out, i18n.T("result.cost", entry.Cost.Total))
+	}
+
+	fmt.Fprintln(h.out, "\n"+i18n.T("result.citations", len(entry.Results)))
+	fmt.Fprintln(h.out, strings.Repeat("-", 30))
+	for i, result := range entry.Results {
+		fmt.Fprintf(h.out, "[%d] %s\n", i+1, result.Title)
+		fmt.Fprintf(h.out, "    🔗 %s\n", result.URL)
+	}
+
+	if entry.Summary != "" {
+		fmt.Fprintln(h.out, "\n"+i18n.T("history.summary"))
+		fmt.Fprintln(h.out, strings.Repeat("-", 30))
+		fmt.Fprintln(h.out, entry.Summary)
+	}
+	fmt.Fprintln(h.out, strings.Repeat("=", 50))
+
+	return nil
+}
+
+// Replay 保存された検索結果をAPIを呼ばずに指定形式で再出力
+func (h *HistoryHandler) Replay(id string, renderer Renderer) error {
+	entry, err := h.store.Get(id)
+	if err != nil {
+		return fmt.Errorf("履歴の取得に失敗しました: %w", err)
+	}
+
+	if err := renderer.Render(h.out, &entry.SearchResult); err != nil {
+		return fmt.Errorf("結果の出力に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// truncate 文字数が上限を超える場合に末尾を省略
+func truncate(text string, maxRunes int) string {
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxRunes-1]) + "…"
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"news_reporter/i18n"
+	"news_reporter/models"
+)
+
+// 出力形式
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+	FormatHTML     = "html"
+)
+
+// Renderer 検索結果を特定の出力形式で書き出す
+type Renderer interface {
+	Render(w io.Writer, result *models.SearchResult) error
+}
+
+// Formats 利用可能な出力形式の一覧を返す
+func Formats() []string {
+	return []string{FormatText, FormatJSON, FormatMarkdown, FormatCSV, FormatHTML}
+}
+
+// NewRenderer 出力形式名に対応するレンダラーを作成
+func NewRenderer(format string) (Renderer, error) {
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return &TextRenderer{Width: 80}, nil
+	case FormatJSON:
+		return &JSONRenderer{}, nil
+	case FormatMarkdown, "md":
+		return &MarkdownRenderer{}, nil
+	case FormatCSV:
+		return &CSVRenderer{}, nil
+	case FormatHTML:
+		return &HTMLRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (available: %s)", format, strings.Join(Formats(), ", "))
+	}
+}
+
+// FileExtension 出力形式に対応するファイル拡張子を返す
+func FileExtension(format string) string {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return ".json"
+	case FormatMarkdown, "md":
+		return ".md"
+	case FormatCSV:
+		return ".csv"
+	case FormatHTML:
+		return ".html"
+	default:
+		return ".txt"
+	}
+}
+
+// TextRenderer 端末向けの装飾付きテキスト出力
+type TextRenderer struct {
+	Width int
+}
+
+// Render 検索結果を表示
+func (r *TextRenderer) Render(w io.Writer, result *models.SearchResult) error {
+	fmt.Fprintln(w, i18n.T("result.header", result.Timestamp.Format("2006-01-02 15:04:05")))
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+
+	// Web検索結果を表示
+	if len(result.Results) > 0 {
+		fmt.Fprintln(w, "\n"+i18n.T("result.web_results", len(result.Results)))
+		fmt.Fprintln(w, strings.Repeat("-", 30))
+
+		for i, searchResult := range result.Results {
+			fmt.Fprintf(w, "\n%d. %s\n", i+1, searchResult.Title)
+			fmt.Fprintf(w, "   🔗 %s\n", searchResult.URL)
+			if searchResult.Snippet != "" {
+				// スニペットを適切な長さで改行
+				snippet := r.formatSnippet(searchResult.Snippet, r.Width)
+				fmt.Fprintf(w, "   📄 %s\n", snippet)
+			}
+		}
+	} else {
+		fmt.Fprintln(w, "\n"+i18n.T("result.no_results"))
+	}
+
+	// AI要約を表示
+	if result.Summary != "" {
+		fmt.Fprintln(w, "\n"+i18n.T("result.summary"))
+		fmt.Fprintln(w, strings.Repeat("-", 30))
+		summary := r.formatText(result.Summary, r.Width)
+		fmt.Fprintf(w, "%s\n", summary)
+	}
+
+	writeUsage(w, result)
+	_, err := fmt.Fprintln(w, strings.Repeat("=", 50))
+	return err
+}
+
+// writeUsage トークン数・Web検索の回数と見積もった費用を表示（使用量が不明な場合は何も表示しない）
+// キャッシュから取り出した結果は費用がかからないため、代わりに取得した時刻を表示する
+func writeUsage(w io.Writer, result *models.SearchResult) {
+	if result.Cached {
+		fmt.Fprintln(w, "\n"+i18n.T("result.cached", result.Timestamp.Format(i18n.T("layout.datetime"))))
+		return
+	}
+	if result.Usage == nil {
+		return
+	}
+	fmt.Fprintln(w, "\n"+i18n.T("result.usage", result.Usage.InputTokens, result.Usage.OutputTokens, result.Usage.WebSearchCalls))
+	if result.Cost != nil {
+		fmt.Fprintln(w, i18n.T("result.cost", result.Cost.Total))
+	}
+}
+
+// formatSnippet スニペットをフォーマット
+func (r *TextRenderer) formatSnippet(snippet string, maxWidth int) string {
+	if len(snippet) <= maxWidth {
+		return snippet
+	}
+
+	// 長いスニペットを適切に切り詰める
+	words := strings.Fields(snippet)
+	var result strings.Builder
+	currentLength := 0
+
+	for _, word := range words {
+		if currentLength+len(word)+1 > maxWidth-3 { // "..."を考慮
+			result.WriteString("...")
+			break
+		}
+
+		if currentLength > 0 {
+			result.WriteString(" ")
+			currentLength++
+		}
+
+		result.WriteString(word)
+		currentLength += len(word)
+	}
+
+	return result.String()
+}
+
+// formatText テキストを指定幅でフォーマット
+func (r *TextRenderer) formatText(text string, maxWidth int) string {
+	if len(text) <= maxWidth {
+		return text
+	}
+
+	var result strings.Builder
+	lines := strings.Split(text, "\n")
+
+	for _, line := range lines {
+		if len(line) <= maxWidth {
+			result.WriteString(line)
+			result.WriteString("\n")
+			continue
+		}
+
+		// 長い行を分割
+		words := strings.Fields(line)
+		currentLength := 0
+		var currentLine strings.Builder
+
+		for _, word := range words {
+			if currentLength+len(word)+1 > maxWidth && currentLength > 0 {
+				result.WriteString(currentLine.String())
+				result.WriteString("\n")
+				currentLine.Reset()
+				currentLength = 0
+			}
+
+			if currentLength > 0 {
+				currentLine.WriteString(" ")
+				currentLength++
+			}
+
+			currentLine.WriteString(word)
+			currentLength += len(word)
+		}
+
+		if currentLine.Len() > 0 {
+			result.WriteString(currentLine.String())
+			result.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSuffix(result.String(), "\n")
+}
+
+// JSONRenderer models.SearchResult をそのままJSONで出力
+type JSONRenderer struct{}
+
+// Render 検索結果をJSONで書き出す
+func (r *JSONRenderer) Render(w io.Writer, result *models.SearchResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(result)
+}
+
+// MarkdownRenderer 要約と引用元リンク一覧をMarkdownで出力
+type MarkdownRenderer struct{}
+
+// Render 検索結果をMarkdownで書き出す
+func (r *MarkdownRenderer) Render(w io.Writer, result *models.SearchResult) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", result.Query)
+	fmt.Fprintf(&b, "_%s_\n\n", i18n.T("result.fetched_at", result.Timestamp.Format("2006-01-02 15:04:05")))
+
+	b.WriteString("## " + i18n.T("result.summary_title") + "\n\n")
+	if result.Summary != "" {
+		b.WriteString(strings.TrimSpace(result.Summary))
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString(i18n.T("result.no_summary") + "\n\n")
+	}
+
+	b.WriteString("## " + i18n.T("result.sources_title") + "\n\n")
+	if len(result.Results) == 0 {
+		b.WriteString(i18n.T("result.no_sources") + "\n")
+	}
+	for i, searchResult := range result.Results {
+		title := searchResult.Title
+		if title == "" {
+			title = searchResult.URL
+		}
+		fmt.Fprintf(&b, "%d. [%s](%s)\n", i+1, escapeMarkdownLinkText(title), searchResult.URL)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeMarkdownLinkText リンクテキスト内の角括弧をエスケープ
+func escapeMarkdownLinkText(text string) string {
+	return strings.NewReplacer("[", "\\[", "]", "\\]").Replace(text)
+}
+
+// CSVRenderer Web検索結果を1行ずつCSVで出力
+type CSVRenderer struct{}
+
+// Render 検索結果をCSVで書き出す
+func (r *CSVRenderer) Render(w io.Writer, result *models.SearchResult) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"query", "timestamp", "rank", "title", "url", "snippet"}); err != nil {
+		return err
+	}
+
+	timestamp := result.Timestamp.Format(time.RFC3339)
+	for i, searchResult := range result.Results {
+		record := []string{
+			result.Query,
+			timestamp,
+			strconv.Itoa(i + 1),
+			searchResult.Title,
+			searchResult.URL,
+			searchResult.Snippet,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// HTMLRenderer 単体で閲覧できるHTMLページを出力
+type HTMLRenderer struct{}
+
+var htmlTemplate = template.Must(template.New("result").Funcs(template.FuncMap{
+	"t":    i18n.T,
+	"lang": i18n.Language,
+}).Parse(`<!DOCTYPE html>
+<html lang="{{lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Query}} - News Reporter</title>
+<style>
+body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.7; color: #222; }
+h1 { font-size: 1.5rem; }
+.timestamp { color: #666; font-size: 0.9rem; }
+.summary { white-space: pre-wrap; }
+ol li { margin-bottom: 0.5rem; }
+.url { color: #666; font-size: 0.85rem; word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>📰 {{.Query}}</h1>
+<p class="timestamp">{{t "result.fetched_at" (.Timestamp.Format "2006-01-02 15:04:05")}}</p>
+<h2>{{t "result.summary_title"}}</h2>
+{{if .Summary}}<div class="summary">{{.Summary}}</div>{{else}}<p>{{t "result.no_summary"}}</p>{{end}}
+<h2>{{t "result.sources_title"}}</h2>
+{{if .Results}}<ol>
+{{range .Results}}<li><a href="{{.URL}}">{{if .Title}}{{.Title}}{{else}}{{.URL}}{{end}}</a><br><span class="url">{{.URL}}</span></li>
+{{end}}</ol>{{else}}<p>{{t "result.no_sources"}}</p>{{end}}
+</body>
+</html>
+`))
+
+// Render 検索結果をHTMLで書き出す
+func (r *HTMLRenderer) Render(w io.Writer, result *models.SearchResult) error {
+	return htmlTemplate.Execute(w, result)
+}
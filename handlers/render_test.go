@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"news_reporter/models"
+)
+
+// renderResult 特殊文字を含む検索結果
+func renderResult() *models.SearchResult {
+	return &models.SearchResult{
+		Query:     `AI, "規制"`,
+		Summary:   "<script>alert(1)</script> 規制案 & 今後の予定",
+		Timestamp: time.Date(2024, 10, 14, 9, 30, 0, 0, time.UTC),
+		Results: []models.WebSearchResult{
+			{Title: "規制案, 公表へ", URL: "https://example.com/a?x=1&y=2", Snippet: "1行目\n2行目"},
+			{Title: `<b>"太字"</b> [速報]`, URL: "javascript:alert(1)"},
+		},
+	}
+}
+
+func TestCSVRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&CSVRenderer{}).Render(&buf, renderResult()); err != nil {
+		t.Fatal(err)
+	}
+
+	// カンマ・引用符・改行を含む値もCSVとして読み戻せる
+	out := buf.String()
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v\n%s", err, out)
+	}
+	want := [][]string{
+		{"query", "timestamp", "rank", "title", "url", "snippet"},
+		{`AI, "規制"`, "2024-10-14T09:30:00Z", "1", "規制案, 公表へ", "https://example.com/a?x=1&y=2", "1行目\n2行目"},
+		{`AI, "規制"`, "2024-10-14T09:30:00Z", "2", `<b>"太字"</b> [速報]`, "javascript:alert(1)", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q\nwant %q", records, want)
+	}
+	if !strings.Contains(out, `"AI, ""規制"""`) || !strings.Contains(out, "\"1行目\n2行目\"") {
+		t.Errorf("values are not quoted:\n%s", out)
+	}
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&HTMLRenderer{}).Render(&buf, renderResult()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"&lt;script&gt;alert(1)&lt;/script&gt; 規制案 &amp; 今後の予定",
+		"&lt;b&gt;&#34;太字&#34;&lt;/b&gt; [速報]",
+		"<title>AI, &#34;規制&#34; - News Reporter</title>",
+		`href="https://example.com/a?x=1&amp;y=2"`,
+		`href="#ZgotmplZ"`, // 危険なスキームのURLはリンクにしない
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"<script>", "<b>", `href="javascript:`} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %q unescaped:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&MarkdownRenderer{}).Render(&buf, renderResult()); err != nil {
+		t.Fatal(err)
+	}
+	if want := `2. [<b>"太字"</b> \[速報\]](javascript:alert(1))`; !strings.Contains(buf.String(), want) {
+		t.Errorf("output does not contain %q:\n%s", want, buf.String())
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	tests := []struct {
+		format    string
+		renderer  Renderer
+		extension string
+	}{
+		{"", &TextRenderer{Width: 80}, ".txt"},
+		{"text", &TextRenderer{Width: 80}, ".txt"},
+		{"JSON", &JSONRenderer{}, ".json"},
+		{"markdown", &MarkdownRenderer{}, ".md"},
+		{"md", &MarkdownRenderer{}, ".md"},
+		{"csv", &CSVRenderer{}, ".csv"},
+		{"Html", &HTMLRenderer{}, ".html"},
+	}
+	for _, tt := range tests {
+		renderer, err := NewRenderer(tt.format)
+		if err != nil {
+			t.Errorf("NewRenderer(%q): %v", tt.format, err)
+			continue
+		}
+		if !reflect.DeepEqual(renderer, tt.renderer) {
+			t.Errorf("NewRenderer(%q) = %#v, want %#v", tt.format, renderer, tt.renderer)
+		}
+		if got := FileExtension(tt.format); got != tt.extension {
+			t.Errorf("FileExtension(%q) = %q, want %q", tt.format, got, tt.extension)
+		}
+	}
+
+	// 未知の形式はエラーにし、利用可能な形式を示す（拡張子はテキストとして扱う）
+	renderer, err := NewRenderer("pdf")
+	if err == nil || !strings.Contains(err.Error(), `"pdf"`) || !strings.Contains(err.Error(), strings.Join(Formats(), ", ")) {
+		t.Errorf("NewRenderer(pdf) = %v, %v", renderer, err)
+	}
+	if got := FileExtension("pdf"); got != ".txt" {
+		t.Errorf("FileExtension(pdf) = %q, want .txt", got)
+	}
+}
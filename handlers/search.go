@@ -1,202 +1,417 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
 	"news_reporter/audio"
 	"news_reporter/client"
+	"news_reporter/history"
+	"news_reporter/i18n"
 	"news_reporter/models"
+	"news_reporter/usage"
 )
 
+// ErrNoSummary 音声化できる要約が存在しない（メッセージは表示するときの言語で返す）
+var ErrNoSummary error = noSummaryError{}
+
+type noSummaryError struct{}
+
+func (noSummaryError) Error() string {
+	return i18n.T("search.no_summary")
+}
+
 type SearchHandler struct {
-	openaiClient *client.OpenAIClient
-	ttsClient    *audio.TTSClient
+	searcher   client.Searcher
+	ttsClient  *audio.TTSClient
+	history    *history.Store
+	usage      *usage.Recorder
+	budget     *usage.Budget
+	normalizer *audio.SpeechNormalizer
+	renderer   Renderer
+	out        io.Writer // 検索結果の出力先
+	status     io.Writer // 進捗メッセージの出力先
 }
 
 // NewSearchHandler 新しい検索ハンドラーを作成
-func NewSearchHandler(openaiClient *client.OpenAIClient, ttsClient *audio.TTSClient) *SearchHandler {
-	return &SearchHandler{
-		openaiClient: openaiClient,
-		ttsClient:    ttsClient,
+func NewSearchHandler(searcher client.Searcher, ttsClient *audio.TTSClient) *SearchHandler {
+	h := &SearchHandler{
+		searcher:   searcher,
+		ttsClient:  ttsClient,
+		normalizer: audio.DefaultSpeechNormalizer(),
+		renderer:   &TextRenderer{Width: 80},
+		out:        os.Stdout,
+		status:     os.Stdout,
 	}
+	h.syncTTSStatus()
+	return h
 }
 
-// HandleSearch 検索を処理
-func (h *SearchHandler) HandleSearch(query string) error {
-	currentDate := time.Now().Format("2006年1月2日 15:04")
-	fmt.Printf("🔍 最新情報を検索中: %s (%s時点)\n", query, currentDate)
-	fmt.Println(strings.Repeat("-", 50))
+// syncTTSStatus 音声合成の進捗メッセージを検索の進捗メッセージと同じ出力先にする
+func (h *SearchHandler) syncTTSStatus() {
+	if h.ttsClient != nil {
+		h.ttsClient.SetStatus(h.status)
+	}
+}
 
-	// 検索を実行
-	result, err := h.openaiClient.Search(query)
-	if err != nil {
-		return fmt.Errorf("検索に失敗しました: %w", err)
+// SetRenderer 検索結果の出力形式を設定
+// テキスト以外の形式では標準出力を結果専用にするため、進捗メッセージを標準エラー出力に切り替える
+func (h *SearchHandler) SetRenderer(renderer Renderer) {
+	h.renderer = renderer
+	if _, ok := renderer.(*TextRenderer); ok {
+		h.status = h.out
+	} else {
+		h.status = os.Stderr
 	}
+	h.syncTTSStatus()
+}
 
-	// 結果を表示
-	h.displayResult(result)
+// SetHistory 検索結果を保存する履歴ストアを設定（nil の場合は保存しない）
+func (h *SearchHandler) SetHistory(store *history.Store) {
+	h.history = store
+}
 
-	return nil
+// SetUsage 使用量と費用を記録する Recorder を設定（nil の場合は記録しない）
+func (h *SearchHandler) SetUsage(recorder *usage.Recorder) {
+	h.usage = recorder
 }
 
-// HandleSearchWithAudio 検索と音声再生を処理
-func (h *SearchHandler) HandleSearchWithAudio(query string) error {
-	// 通常の検索を実行
-	if err := h.HandleSearch(query); err != nil {
-		return err
+// SetBudget 音声合成の前に確認する使用額の上限を設定（nil の場合は確認しない）
+func (h *SearchHandler) SetBudget(budget *usage.Budget) {
+	h.budget = budget
+}
+
+// SetSpeechNormalizer 音声合成前に要約へ適用する変換を設定（nil の場合は変換しない）
+func (h *SearchHandler) SetSpeechNormalizer(normalizer *audio.SpeechNormalizer) {
+	h.normalizer = normalizer
+}
+
+// speechText 要約を読み上げ用のテキストに変換
+func (h *SearchHandler) speechText(text string) string {
+	if h.normalizer == nil {
+		return text
 	}
+	return h.normalizer.Normalize(text)
+}
 
-	// 再度検索結果を取得（音声用）
-	result, err := h.openaiClient.Search(query)
+// Search 検索を実行して結果を返す（表示は行わない）
+// 中断された場合は途中までの結果（Partial）もエラーと一緒に返す。途中までの結果は履歴に保存しない
+func (h *SearchHandler) Search(ctx context.Context, query string) (*models.SearchResult, error) {
+	result, err := h.searcher.Search(ctx, query)
 	if err != nil {
-		return fmt.Errorf("音声用検索に失敗しました: %w", err)
+		return result, fmt.Errorf("%s: %w", i18n.T("search.failed"), err)
 	}
+	h.record(result)
+	return result, nil
+}
 
-	// 音声再生確認
-	fmt.Println("\n🎵 音声で要約を再生しますか？ (y/N): ")
-	var response string
-	fmt.Scanln(&response)
+// record 検索結果を履歴に保存し、使用量を記録（保存の失敗は検索結果に影響させない）
+func (h *SearchHandler) record(result *models.SearchResult) {
+	h.recordUsage(usage.KindSearch, result.Query, result)
+	if h.history == nil {
+		return
+	}
+	if _, err := h.history.Append(result); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("search.history_failed", err))
+	}
+}
 
-	if strings.ToLower(response) == "y" || strings.ToLower(response) == "yes" {
-		if result.Summary != "" {
-			// 要約を音声で再生
-			if err := h.ttsClient.SynthesizeAndPlay(result.Summary); err != nil {
-				fmt.Printf("⚠️  音声再生エラー: %v\n", err)
-				return nil // 音声再生エラーは致命的ではない
-			}
-			fmt.Println("✅ 音声再生が完了しました！")
-		} else {
-			fmt.Println("⚠️  再生可能な要約がありません")
-		}
+// recordUsage 検索・生成の使用量を記録（記録の失敗は警告のみ）
+func (h *SearchHandler) recordUsage(kind, topic string, result *models.SearchResult) {
+	if h.usage == nil {
+		return
+	}
+	if err := h.usage.Record(kind, topic, result); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("usage.record_failed", err))
 	}
+}
 
-	return nil
+// recordSpeech 音声合成した文字数を記録（記録の失敗は警告のみ）
+func (h *SearchHandler) recordSpeech(topic, text string) {
+	if h.usage == nil {
+		return
+	}
+	if err := h.usage.RecordSpeech(topic, text); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("usage.record_failed", err))
+	}
 }
 
-// SaveAudioSummary 要約を音声ファイルとして保存
-func (h *SearchHandler) SaveAudioSummary(query, filename string) error {
-	// 検索を実行
-	result, err := h.openaiClient.Search(query)
-	if err != nil {
-		return fmt.Errorf("検索に失敗しました: %w", err)
+// checkSpeech 音声合成の前に使用額の上限を確認する
+// キャッシュされた音声は費用がかからないため確認せず、cached に true を返す
+func (h *SearchHandler) checkSpeech(text string) (cached bool, err error) {
+	if h.ttsClient.Cached(text) {
+		return true, nil
 	}
+	return false, h.budget.CheckSpeech()
+}
 
+// SynthesizeSummary 検索結果の要約を音声データに変換
+func (h *SearchHandler) SynthesizeSummary(ctx context.Context, result *models.SearchResult) ([]byte, error) {
 	if result.Summary == "" {
-		return fmt.Errorf("保存可能な要約がありません")
+		return nil, ErrNoSummary
 	}
 
-	// 音声ファイルを保存
-	return h.ttsClient.SaveToFile(result.Summary, filename)
-}
-
-// displayResult 検索結果を表示
-func (h *SearchHandler) displayResult(result *models.SearchResult) {
-	fmt.Printf("📊 最新検索結果 (%s取得)\n", result.Timestamp.Format("2006-01-02 15:04:05"))
-	fmt.Println(strings.Repeat("=", 50))
-
-	// Web検索結果を表示
-	if len(result.Results) > 0 {
-		fmt.Printf("\n🌐 最新Web検索結果 (%d件):\n", len(result.Results))
-		fmt.Println(strings.Repeat("-", 30))
-
-		for i, searchResult := range result.Results {
-			fmt.Printf("\n%d. %s\n", i+1, searchResult.Title)
-			fmt.Printf("   🔗 %s\n", searchResult.URL)
-			if searchResult.Snippet != "" {
-				// スニペットを適切な長さで改行
-				snippet := h.formatSnippet(searchResult.Snippet, 80)
-				fmt.Printf("   📄 %s\n", snippet)
-			}
-		}
-	} else {
-		fmt.Println("\n⚠️  最新のWeb検索結果が見つかりませんでした")
+	text := h.speechText(result.Summary)
+	cached, err := h.checkSpeech(text)
+	if err != nil {
+		return nil, err
 	}
+	return h.synthesize(ctx, result.Query, text, cached)
+}
 
-	// AI要約を表示
-	if result.Summary != "" {
-		fmt.Printf("\n🤖 最新情報AI要約:\n")
-		fmt.Println(strings.Repeat("-", 30))
-		summary := h.formatText(result.Summary, 80)
-		fmt.Printf("%s\n", summary)
+// synthesize checkSpeech で確認済みの読み上げテキストを音声データに変換
+// キャッシュされていなかった（費用がかかった）場合は使用量を記録する
+func (h *SearchHandler) synthesize(ctx context.Context, topic, text string, cached bool) ([]byte, error) {
+	audioData, err := h.ttsClient.Synthesize(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", i18n.T("search.speech_failed"), err)
+	}
+	if !cached {
+		h.recordSpeech(topic, text)
 	}
+	return audioData, nil
+}
+
+// AudioFormat SynthesizeSummary が返す音声データの形式（保存するファイルの拡張子に使う）
+func (h *SearchHandler) AudioFormat() string {
+	return h.ttsClient.Format()
+}
 
-	fmt.Println(strings.Repeat("=", 50))
+// AudioContentType SynthesizeSummary が返す音声データのMIMEタイプ
+func (h *SearchHandler) AudioContentType() string {
+	return h.ttsClient.ContentType()
 }
 
-// formatSnippet スニペットをフォーマット
-func (h *SearchHandler) formatSnippet(snippet string, maxWidth int) string {
-	if len(snippet) <= maxWidth {
-		return snippet
+// SearchOptions 検索パイプラインのオプション
+type SearchOptions struct {
+	Stream    bool   // 要約を受信しながら表示する
+	PlayAudio bool   // 表示後に確認のうえ要約を音声で再生する
+	SaveAudio string // 要約の音声を保存するファイル名（空の場合は保存しない）
+}
+
+// HandleSearch 検索を処理
+func (h *SearchHandler) HandleSearch(ctx context.Context, query string) error {
+	return h.Run(ctx, query, SearchOptions{})
+}
+
+// Run 検索 → 表示 → 音声保存 → 音声再生 の順に処理する
+// 検索は1回だけ行い、音声は表示した要約と同じ結果から1回だけ生成する
+// ctx がキャンセルされた場合は途中までの結果を表示し、ctx のエラーを含むエラーを返す
+func (h *SearchHandler) Run(ctx context.Context, query string, opts SearchOptions) error {
+	currentDate := time.Now().Format(i18n.T("layout.datetime"))
+	fmt.Fprintln(h.status, i18n.T("search.start", query, currentDate))
+	fmt.Fprintln(h.status, strings.Repeat("-", 50))
+
+	var result *models.SearchResult
+	var err error
+	if opts.Stream {
+		result, err = h.searchStreaming(ctx, query)
+	} else {
+		result, err = h.Search(ctx, query)
+		if err == nil {
+			err = h.displayResult(result)
+		}
+	}
+	if err != nil {
+		if result != nil && result.Partial {
+			h.displayPartial(result, opts.Stream)
+		}
+		return err
+	}
+
+	if opts.SaveAudio == "" && !opts.PlayAudio {
+		return nil
+	}
+	if result.Summary == "" {
+		if opts.SaveAudio != "" {
+			return ErrNoSummary
+		}
+		fmt.Fprintln(h.status, i18n.T("audio.no_summary"))
+		return nil
+	}
+	// 使用額の上限の確認は1回だけ行い、保存と再生で同じ音声を使う
+	text := h.speechText(result.Summary)
+	cached, err := h.checkSpeech(text)
+	if err != nil {
+		if !errors.Is(err, usage.ErrSpeechSkipped) {
+			return err
+		}
+		fmt.Fprintln(h.status, i18n.T("audio.budget_skipped", err))
+		return nil
 	}
 
-	// 長いスニペットを適切に切り詰める
-	words := strings.Fields(snippet)
-	var result strings.Builder
-	currentLength := 0
+	var audioData []byte
+	if opts.SaveAudio != "" {
+		fmt.Fprintln(h.status, i18n.T("audio.generating_file", opts.SaveAudio))
+		audioData, err = h.synthesize(ctx, result.Query, text, cached)
+		if err != nil {
+			return err
+		}
+		if err := h.ttsClient.WriteFile(audioData, opts.SaveAudio); err != nil {
+			return err
+		}
+	}
 
-	for _, word := range words {
-		if currentLength+len(word)+1 > maxWidth-3 { // "..."を考慮
-			result.WriteString("...")
-			break
+	if opts.PlayAudio {
+		// 音声再生確認（入力待ちの間に中断された場合は中断として返す）
+		if !h.confirm(ctx, "\n"+i18n.T("audio.confirm")) {
+			return ctx.Err()
 		}
 
-		if currentLength > 0 {
-			result.WriteString(" ")
-			currentLength++
+		if audioData == nil {
+			fmt.Fprintln(h.status, i18n.T("audio.generating"))
+			audioData, err = h.synthesize(ctx, result.Query, text, cached)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				fmt.Fprintln(h.status, i18n.T("audio.play_error", err))
+				return nil // 音声再生エラーは致命的ではない
+			}
 		}
 
-		result.WriteString(word)
-		currentLength += len(word)
+		fmt.Fprintln(h.status, i18n.T("audio.playing"))
+		if err := h.ttsClient.Play(ctx, audioData); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Fprintln(h.status, i18n.T("audio.play_error", err))
+			return nil // 音声再生エラーは致命的ではない
+		}
+		fmt.Fprintln(h.status, i18n.T("audio.played"))
 	}
 
-	return result.String()
+	return nil
 }
 
-// formatText テキストを指定幅でフォーマット
-func (h *SearchHandler) formatText(text string, maxWidth int) string {
-	if len(text) <= maxWidth {
-		return text
+// searchStreaming 検索を実行し、要約と引用元を受信しながら表示
+func (h *SearchHandler) searchStreaming(ctx context.Context, query string) (*models.SearchResult, error) {
+	citationCount := 0
+	summaryStarted := false
+	result, err := h.searcher.SearchStream(ctx, query, func(event models.StreamEvent) {
+		switch event.Type {
+		case models.StreamEventWebSearch:
+			if event.Status == "searching" {
+				fmt.Fprintln(h.status, i18n.T("search.web_searching"))
+			}
+		case models.StreamEventDelta:
+			if !summaryStarted {
+				fmt.Fprintln(h.status, "\n"+i18n.T("result.summary"))
+				fmt.Fprintln(h.status, strings.Repeat("-", 30))
+				summaryStarted = true
+			}
+			fmt.Fprint(h.status, event.Delta)
+		case models.StreamEventCitation:
+			// 引用元は本文中に番号で示し、一覧は最後にまとめて表示
+			citationCount++
+			fmt.Fprintf(h.status, " [%d]", citationCount)
+		case models.StreamEventDone:
+			fmt.Fprintln(h.status)
+		}
+	})
+	if err != nil {
+		fmt.Fprintln(h.status)
+		return result, fmt.Errorf("%s: %w", i18n.T("search.failed"), err)
 	}
+	h.record(result)
 
-	var result strings.Builder
-	lines := strings.Split(text, "\n")
+	// テキスト以外の形式では最終結果を改めて出力
+	if _, ok := h.renderer.(*TextRenderer); !ok {
+		return result, h.displayResult(result)
+	}
 
-	for _, line := range lines {
-		if len(line) <= maxWidth {
-			result.WriteString(line)
-			result.WriteString("\n")
-			continue
-		}
+	h.displaySources(result)
 
-		// 長い行を分割
-		words := strings.Fields(line)
-		currentLength := 0
-		var currentLine strings.Builder
-
-		for _, word := range words {
-			if currentLength+len(word)+1 > maxWidth && currentLength > 0 {
-				result.WriteString(currentLine.String())
-				result.WriteString("\n")
-				currentLine.Reset()
-				currentLength = 0
-			}
+	return result, nil
+}
 
-			if currentLength > 0 {
-				currentLine.WriteString(" ")
-				currentLength++
-			}
+// confirm 確認メッセージを表示して y/yes が入力されたか判定（入力待ちの間に中断された場合は false）
+func (h *SearchHandler) confirm(ctx context.Context, message string) bool {
+	fmt.Fprintln(h.status, message)
+
+	answer := make(chan string, 1)
+	go func() {
+		var response string
+		fmt.Scanln(&response)
+		answer <- response
+	}()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case response := <-answer:
+		response = strings.ToLower(response)
+		return response == "y" || response == "yes"
+	}
+}
 
-			currentLine.WriteString(word)
-			currentLength += len(word)
-		}
+// saveResultAudio 検索済みの結果の要約を音声ファイルとして保存
+func (h *SearchHandler) saveResultAudio(ctx context.Context, result *models.SearchResult, filename string) error {
+	if result.Summary == "" {
+		return ErrNoSummary
+	}
 
-		if currentLine.Len() > 0 {
-			result.WriteString(currentLine.String())
-			result.WriteString("\n")
-		}
+	// 音声ファイルを保存
+	text := h.speechText(result.Summary)
+	cached, err := h.checkSpeech(text)
+	if err != nil {
+		return err
+	}
+	if err := h.ttsClient.SaveToFile(ctx, text, filename); err != nil {
+		return err
 	}
+	if !cached {
+		h.recordSpeech(result.Query, text)
+	}
+	return nil
+}
 
-	return strings.TrimSuffix(result.String(), "\n")
+// displayPartial 中断された検索の途中までの結果を表示
+// ストリーミング表示では要約は表示済みのため、テキスト形式では引用元の一覧だけを表示する
+func (h *SearchHandler) displayPartial(result *models.SearchResult, streamed bool) {
+	fmt.Fprintln(h.status, i18n.T("search.interrupted"))
+	if result.Summary == "" && len(result.Results) == 0 {
+		return
+	}
+	if _, ok := h.renderer.(*TextRenderer); ok && streamed {
+		h.displaySources(result)
+		return
+	}
+	if err := h.displayResult(result); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// displaySources ストリーミング表示後に引用元の一覧を表示
+func (h *SearchHandler) displaySources(result *models.SearchResult) {
+	fmt.Fprintln(h.status, strings.Repeat("=", 50))
+	fmt.Fprintln(h.status, i18n.T("result.header", result.Timestamp.Format("2006-01-02 15:04:05")))
+
+	if len(result.Results) == 0 {
+		fmt.Fprintln(h.status, "\n"+i18n.T("result.no_results"))
+		fmt.Fprintln(h.status, strings.Repeat("=", 50))
+		return
+	}
+
+	fmt.Fprintln(h.status, "\n"+i18n.T("result.citations", len(result.Results)))
+	fmt.Fprintln(h.status, strings.Repeat("-", 30))
+	for i, searchResult := range result.Results {
+		fmt.Fprintf(h.status, "[%d] %s\n", i+1, searchResult.Title)
+		fmt.Fprintf(h.status, "    🔗 %s\n", searchResult.URL)
+	}
+	writeUsage(h.status, result)
+	fmt.Fprintln(h.status, strings.Repeat("=", 50))
+}
+
+// displayResult 検索結果を設定された形式で出力
+func (h *SearchHandler) displayResult(result *models.SearchResult) error {
+	if err := h.renderer.Render(h.out, result); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("search.render_failed"), err)
+	}
+	return nil
 }
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"news_reporter/audio"
+	"news_reporter/client"
+	"news_reporter/config"
+	"news_reporter/mockserver"
+	"news_reporter/models"
+	"news_reporter/transport"
+	"news_reporter/usage"
+)
+
+const wantSummary = "今日のAI関連ニュースです。OpenAIが新しいモデルを発表しました。国内では生成AIの業務利用が広がっています。"
+
+// newReplayHandler testdata/fixtures/<name> の記録を再生する検索ハンドラーを作成（ネットワークには接続しない）
+func newReplayHandler(t *testing.T, name string) (*SearchHandler, *bytes.Buffer) {
+	t.Helper()
+	cfg := config.Default()
+	cfg.OpenAIAPIKey = "sk-test"
+	cfg.Voice = config.DefaultVoice
+	cfg.FixtureMode = config.FixtureReplay
+	cfg.FixtureDir = filepath.Join("testdata", "fixtures", name)
+	cfg.CacheDir = t.TempDir()
+
+	h := NewSearchHandler(client.NewSearcher(cfg, nil), audio.NewTTSClient(cfg))
+	out := &bytes.Buffer{}
+	h.out = out
+	h.status = out
+	return h, out
+}
+
+func TestSearchHandlerRun(t *testing.T) {
+	h, out := newReplayHandler(t, "search")
+	audioPath := filepath.Join(t.TempDir(), "summary.mp3")
+
+	if err := h.Run(context.Background(), "今日のAIニュース", SearchOptions{SaveAudio: audioPath}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	for _, want := range []string{"今日のAIニュース", "OpenAIが新しいモデルを発表しました", "OpenAI、新モデルを発表", "https://example.jp/genai-business"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+
+	// 保存した音声は記録した /audio/speech のレスポンスと同じ
+	saved, err := os.ReadFile(audioPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures, err := transport.LoadFixtures(filepath.Join("testdata", "fixtures", "search"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recorded []byte
+	for _, f := range fixtures {
+		if f.Path == "/v1/audio/speech" {
+			recorded = f.ResponseBody()
+		}
+	}
+	if len(recorded) == 0 || !bytes.Equal(saved, recorded) {
+		t.Errorf("saved audio (%d bytes) differs from the recorded response (%d bytes)", len(saved), len(recorded))
+	}
+}
+
+func TestSearchHandlerRunStream(t *testing.T) {
+	h, out := newReplayHandler(t, "search")
+
+	if err := h.Run(context.Background(), "今日のAIニュース", SearchOptions{Stream: true}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// 要約は受信しながら表示し、本文中の引用番号と最後の一覧で引用元を示す
+	output := out.String()
+	if !strings.Contains(output, "今日のAI関連ニュースです。OpenAIが新しいモデルを発表しました [1]") {
+		t.Errorf("streamed summary with citation marker not found:\n%s", output)
+	}
+	if !strings.Contains(output, "[2] 生成AIの業務利用が拡大") {
+		t.Errorf("citation list not found:\n%s", output)
+	}
+}
+
+func TestSearchHandlerRunJSON(t *testing.T) {
+	h, out := newReplayHandler(t, "search")
+	h.SetRenderer(&JSONRenderer{})
+	h.status = &bytes.Buffer{}
+
+	if err := h.Run(context.Background(), "今日のAIニュース", SearchOptions{}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var result models.SearchResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	if result.Summary != wantSummary {
+		t.Errorf("Summary = %q", result.Summary)
+	}
+	if len(result.Results) != 2 {
+		t.Errorf("len(Results) = %d, want 2", len(result.Results))
+	}
+	if result.Usage == nil || result.Usage.TotalTokens != 370 || result.Usage.WebSearchCalls != 1 {
+		t.Errorf("Usage = %+v", result.Usage)
+	}
+	if result.Cost == nil || result.Cost.Total <= result.Cost.WebSearch {
+		t.Errorf("Cost = %+v", result.Cost)
+	}
+}
+
+func TestSearchHandlerInvalidAPIKey(t *testing.T) {
+	h, _ := newReplayHandler(t, "invalid_key")
+
+	err := h.Run(context.Background(), "今日のAIニュース", SearchOptions{})
+	var apiErr *transport.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *transport.APIError", err)
+	}
+	if !apiErr.InvalidAPIKey() {
+		t.Errorf("InvalidAPIKey() = false for %v", apiErr)
+	}
+	if apiErr.RequestID != "req_fixture" {
+		t.Errorf("RequestID = %q", apiErr.RequestID)
+	}
+}
+
+func TestSynthesizeSummaryNoSummary(t *testing.T) {
+	h, _ := newReplayHandler(t, "search")
+	if _, err := h.SynthesizeSummary(context.Background(), &models.SearchResult{}); !errors.Is(err, ErrNoSummary) {
+		t.Fatalf("err = %v, want ErrNoSummary", err)
+	}
+}
+
+func TestBatchHandlerRun(t *testing.T) {
+	h, _ := newReplayHandler(t, "search")
+	batch := NewBatchHandler(h)
+	batch.out = &bytes.Buffer{}
+	dir := t.TempDir()
+
+	queries := []BatchQuery{{Query: "今日のAIニュース"}, {Query: "AI 規制", Name: "regulation"}}
+	results, err := batch.Run(context.Background(), queries, BatchOptions{Workers: 2, OutputDir: dir, Format: "markdown"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.Query.Query, result.Err)
+			continue
+		}
+		data, err := os.ReadFile(result.OutputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "[OpenAI、新モデルを発表](https://example.com/openai-model)") {
+			t.Errorf("%s does not contain the citation link:\n%s", result.OutputPath, data)
+		}
+	}
+	if want := filepath.Join(dir, "002-regulation.md"); results[1].OutputPath != want {
+		t.Errorf("OutputPath = %q, want %q", results[1].OutputPath, want)
+	}
+}
+
+func TestSearchHandlerCache(t *testing.T) {
+	mock := mockserver.New(mockserver.Options{})
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.BaseURL = server.URL + "/v1"
+	cfg.Voice = config.DefaultVoice
+	cfg.CacheDir = t.TempDir()
+	cfg.UsagePath = filepath.Join(t.TempDir(), "usage.jsonl")
+
+	newHandler := func(mode string) *SearchHandler {
+		cfg.CacheMode = mode
+		h := NewSearchHandler(client.NewSearcher(cfg, nil), audio.NewTTSClient(cfg))
+		h.SetUsage(usage.NewRecorder(cfg, usage.NewLedger(cfg.UsagePath)))
+		return h
+	}
+	count := func(path string) int {
+		n := 0
+		for _, r := range mock.Requests() {
+			if r.Path == path {
+				n++
+			}
+		}
+		return n
+	}
+
+	h := newHandler(config.CacheModeDefault)
+	first, err := h.Search(context.Background(), "AI ニュース")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.SynthesizeSummary(context.Background(), first); err != nil {
+		t.Fatal(err)
+	}
+
+	// 大文字・小文字や空白だけが違うクエリは、別のプロセスでもディスクのキャッシュを使う
+	h = newHandler(config.CacheModeDefault)
+	second, err := h.Search(context.Background(), "  ai　ニュース ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.Cached || second.Summary != first.Summary || second.Query != "  ai　ニュース " {
+		t.Errorf("second result = %+v", second)
+	}
+	if _, err := h.SynthesizeSummary(context.Background(), second); err != nil {
+		t.Fatal(err)
+	}
+	if n := count("/v1/responses"); n != 1 {
+		t.Errorf("searched %d times, want 1", n)
+	}
+	if n := count("/v1/audio/speech"); n != 1 {
+		t.Errorf("synthesized %d times, want 1", n)
+	}
+
+	// キャッシュを使った検索・音声合成は使用量に記録しない
+	entries, err := usage.NewLedger(cfg.UsagePath).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("recorded %d usage entries, want 2: %+v", len(entries), entries)
+	}
+
+	// --refresh は取得し直し、--no-cache はキャッシュを使わない
+	for _, mode := range []string{config.CacheModeRefresh, config.CacheModeOff} {
+		result, err := newHandler(mode).Search(context.Background(), "AI ニュース")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Cached {
+			t.Errorf("result is cached with mode %q", mode)
+		}
+	}
+	if n := count("/v1/responses"); n != 3 {
+		t.Errorf("searched %d times, want 3", n)
+	}
+}
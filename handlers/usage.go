@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"news_reporter/usage"
+)
+
+type UsageHandler struct {
+	ledger *usage.Ledger
+	out    io.Writer
+}
+
+// NewUsageHandler 新しい使用量ハンドラーを作成
+func NewUsageHandler(ledger *usage.Ledger) *UsageHandler {
+	return &UsageHandler{
+		ledger: ledger,
+		out:    os.Stdout,
+	}
+}
+
+// UsageReportOptions 使用量の集計のオプション
+type UsageReportOptions struct {
+	Group  string       // 集計の単位（day, month, topic, profile）
+	Filter usage.Filter // 集計の対象にする期間・プロファイル
+	Format string       // 出力形式（text, json, csv）
+}
+
+// usageGroupLabels 集計の単位ごとの見出し
+var usageGroupLabels = map[string]string{
+	usage.GroupDay:     "日付",
+	usage.GroupMonth:   "月",
+	usage.GroupTopic:   "トピック",
+	usage.GroupProfile: "プロファイル",
+}
+
+// Report 記録された使用量を集計して表示
+func (h *UsageHandler) Report(opts UsageReportOptions) error {
+	entries, err := h.ledger.List()
+	if err != nil {
+		return fmt.Errorf("使用量の読み込みに失敗しました: %w", err)
+	}
+	rows, total, err := usage.Summarize(entries, opts.Group, opts.Filter, nil)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(opts.Format) {
+	case "", FormatText:
+		h.writeText(opts.Group, rows, total)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(h.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Group string      `json:"group"`
+			Rows  []usage.Row `json:"rows"`
+			Total usage.Row   `json:"total"`
+		}{opts.Group, rows, total})
+	case FormatCSV:
+		return writeUsageCSV(h.out, rows)
+	default:
+		return fmt.Errorf("unknown output format %q (available: text, json, csv)", opts.Format)
+	}
+}
+
+// writeText 集計を表形式で表示（キーは長さがまちまちなため行末に置く）
+func (h *UsageHandler) writeText(group string, rows []usage.Row, total usage.Row) {
+	if len(rows) == 0 {
+		fmt.Fprintln(h.out, "📭 記録された使用量はありません")
+		return
+	}
+
+	label := usageGroupLabels[group]
+	fmt.Fprintf(h.out, "💰 使用量と概算費用（%s別・%d件）\n", label, len(rows))
+	fmt.Fprintln(h.out, strings.Repeat("=", 78))
+	fmt.Fprintf(h.out, "%s %s %s %s %s %s %s  %s\n",
+		padLeft("費用(USD)", 10), padLeft("検索", 5), padLeft("音声", 5), padLeft("入力", 10),
+		padLeft("出力", 10), padLeft("Web検索", 7), padLeft("文字数", 8), label)
+	fmt.Fprintln(h.out, strings.Repeat("-", 78))
+	for _, row := range rows {
+		h.writeRow(row, truncate(row.Key, 40))
+	}
+	fmt.Fprintln(h.out, strings.Repeat("-", 78))
+	h.writeRow(total, "合計")
+	fmt.Fprintln(h.out, strings.Repeat("=", 78))
+
+	if total.Unpriced > 0 {
+		fmt.Fprintf(h.out, "⚠️  料金表にないモデルの記録 %d 件は費用に含まれていません（設定ファイルの pricing で単価を指定できます）\n", total.Unpriced)
+	}
+}
+
+// padLeft 全角文字を2桁として width 桁に右寄せ
+func padLeft(text string, width int) string {
+	n := 0
+	for _, r := range text {
+		if r >= 0x1100 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	if n >= width {
+		return text
+	}
+	return strings.Repeat(" ", width-n) + text
+}
+
+// writeRow 集計の1行を表示
+func (h *UsageHandler) writeRow(row usage.Row, key string) {
+	fmt.Fprintf(h.out, "%10s %5d %5d %10d %10d %7d %8d  %s\n",
+		fmt.Sprintf("$%.4f", row.Cost),
+		row.Searches,
+		row.Speeches,
+		row.InputTokens,
+		row.OutputTokens,
+		row.WebSearchCalls,
+		row.Characters,
+		key,
+	)
+}
+
+// writeUsageCSV 集計をCSVで出力（表計算ソフトでの月次報告用）
+func writeUsageCSV(w io.Writer, rows []usage.Row) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"key", "searches", "speeches", "input_tokens", "output_tokens", "web_search_calls", "characters", "cost_usd", "unpriced"})
+	for _, row := range rows {
+		writer.Write([]string{
+			row.Key,
+			strconv.Itoa(row.Searches),
+			strconv.Itoa(row.Speeches),
+			strconv.Itoa(row.InputTokens),
+			strconv.Itoa(row.OutputTokens),
+			strconv.Itoa(row.WebSearchCalls),
+			strconv.Itoa(row.Characters),
+			strconv.FormatFloat(row.Cost, 'f', 6, 64),
+			strconv.Itoa(row.Unpriced),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
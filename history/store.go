@@ -0,0 +1,159 @@
+package history
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"news_reporter/i18n"
+	"news_reporter/models"
+)
+
+// maxEntrySize 1エントリ（1行）の最大サイズ
+const maxEntrySize = 4 * 1024 * 1024
+
+// ErrNotFound 指定したIDの履歴が存在しない
+var ErrNotFound = errors.New("history entry not found")
+
+// Entry 保存された検索結果
+type Entry struct {
+	ID string `json:"id"`
+	models.SearchResult
+}
+
+// Store 追記専用のJSONLファイルに検索履歴を保存する
+type Store struct {
+	path     string
+	mu       sync.Mutex
+	warnings io.Writer // 壊れた行を読み飛ばしたときの警告の出力先
+}
+
+// NewStore 新しい履歴ストアを作成
+func NewStore(path string) *Store {
+	return &Store{path: path, warnings: os.Stderr}
+}
+
+// Path 履歴ファイルのパスを返す
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append 検索結果を履歴に追記
+func (s *Store) Append(result *models.SearchResult) (*Entry, error) {
+	id, err := newID(result)
+	if err != nil {
+		return nil, err
+	}
+	entry := &Entry{ID: id, SearchResult: *result}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal history entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(line); err != nil {
+		return nil, fmt.Errorf("failed to write history entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// List 保存されている履歴を古い順に返す
+// 書き込み中の中断などで壊れた行は警告して読み飛ばす
+func (s *Store) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEntrySize)
+
+	var entries []Entry
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			fmt.Fprintln(s.warnings, i18n.T("history.invalid_entry", s.path, lineNumber, err))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Get IDまたはIDの前方一致で履歴を取得
+func (s *Store) Get(id string) (*Entry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []Entry
+	for _, entry := range entries {
+		if entry.ID == id {
+			return &entry, nil
+		}
+		if strings.HasPrefix(entry.ID, id) {
+			matched = append(matched, entry)
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	case 1:
+		return &matched[0], nil
+	default:
+		return nil, fmt.Errorf("ambiguous history id %q matches %d entries", id, len(matched))
+	}
+}
+
+// newID 検索日時と乱数から並べ替え可能なIDを生成
+func newID(result *models.SearchResult) (string, error) {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate history id: %w", err)
+	}
+	return result.Timestamp.Format("20060102-150405") + "-" + hex.EncodeToString(suffix), nil
+}
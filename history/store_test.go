@@ -0,0 +1,62 @@
+package history
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"news_reporter/models"
+)
+
+func TestStoreListSkipsCorruptEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store := NewStore(path)
+	warnings := &bytes.Buffer{}
+	store.warnings = warnings
+
+	first, err := store.Append(&models.SearchResult{Query: "AI", Summary: "要約1", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 壊れた行と、書き込み中に中断された行
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString("not json\n\n{\"id\":\"20240101-000000-abcdef\",\"query\":\"途中\n"); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	second, err := store.Append(&models.SearchResult{Query: "経済", Summary: "要約2", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != first.ID || entries[1].ID != second.ID {
+		t.Errorf("entries = %+v, want the two valid entries", entries)
+	}
+	for _, want := range []string{path + ":2", path + ":4"} {
+		if !strings.Contains(warnings.String(), want) {
+			t.Errorf("warnings do not mention %s:\n%s", want, warnings)
+		}
+	}
+
+	// 壊れた行があっても他の履歴は取得できる
+	if entry, err := store.Get(second.ID[:len(second.ID)-2]); err != nil || entry.Query != "経済" {
+		t.Errorf("Get = %+v, %v", entry, err)
+	}
+}
+
+func TestStoreListMissingFile(t *testing.T) {
+	entries, err := NewStore(filepath.Join(t.TempDir(), "history.jsonl")).List()
+	if err != nil || entries != nil {
+		t.Errorf("List = %v, %v, want no entries", entries, err)
+	}
+}
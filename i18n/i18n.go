@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 対応言語
+const (
+	Japanese = "ja"
+	English  = "en"
+	Chinese  = "zh"
+	Korean   = "ko"
+)
+
+// fallbackLanguage 翻訳がない言語・メッセージに使う言語
+const fallbackLanguage = English
+
+// catalogs 言語ごとのメッセージ
+var catalogs = map[string]map[string]string{
+	Japanese: messagesJa,
+	English:  messagesEn,
+	Chinese:  messagesZh,
+	Korean:   messagesKo,
+}
+
+var (
+	mu      sync.RWMutex
+	current = Japanese
+)
+
+// Languages メッセージが用意されている言語コードの一覧
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Supported メッセージが用意されている言語か判定
+func Supported(lang string) bool {
+	_, ok := catalogs[Normalize(lang)]
+	return ok
+}
+
+// Normalize "en-US" や "ko_KR.UTF-8" のような指定を言語コードに揃える
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_."); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// SetLanguage CLIのメッセージに使う言語を設定
+func SetLanguage(lang string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if lang = Normalize(lang); lang != "" {
+		current = lang
+	}
+}
+
+// Language 現在の言語コード
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T 現在の言語でメッセージを取得し、引数があれば書式化する
+func T(id string, args ...interface{}) string {
+	return Lookup(Language(), id, args...)
+}
+
+// Lookup 指定した言語でメッセージを取得する
+// 翻訳がない場合は英語、日本語の順に探し、どれにもなければIDをそのまま返す
+func Lookup(lang, id string, args ...interface{}) string {
+	message, ok := find(Normalize(lang), id)
+	if !ok {
+		return id
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Plural 数に応じて単数形（id.one）と複数形（id）を切り替える
+// 単数形は複数形と同じ言語のメッセージにある場合だけ使う
+func Plural(lang, id string, n int) string {
+	found := resolve(Normalize(lang), id)
+	if n == 1 {
+		if message, ok := catalogs[found][id+".one"]; ok {
+			return fmt.Sprintf(message, n)
+		}
+	}
+	return Lookup(lang, id, n)
+}
+
+// find 言語のフォールバックをたどってメッセージを探す
+func find(lang, id string) (string, bool) {
+	message, ok := catalogs[resolve(lang, id)][id]
+	return message, ok
+}
+
+// resolve メッセージが見つかる言語を返す（どこにもなければ空）
+func resolve(lang, id string) string {
+	for _, candidate := range []string{lang, fallbackLanguage, Japanese} {
+		if _, ok := catalogs[candidate][id]; ok {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// formatVerb 書式指定子（%% を除く。%[2]s のような引数の位置指定も含む）
+var formatVerb = regexp.MustCompile(`%(?:\[\d+\])?[-+# 0]*\d*(?:\.\d+)?[a-zA-Z%]`)
+
+// verbs メッセージの書式指定子の種類を引数の順に返す
+func verbs(message string) []string {
+	var found []string
+	next := 0
+	for _, verb := range formatVerb.FindAllString(message, -1) {
+		if verb == "%%" {
+			continue
+		}
+		position := next
+		if strings.HasPrefix(verb, "%[") {
+			n := 0
+			for _, r := range verb[2:strings.Index(verb, "]")] {
+				n = n*10 + int(r-'0')
+			}
+			position = n - 1
+		}
+		for len(found) <= position {
+			found = append(found, "")
+		}
+		found[position] = verb[len(verb)-1:]
+		next = position + 1
+	}
+	return found
+}
+
+// baseKeys 単数形（.one）を除いたメッセージIDの一覧
+func baseKeys(messages map[string]string) []string {
+	var keys []string
+	for key := range messages {
+		if !strings.HasSuffix(key, ".one") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestCatalogParity(t *testing.T) {
+	want := baseKeys(messagesJa)
+	for lang, messages := range catalogs {
+		got := baseKeys(messages)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: keys differ from ja\nmissing: %v\nextra:   %v", lang, difference(want, got), difference(got, want))
+		}
+	}
+}
+
+func TestCatalogFormatVerbs(t *testing.T) {
+	for lang, messages := range catalogs {
+		for key, message := range messages {
+			base, ok := messagesJa[strings.TrimSuffix(key, ".one")]
+			if !ok || lang == Japanese {
+				continue
+			}
+			if got, want := verbs(message), verbs(base); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s %s: format verbs %v, want %v as in ja", lang, key, got, want)
+			}
+		}
+	}
+}
+
+func TestLookupFallback(t *testing.T) {
+	if got := Lookup("fr", "main.done"); got != messagesEn["main.done"] {
+		t.Errorf("fr main.done = %q, want the English message", got)
+	}
+	if got := Lookup("en-US", "daemon.stopped"); got != messagesEn["daemon.stopped"] {
+		t.Errorf("en-US daemon.stopped = %q", got)
+	}
+	if got := Lookup("ko", "no.such.message"); got != "no.such.message" {
+		t.Errorf("unknown message = %q, want the ID", got)
+	}
+	if got := Lookup("en", "daemon.skipped_late", "news", "09:00"); got != "⏭️  Skipped the 09:00 run of news because it is too late" {
+		t.Errorf("indexed arguments = %q", got)
+	}
+}
+
+// difference a にあって b にないキー
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, key := range b {
+		in[key] = true
+	}
+	var diff []string
+	for _, key := range a {
+		if !in[key] {
+			diff = append(diff, key)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,335 @@
+package i18n
+
+// messagesEn 英語のメッセージ。翻訳のない言語でもこれを使う
+var messagesEn = map[string]string{
+	"layout.date":            "January 2, 2006",
+	"layout.datetime":        "January 2, 2006 15:04",
+	"period.day.one":         "%d day",
+	"period.day":             "%d days",
+	"period.week.one":        "%d week",
+	"period.week":            "%d weeks",
+	"period.month.one":       "%d month",
+	"period.month":           "%d months",
+	"search.start":           "🔍 Searching the latest news: %s (as of %s)",
+	"search.web_searching":   "🔎 Running web search...",
+	"search.history_failed":  "⚠️  Failed to save history: %v",
+	"search.interrupted":     "⚠️  Interrupted (showing what was received so far)",
+	"result.header":          "📊 Latest results (retrieved %s)",
+	"result.web_results":     "🌐 Web search results (%d):",
+	"result.no_results":      "⚠️  No recent web search results were found",
+	"result.summary":         "🤖 AI summary:",
+	"result.citations":       "🌐 Sources (%d):",
+	"result.fetched_at":      "Retrieved %s",
+	"result.summary_title":   "Summary",
+	"result.no_summary":      "No summary available.",
+	"result.sources_title":   "Sources",
+	"result.no_sources":      "No sources.",
+	"result.usage":           "🪙 Tokens: input %d / output %d · web searches %d",
+	"result.cost":            "💰 Estimated cost: $%.4f",
+	"result.cached":          "♻️  Showing a cached result fetched at %s (no cost; use --refresh to fetch again)",
+	"usage.record_failed":    "⚠️  Failed to record usage: %v",
+	"audio.no_summary":       "⚠️  There is no summary to play",
+	"audio.confirm":          "🎵 Play the summary as audio? (y/N): ",
+	"audio.generating":       "🎵 Generating audio...",
+	"audio.generating_file":  "🎵 Generating audio file: %s",
+	"audio.chunked":          "🧩 Long text: generating audio in %d parts",
+	"audio.playing":          "🔊 Playing audio...",
+	"audio.played":           "✅ Playback finished!",
+	"audio.play_error":       "⚠️  Audio playback error: %v",
+	"audio.saved":            "✅ Saved audio file: %s",
+	"audio.budget_skipped":   "💸 %v",
+	"budget.daily":           "spent $%.4f of the $%.2f daily cap for all profiles",
+	"budget.monthly":         "spent $%.4f of the $%.2f monthly cap for all profiles",
+	"budget.profile_daily":   "spent $%.4f of the profile's $%.2f daily cap",
+	"budget.profile_monthly": "spent $%.4f of the profile's $%.2f monthly cap",
+	"budget.exceeded":        "stopped because the spending cap has been reached (profile %s: %s)",
+	"budget.speech_skipped":  "skipped speech synthesis because the spending cap is near (%s)",
+	"budget.fallback":        "⚠️  %.0f%% of the spending cap used, switching the model to %s",
+	"budget.check_failed":    "failed to check the spending cap",
+	"batch.start":            "📦 Starting batch search: %d queries (workers: %d)",
+	"batch.summary":          "📊 Batch results: %d succeeded / %d failed",
+	"briefing.default_title": "News Reporter Daily Briefing",
+	"briefing.start":         "🎙️  Creating briefing: %s (%d topics)",
+	"briefing.topic_done":    "✅ %s (%d sources)",
+	"briefing.writing":       "✍️  Writing the script...",
+	"briefing.script":        "📜 Script:",
+	"briefing.script_saved":  "📝 Saved script: %s",
+	"briefing.input.title":   "Show title: %s",
+	"briefing.input.date":    "Date: %s",
+	"briefing.input.topics":  "Number of topics: %d",
+	"briefing.input.topic":   "=== Topic %d: %s ===",
+	"briefing.done":          "✅ Briefing created!",
+	"briefing.input.sources": "Sources:",
+	"main.no_query":          "❌ Error: no search query given",
+	"main.empty_query":       "❌ Error: the search query is empty",
+	"main.done":              "✅ Done!",
+	"main.interrupted":       "🛑 Interrupted",
+
+	"main.config_error":            "❌ Configuration error: %v",
+	"main.error":                   "❌ Error: %v",
+	"main.missing_value":           "❌ Error: the %s option requires %s",
+	"main.unknown_option":          "❌ Error: unknown option: %s",
+	"main.invalid_interval":        "❌ Error: invalid interval: %s (e.g. %s)",
+	"main.invalid_count":           "❌ Error: invalid count: %s",
+	"main.invalid_workers":         "❌ Error: invalid number of workers: %s",
+	"main.invalid_month":           "❌ Error: invalid month: %s (e.g. 2024-01)",
+	"main.invalid_date":            "❌ Error: invalid date: %s (e.g. 2024-01-31)",
+	"main.invalid_citation":        "❌ Error: give citations as \"title|URL\": %s",
+	"main.history_command":         "❌ Error: specify a history subcommand (list, show, replay)",
+	"main.history_id":              "❌ Error: history %s takes exactly one history ID",
+	"main.unknown_history_command": "❌ Error: unknown history subcommand: %s",
+	"main.batch_file":              "❌ Error: batch takes exactly one query file",
+	"main.daemon_file":             "❌ Error: daemon takes exactly one schedule file",
+	"main.briefing_topics":         "❌ Error: briefing needs at least one topic",
+	"main.fixtures_command":        "❌ Error: specify a fixtures subcommand (serve)",
+	"main.fixtures_serving":        "🎞️  Serving fixtures from %s on http://%s",
+	"main.mockserver_start":        "🧪 Starting the mock server on http://%s (OPENAI_BASE_URL=http://%s/v1)",
+	"main.server_stopping":         "🛑 Stopping the server...",
+	"main.server_stop_error":       "⚠️  Server shutdown error: %v",
+
+	"option.value":     "a value",
+	"option.address":   "an address",
+	"option.directory": "a directory",
+	"option.file":      "a file name",
+	"option.text":      "text",
+	"option.citation":  "a citation",
+	"option.interval":  "an interval",
+	"option.api_key":   "an API key",
+	"option.count":     "a count",
+	"option.filter":    "a search string",
+	"option.format":    "an output format",
+	"option.group":     "a grouping unit",
+	"option.month":     "a month",
+	"option.date":      "a date",
+	"option.workers":   "a number of workers",
+	"option.title":     "a title",
+
+	"history.empty":         "📭 No search history saved",
+	"history.list":          "📚 Search history (%d)",
+	"history.entry":         "🗂️  History entry %s",
+	"history.query":         "🔍 Query: %s",
+	"history.time":          "🕒 Time:  %s",
+	"history.model":         "🧠 Model: %s",
+	"history.tokens":        "🪙 Tokens: input %d / output %d / total %d",
+	"history.summary":       "🤖 AI summary:",
+	"history.invalid_entry": "⚠️  Skipped a corrupt history entry (%s:%d): %v",
+
+	"daemon.status.none":         "📭 No daemon runs recorded (%s)",
+	"daemon.status.title":        "🗓️  Daemon status",
+	"daemon.status.stopped":      "⚪ Stopped (at %s)",
+	"daemon.status.unresponsive": "🔴 Not responding (last update: %s, PID: %d)",
+	"daemon.status.running":      "🟢 Running (PID: %d, started: %s)",
+	"daemon.status.schedule":     "📄 Schedule: %s",
+	"daemon.status.last":         "   Last run: %s",
+	"daemon.status.error":        "   Error: %s",
+	"daemon.status.output":       "   Output: %s",
+	"daemon.status.next":         "   Next run: %s",
+	"daemon.status.missed":       "   Skipped: %d",
+
+	"search.failed":        "search failed",
+	"search.speech_failed": "speech synthesis failed",
+	"search.render_failed": "failed to write the result",
+	"search.no_summary":    "there is no summary to save",
+	"search.incomplete":    "⚠️  The response ended early: %s",
+
+	"daemon.started":           "🗓️  Daemon started (topics: %d, output: %s)",
+	"daemon.topic":             "   • %s [%s] next run: %s",
+	"daemon.catch_up":          "⏪ Running a missed run: %s",
+	"daemon.stopped":           "🛑 Daemon stopped",
+	"daemon.skipped_late":      "⏭️  Skipped the %[2]s run of %[1]s because it is too late",
+	"daemon.skipped_overrun":   "⏭️  Skipped %[2]d run(s) of %[1]s because the previous run took too long",
+	"daemon.skipped_offline":   "⏭️  Skipped the runs of %s scheduled while stopped because they are outside the catch-up window",
+	"daemon.searching":         "🔍 Searching %s: %s",
+	"daemon.failed":            "❌ %s: %v",
+	"daemon.done":              "✅ %s → %s (%s)",
+	"daemon.speech_skipped":    "💸 Skipped audio for %s: %v",
+	"daemon.speech_failed":     "⚠️  Failed to save audio for %s: %v",
+	"daemon.state_load_failed": "failed to read the state file",
+	"daemon.state_save_failed": "⚠️  Failed to save the state file: %v",
+	"daemon.output_dir_failed": "cannot create the output directory",
+
+	"server.started":      "🌐 API server started: %s",
+	"server.start_failed": "failed to start the server",
+	"server.write_failed": "⚠️  Failed to write the response: %v",
+
+	"config.file_not_found":    "config file not found: %s",
+	"config.file_read":         "cannot read the config file",
+	"config.unknown_key":       "%s: unknown key: %s",
+	"config.file_format":       "unsupported config file format: %s (use .yaml, .yml or .toml)",
+	"config.template_conflict": "a template body and a template file (%s) cannot both be set",
+	"config.template_read":     "cannot read the template file",
+	"config.no_profiles":       "profile not found: %s (the config file defines no profiles)",
+	"config.profile_not_found": "profile not found: %s (available: %s)",
+	"config.profile":           "profile %s",
+	"config.invalid_value":     "invalid value for %s: %q",
+	"config.feed_urls":         "the feed backend needs at least one feed in feed.urls",
+	"config.feed_max_items":    "feed.max_items must be at least 1: %d",
+	"config.backend":           "unsupported search backend: %s (supported: %s)",
+	"config.temperature":       "temperature must be between 0 and 2: %g",
+	"config.speed":             "speed must be between 0.25 and 4.0: %g",
+	"config.audio_format":      "unsupported audio format: %s (supported: %s)",
+	"config.tts_command":       "the command backend needs a command to run in tts.command.run",
+	"config.voicevox_url":      "the voicevox backend needs tts.voicevox.url",
+	"config.voicevox_voice":    "VOICEVOX voices must be a numeric speaker ID: %s",
+	"config.tts_backend":       "unsupported speech backend: %s (supported: %s)",
+	"config.timeout":           "timeouts must be positive durations",
+	"config.fixture_mode":      "unsupported fixture mode: %s (supported: %s, %s)",
+	"config.fixture_dir":       "a fixture directory is required",
+	"config.max_retries":       "max_retries must be 0 or more: %d",
+	"config.max_retry_wait":    "max_retry_wait must be a positive duration",
+	"config.language":          "a language is required (e.g. %s)",
+	"config.recency_days":      "recency_days must be at least 1: %d",
+	"config.timezone":          "unknown time zone: %s",
+	"config.system_prompt":     "system prompt",
+	"config.query_template":    "query template",
+	"config.cache_ttl":         "cache lifetimes must be 0 or more",
+	"config.cache_mode":        "unsupported cache mode: %s",
+	"config.cache_dir":         "a cache directory is required",
+	"config.budget_limit":      "budget caps must be 0 or more",
+	"config.budget_warn_at":    "budget.warn_at must be between 0 and 1: %g",
+	"config.budget_pricing":    "set a price for model %s in pricing.models to use budget",
+	"config.web_search_price":  "pricing.web_search_per_call must be 0 or more: %g",
+	"config.model_price":       "pricing.models.%s prices must be 0 or more",
+	"config.speech_price":      "pricing.speech.%s must be 0 or more: %g",
+
+	"transport.retrying":       "⚠️  %s, retrying in %s (attempt %d)",
+	"transport.fixture_failed": "⚠️  Failed to save the fixture: %v",
+
+	"feed.fetch_failed": "⚠️  Failed to fetch feed %s: %v",
+	"feed.unavailable":  "no feed could be fetched",
+
+	"cache.result_failed": "⚠️  Failed to cache the search result: %v",
+	"cache.speech_failed": "⚠️  Failed to cache the speech: %v",
+
+	"hint.missing_key":     "💡 Hint: set the OPENAI_API_KEY environment variable\n   export OPENAI_API_KEY=\"your-api-key-here\"",
+	"hint.invalid_key":     "💡 Hint: the API key is invalid. Check OPENAI_API_KEY (or openai.api_key in the config file)\n   https://platform.openai.com/api-keys",
+	"hint.quota":           "💡 Hint: you have exceeded your quota. Check your billing settings and credit balance\n   https://platform.openai.com/settings/organization/billing",
+	"hint.model_not_found": "💡 Hint: model %q does not exist or is not available to this API key. Check --model or openai.model in the config file",
+	"hint.content_policy":  "💡 Hint: the request was rejected by the content policy. Try rephrasing the query",
+	"hint.rate_limited":    "💡 Hint: the rate limit did not clear. Try again later, or slow batch runs down with --workers and --interval",
+	"hint.budget":          "💡 Hint: the spending cap set under budget in the config file has been reached. Check the breakdown with the usage command and raise the cap if needed",
+
+	"help": `📰 News Reporter - latest news search
+==================================================
+Searches the latest news and information in real time
+using the OpenAI Responses API and web_search_preview.
+
+Usage:
+  go run main.go "query"
+  go run main.go [options] "query"
+  go run main.go serve [--addr :8080]
+
+Examples:
+  go run main.go --lang en "today's business news"
+  go run main.go --lang en "latest AI trends"
+  go run main.go --lang en --stream "today's headlines"
+  go run main.go --lang en --audio "today's headlines"
+  go run main.go --lang en --save summary.mp3 "AI news"
+  go run main.go --lang en --stream --audio --save summary.mp3 "AI news"
+  go run main.go --lang en --format json "AI news" > result.json
+  go run main.go --profile english "today's markets"
+
+Options:
+  -h, --help                show this help message
+  -a, --audio               play the summary as audio
+      --stream              print the summary while it is received
+  -f, --format <format>     output format (text, json, markdown, csv, html)
+      --no-history          do not save the result to history
+      --raw-speech          read the summary aloud without normalizing it
+  -s, --save <filename>     save the summary as an audio file
+
+Configuration options (all commands; override config file and environment):
+      --config <file>       config file (YAML/TOML)
+      --lang <lang>         language for messages, summaries and speech (ja, en, zh, ko, ...; default: ja)
+      --profile <name>      use a profile defined in the config file
+      --backend <name>      search backend (openai, feed; default: openai)
+      --model <model>       model for search and summaries (default: gpt-4o-mini)
+      --temperature <n>     summary temperature (0-2, default: 0.3)
+      --timeout <duration>  search timeout (default: 60s)
+      --tts-backend <name>  speech backend (openai, command, voicevox; default: openai)
+      --tts-model <model>   speech model (default: tts-1)
+      --voice <voice>       speech voice or VOICEVOX speaker ID (default: depends on the language)
+      --speed <n>           speech speed (0.25-4.0, default: 1.0)
+      --audio-format <fmt>  audio format (mp3, opus, aac, flac, wav, pcm)
+      --tts-timeout <dur>   speech timeout (default: 120s)
+      --no-cache            do not use cached search results or audio
+      --refresh             ignore the cache, fetch again and update the cache
+
+Subcommands:
+  serve                     start the REST API server
+    --addr <addr>           listen address (default: :8080)
+  history list              list search history
+    -n, --limit <n>         number of entries (default: 20)
+    -q, --query <text>      filter by query
+  history show <id>         show a history entry
+  history replay <id>       print a saved result again (accepts --format)
+  usage                     summarize recorded usage and estimated cost
+    --by <unit>             group by day, month, topic or profile (default: day)
+    --month <YYYY-MM>       only the given month
+    --since <YYYY-MM-DD>    from the given date
+    --until <YYYY-MM-DD>    up to and including the given date
+    --profile <name>        only the given profile
+    -f, --format <format>   output format (text, json, csv)
+  batch <file>              search every query in a file (.txt: one per line, .jsonl: {"query": ...})
+    -w, --workers <n>       parallel searches (default: 3)
+    --interval <duration>   minimum interval between searches (default: 1s)
+    -o, --out <dir>         output directory (default: batch-<datetime>)
+    -f, --format <format>   result file format (default: profile format or markdown)
+  daemon <schedule.json>    run scheduled searches
+  daemon status             show daemon status
+    --state <file>          state file (default: ~/.news_reporter/daemon_state.json)
+  briefing <topic>...       create one spoken briefing from several topics
+    -o, --out <file>        audio output (default: briefing-<date>.<audio format>)
+    --script <file>         script output (default: .txt next to the audio)
+    --title <title>         show title
+    --topics <file>         read topics from a file
+    -w, --workers <n>       topics searched in parallel (default: 3)
+  fixtures serve            start a stand-in server that replays recorded fixtures
+    --dir <dir>             fixture directory (default: ~/.news_reporter/fixtures)
+    --addr <addr>           listen address (default: 127.0.0.1:8089)
+  mockserver                start a mock OpenAI API server (try the CLI without keys or spend)
+    --addr <addr>           listen address (default: 127.0.0.1:8090)
+    --delta <text>          summary text to stream (repeat for several deltas)
+    --citation <title|url>  citation attached to the summary (repeatable)
+    --no-citations          attach no citations
+    --delay <duration>      delay between deltas (e.g. 50ms)
+    --api-key <key>         reject requests with any other key (401)
+
+Features:
+  ✅ real-time web search
+  ✅ summaries in Japanese, English, Chinese and Korean (--lang)
+  ✅ results with source URLs
+  ⚡ streaming output
+  📄 JSON/Markdown/CSV/HTML output
+  🎵 text-to-speech playback and MP3 export
+  🌐 REST API server
+  📚 search history
+  📦 batch search
+  🗓️  scheduled briefings (daemon)
+  🎙️  podcast-style briefings
+
+Audio:
+  • high-quality speech with OpenAI TTS
+  • Markdown, URLs and citation marks are removed before reading aloud
+  • --audio and --save can be combined (one search, one synthesis)
+  • long summaries are split by sentence, synthesized in parallel and joined
+
+REST API:
+  • POST /search        returns the result as JSON
+  • POST /search/audio  returns the summary as audio
+  • request body: {"query": "..."}
+
+History:
+  • results are saved to ~/.news_reporter/history.jsonl
+  • change the location with NEWS_REPORTER_HISTORY
+
+Config file:
+  • ~/.news_reporter/config.yaml (or .yml/.toml) is loaded automatically
+  • change the location with --config or NEWS_REPORTER_CONFIG
+  • the prompt section customizes the system prompt and query templates (text/template)
+  • profiles bundle model, prompt, language, voice, output format and sources
+  • precedence: command line > environment > profile > config file > defaults
+
+Note: the OPENAI_API_KEY environment variable is required
+`,
+}
@@ -0,0 +1,338 @@
+package i18n
+
+// messagesJa 日本語のメッセージ（元の言語）
+var messagesJa = map[string]string{
+	"layout.date":            "2006年1月2日",
+	"layout.datetime":        "2006年1月2日 15:04",
+	"period.day":             "%d日",
+	"period.week":            "%d週間",
+	"period.month":           "%dか月",
+	"search.start":           "🔍 最新情報を検索中: %s (%s時点)",
+	"search.web_searching":   "🔎 Web検索を実行中...",
+	"search.history_failed":  "⚠️  履歴の保存に失敗しました: %v",
+	"search.interrupted":     "⚠️  中断しました（ここまでに受信した内容です）",
+	"result.header":          "📊 最新検索結果 (%s取得)",
+	"result.web_results":     "🌐 最新Web検索結果 (%d件):",
+	"result.no_results":      "⚠️  最新のWeb検索結果が見つかりませんでした",
+	"result.summary":         "🤖 最新情報AI要約:",
+	"result.citations":       "🌐 引用元 (%d件):",
+	"result.fetched_at":      "%s取得",
+	"result.summary_title":   "要約",
+	"result.no_summary":      "要約はありません。",
+	"result.sources_title":   "引用元",
+	"result.no_sources":      "引用元はありません。",
+	"result.usage":           "🪙 トークン: 入力 %d / 出力 %d ・ Web検索 %d回",
+	"result.cost":            "💰 概算費用: $%.4f",
+	"result.cached":          "♻️  %s に取得したキャッシュを表示しています（費用はかかりません。--refresh で取得し直します）",
+	"usage.record_failed":    "⚠️  使用量の記録に失敗しました: %v",
+	"audio.no_summary":       "⚠️  再生可能な要約がありません",
+	"audio.confirm":          "🎵 音声で要約を再生しますか？ (y/N): ",
+	"audio.generating":       "🎵 音声を生成中...",
+	"audio.generating_file":  "🎵 音声ファイルを生成中: %s",
+	"audio.chunked":          "🧩 長文のため %d 個に分割して音声を生成します",
+	"audio.playing":          "🔊 音声を再生中...",
+	"audio.played":           "✅ 音声再生が完了しました！",
+	"audio.play_error":       "⚠️  音声再生エラー: %v",
+	"audio.saved":            "✅ 音声ファイルを保存しました: %s",
+	"audio.budget_skipped":   "💸 %v",
+	"budget.daily":           "全プロファイルの1日の使用額 $%.4f / 上限 $%.2f",
+	"budget.monthly":         "全プロファイルの1か月の使用額 $%.4f / 上限 $%.2f",
+	"budget.profile_daily":   "このプロファイルの1日の使用額 $%.4f / 上限 $%.2f",
+	"budget.profile_monthly": "このプロファイルの1か月の使用額 $%.4f / 上限 $%.2f",
+	"budget.exceeded":        "使用額が上限に達したため実行を中止しました（プロファイル %s: %s）",
+	"budget.speech_skipped":  "使用額が上限に近づいたため音声合成を省略しました（%s）",
+	"budget.fallback":        "⚠️  使用額が上限の%.0f%%に達したため、モデルを %s に切り替えます",
+	"budget.check_failed":    "使用額の確認に失敗しました",
+	"batch.start":            "📦 バッチ検索を開始: %d件 (並列数: %d)",
+	"batch.summary":          "📊 バッチ検索結果: 成功 %d件 / 失敗 %d件",
+	"briefing.default_title": "News Reporter デイリーブリーフィング",
+	"briefing.start":         "🎙️  ブリーフィングを作成中: %s (%d トピック)",
+	"briefing.topic_done":    "✅ %s (引用元 %d件)",
+	"briefing.writing":       "✍️  読み上げ原稿を作成中...",
+	"briefing.script":        "📜 読み上げ原稿:",
+	"briefing.script_saved":  "📝 原稿を保存しました: %s",
+	"briefing.input.title":   "番組名: %s",
+	"briefing.input.date":    "日付: %s",
+	"briefing.input.topics":  "トピック数: %d",
+	"briefing.input.topic":   "=== トピック%d: %s ===",
+	"briefing.done":          "✅ ブリーフィングを作成しました！",
+	"briefing.input.sources": "出典:",
+	"main.no_query":          "❌ エラー: 検索クエリが指定されていません",
+	"main.empty_query":       "❌ エラー: 空の検索クエリです",
+	"main.done":              "✅ 処理が完了しました！",
+	"main.interrupted":       "🛑 中断しました",
+
+	"main.config_error":            "❌ 設定エラー: %v",
+	"main.error":                   "❌ エラー: %v",
+	"main.missing_value":           "❌ エラー: %s オプションには%sが必要です",
+	"main.unknown_option":          "❌ エラー: 不明なオプションです: %s",
+	"main.invalid_interval":        "❌ エラー: 不正な間隔です: %s (例: %s)",
+	"main.invalid_count":           "❌ エラー: 不正な件数です: %s",
+	"main.invalid_workers":         "❌ エラー: 不正な並列数です: %s",
+	"main.invalid_month":           "❌ エラー: 不正な月です: %s (例: 2024-01)",
+	"main.invalid_date":            "❌ エラー: 不正な日付です: %s (例: 2024-01-31)",
+	"main.invalid_citation":        "❌ エラー: 引用元は \"タイトル|URL\" の形式で指定してください: %s",
+	"main.history_command":         "❌ エラー: history サブコマンド (list, show, replay) を指定してください",
+	"main.history_id":              "❌ エラー: history %s には履歴IDを1つ指定してください",
+	"main.unknown_history_command": "❌ エラー: 不明な history サブコマンドです: %s",
+	"main.batch_file":              "❌ エラー: batch にはクエリファイルを1つ指定してください",
+	"main.daemon_file":             "❌ エラー: daemon にはスケジュールファイルを1つ指定してください",
+	"main.briefing_topics":         "❌ エラー: briefing にはトピックを1つ以上指定してください",
+	"main.fixtures_command":        "❌ エラー: fixtures サブコマンド (serve) を指定してください",
+	"main.fixtures_serving":        "🎞️  %s のフィクスチャを http://%s で返します",
+	"main.mockserver_start":        "🧪 モックサーバーを http://%s で起動します (OPENAI_BASE_URL=http://%s/v1)",
+	"main.server_stopping":         "🛑 サーバーを停止しています...",
+	"main.server_stop_error":       "⚠️  サーバー停止エラー: %v",
+
+	"option.value":     "値",
+	"option.address":   "アドレス",
+	"option.directory": "ディレクトリ",
+	"option.file":      "ファイル名",
+	"option.text":      "テキスト",
+	"option.citation":  "引用元",
+	"option.interval":  "間隔",
+	"option.api_key":   "APIキー",
+	"option.count":     "件数",
+	"option.filter":    "検索文字列",
+	"option.format":    "出力形式",
+	"option.group":     "集計単位",
+	"option.month":     "月",
+	"option.date":      "日付",
+	"option.workers":   "並列数",
+	"option.title":     "番組名",
+
+	"history.empty":         "📭 保存された検索履歴はありません",
+	"history.list":          "📚 検索履歴 (%d件)",
+	"history.entry":         "🗂️  履歴 %s",
+	"history.query":         "🔍 クエリ: %s",
+	"history.time":          "🕒 日時:   %s",
+	"history.model":         "🧠 モデル: %s",
+	"history.tokens":        "🪙 トークン: 入力 %d / 出力 %d / 合計 %d",
+	"history.summary":       "🤖 AI要約:",
+	"history.invalid_entry": "⚠️  壊れた履歴を読み飛ばしました (%s:%d): %v",
+
+	"daemon.status.none":         "📭 デーモンの実行記録がありません (%s)",
+	"daemon.status.title":        "🗓️  デーモンの状態",
+	"daemon.status.stopped":      "⚪ 停止中 (%s に停止)",
+	"daemon.status.unresponsive": "🔴 応答なし (最終更新: %s, PID: %d)",
+	"daemon.status.running":      "🟢 稼働中 (PID: %d, 起動: %s)",
+	"daemon.status.schedule":     "📄 スケジュール: %s",
+	"daemon.status.last":         "   前回: %s",
+	"daemon.status.error":        "   エラー: %s",
+	"daemon.status.output":       "   出力: %s",
+	"daemon.status.next":         "   次回: %s",
+	"daemon.status.missed":       "   スキップ: %d回",
+
+	"search.failed":        "検索に失敗しました",
+	"search.speech_failed": "音声生成に失敗しました",
+	"search.render_failed": "結果の出力に失敗しました",
+	"search.no_summary":    "保存可能な要約がありません",
+	"search.incomplete":    "⚠️  応答が途中で終了しました: %s",
+
+	"daemon.started":           "🗓️  デーモンを起動しました (トピック: %d件, 出力先: %s)",
+	"daemon.topic":             "   • %s [%s] 次回: %s",
+	"daemon.catch_up":          "⏪ 取りこぼした回を実行します: %s",
+	"daemon.stopped":           "🛑 デーモンを停止しました",
+	"daemon.skipped_late":      "⏭️  %s の %s の回は時間を過ぎたためスキップしました",
+	"daemon.skipped_overrun":   "⏭️  %s は実行が長引いたため %d回分をスキップしました",
+	"daemon.skipped_offline":   "⏭️  %s は停止中に予定されていた回を取り戻し期間外のためスキップしました",
+	"daemon.searching":         "🔍 %s を検索中: %s",
+	"daemon.failed":            "❌ %s: %v",
+	"daemon.done":              "✅ %s → %s (%s)",
+	"daemon.speech_skipped":    "💸 %s の音声を省略しました: %v",
+	"daemon.speech_failed":     "⚠️  %s の音声保存に失敗しました: %v",
+	"daemon.state_load_failed": "状態ファイルの読み込みに失敗しました",
+	"daemon.state_save_failed": "⚠️  状態ファイルの保存に失敗しました: %v",
+	"daemon.output_dir_failed": "出力ディレクトリを作成できません",
+
+	"server.started":      "🌐 APIサーバーを起動しました: %s",
+	"server.start_failed": "サーバーの起動に失敗しました",
+	"server.write_failed": "⚠️  レスポンスの書き込みに失敗しました: %v",
+
+	"config.file_not_found":    "設定ファイルが見つかりません: %s",
+	"config.file_read":         "設定ファイルを読み込めません",
+	"config.unknown_key":       "%s: 不明な項目があります: %s",
+	"config.file_format":       "未対応の設定ファイル形式です: %s (.yaml, .yml, .toml のいずれか)",
+	"config.template_conflict": "テンプレートの本文とファイル (%s) は同時に指定できません",
+	"config.template_read":     "テンプレートファイルを読み込めません",
+	"config.no_profiles":       "プロファイルが見つかりません: %s (設定ファイルにプロファイルが定義されていません)",
+	"config.profile_not_found": "プロファイルが見つかりません: %s (利用可能: %s)",
+	"config.profile":           "プロファイル %s",
+	"config.invalid_value":     "%s の値が不正です: %q",
+	"config.feed_urls":         "feed バックエンドには feed.urls でフィードを1つ以上指定してください",
+	"config.feed_max_items":    "feed.max_items には1以上の件数を指定してください: %d",
+	"config.backend":           "未対応の検索バックエンドです: %s (対応: %s)",
+	"config.temperature":       "temperature は 0〜2 の範囲で指定してください: %g",
+	"config.speed":             "speed は 0.25〜4.0 の範囲で指定してください: %g",
+	"config.audio_format":      "未対応の音声形式です: %s (対応形式: %s)",
+	"config.tts_command":       "command バックエンドには tts.command.run で実行するコマンドを指定してください",
+	"config.voicevox_url":      "voicevox バックエンドには tts.voicevox.url を指定してください",
+	"config.voicevox_voice":    "VOICEVOX の声には話者IDを数値で指定してください: %s",
+	"config.tts_backend":       "未対応の音声合成バックエンドです: %s (対応: %s)",
+	"config.timeout":           "タイムアウトには正の時間を指定してください",
+	"config.fixture_mode":      "未対応のフィクスチャのモードです: %s (対応: %s, %s)",
+	"config.fixture_dir":       "フィクスチャのディレクトリを指定してください",
+	"config.max_retries":       "max_retries には0以上の回数を指定してください: %d",
+	"config.max_retry_wait":    "max_retry_wait には正の時間を指定してください",
+	"config.language":          "言語を指定してください (例: %s)",
+	"config.recency_days":      "recency_days には1以上の日数を指定してください: %d",
+	"config.timezone":          "不明なタイムゾーンです: %s",
+	"config.system_prompt":     "システムプロンプト",
+	"config.query_template":    "クエリテンプレート",
+	"config.cache_ttl":         "cache の有効期限には0以上の時間を指定してください",
+	"config.cache_mode":        "未対応のキャッシュの指定です: %s",
+	"config.cache_dir":         "キャッシュのディレクトリを指定してください",
+	"config.budget_limit":      "budget の上限には0以上の金額を指定してください",
+	"config.budget_warn_at":    "budget.warn_at は 0〜1 の範囲で指定してください: %g",
+	"config.budget_pricing":    "budget を使うには pricing.models にモデル %s の単価を指定してください",
+	"config.web_search_price":  "pricing.web_search_per_call には0以上の金額を指定してください: %g",
+	"config.model_price":       "pricing.models.%s には0以上の単価を指定してください",
+	"config.speech_price":      "pricing.speech.%s には0以上の単価を指定してください: %g",
+
+	"transport.retrying":       "⚠️  %s のため %s 後に再送します（%d回目）",
+	"transport.fixture_failed": "⚠️  フィクスチャの保存に失敗しました: %v",
+
+	"feed.fetch_failed": "⚠️  フィード %s を取得できませんでした: %v",
+	"feed.unavailable":  "フィードを取得できませんでした",
+
+	"cache.result_failed": "⚠️  検索結果のキャッシュに失敗しました: %v",
+	"cache.speech_failed": "⚠️  音声のキャッシュに失敗しました: %v",
+
+	"hint.missing_key":     "💡 ヒント: OPENAI_API_KEY環境変数を設定してください\n   export OPENAI_API_KEY=\"your-api-key-here\"",
+	"hint.invalid_key":     "💡 ヒント: APIキーが無効です。OPENAI_API_KEY（または設定ファイルの openai.api_key）を確認してください\n   https://platform.openai.com/api-keys",
+	"hint.quota":           "💡 ヒント: 利用上限に達しています。請求設定とクレジット残高を確認してください\n   https://platform.openai.com/settings/organization/billing",
+	"hint.model_not_found": "💡 ヒント: モデル %q が見つからないか、このAPIキーでは利用できません。--model または設定ファイルの openai.model を確認してください",
+	"hint.content_policy":  "💡 ヒント: 入力がコンテンツポリシーに抵触したため処理されませんでした。クエリの表現を変えて再度お試しください",
+	"hint.rate_limited":    "💡 ヒント: レート制限が解除されませんでした。しばらく待ってから再度実行するか、batch の --workers と --interval で送信ペースを下げてください",
+	"hint.budget":          "💡 ヒント: 設定ファイルの budget で指定した使用額の上限に達しました。usage コマンドで内訳を確認し、必要なら上限を引き上げてください",
+
+	"help": `📰 News Reporter - 最新ニュース検索アプリ
+==================================================
+OpenAI Responses API と web_search_preview を使用して
+リアルタイムで最新のニュースや情報を検索します。
+
+使用方法:
+  go run main.go "検索クエリ"
+  go run main.go [オプション] "検索クエリ"
+  go run main.go serve [--addr :8080]
+
+例:
+  go run main.go "今日の経済ニュース"
+  go run main.go "最新のAI技術動向"
+  go run main.go "円安ドル高の最新状況"
+  go run main.go --stream "今日のニュース"
+  go run main.go --audio "今日のニュース"
+  go run main.go --save summary.mp3 "AIニュース"
+  go run main.go --stream --audio --save summary.mp3 "AIニュース"
+  go run main.go --format json "AIニュース" > result.json
+  go run main.go --profile economy "今日の為替"
+  go run main.go --lang en "AI news today"
+
+オプション:
+  -h, --help                このヘルプメッセージを表示
+  -a, --audio               音声再生機能付きで実行
+      --stream              要約を受信しながら逐次表示
+  -f, --format <format>     出力形式 (text, json, markdown, csv, html)
+      --no-history          検索結果を履歴に保存しない
+      --raw-speech          要約を整形せずそのまま読み上げる
+  -s, --save <filename>     要約を音声ファイルに保存
+
+設定オプション (全コマンド共通、設定ファイル・環境変数より優先):
+      --config <file>       設定ファイル (YAML/TOML)
+      --lang <lang>         表示・要約・読み上げの言語 (ja, en, zh, ko など, デフォルト: ja)
+      --profile <name>      設定ファイルで定義したプロファイルを使用
+      --backend <name>      検索バックエンド (openai, feed, デフォルト: openai)
+      --model <model>       検索・要約に使うモデル (デフォルト: gpt-4o-mini)
+      --temperature <n>     要約の温度 (0〜2, デフォルト: 0.3)
+      --timeout <duration>  検索のタイムアウト (デフォルト: 60s)
+      --tts-backend <name>  音声合成バックエンド (openai, command, voicevox, デフォルト: openai)
+      --tts-model <model>   音声合成モデル (デフォルト: tts-1)
+      --voice <voice>       読み上げの声・VOICEVOXの話者ID (デフォルト: 言語ごとの既定値)
+      --speed <n>           読み上げ速度 (0.25〜4.0, デフォルト: 1.0)
+      --audio-format <fmt>  音声形式 (mp3, opus, aac, flac, wav, pcm)
+      --tts-timeout <dur>   音声合成のタイムアウト (デフォルト: 120s)
+      --no-cache            検索結果・音声のキャッシュを使わない
+      --refresh             キャッシュを使わずに取得し直し、キャッシュを更新する
+
+サブコマンド:
+  serve                     REST APIサーバーを起動
+    --addr <addr>           待ち受けアドレス (デフォルト: :8080)
+  history list              検索履歴を一覧表示
+    -n, --limit <n>         表示件数 (デフォルト: 20)
+    -q, --query <text>      クエリで絞り込み
+  history show <id>         履歴の詳細を表示
+  history replay <id>       保存した結果を再出力 (--format 指定可)
+  usage                     記録された使用量と概算費用を集計
+    --by <unit>             集計単位 (day, month, topic, profile。デフォルト: day)
+    --month <YYYY-MM>       指定した月だけを集計
+    --since <YYYY-MM-DD>    指定した日以降を集計
+    --until <YYYY-MM-DD>    指定した日までを集計
+    --profile <name>        プロファイルで絞り込み
+    -f, --format <format>   出力形式 (text, json, csv)
+  batch <file>              ファイルのクエリをまとめて検索 (.txt: 1行1件, .jsonl: {"query": ...})
+    -w, --workers <n>       並列数 (デフォルト: 3)
+    --interval <duration>   検索を開始する最小間隔 (デフォルト: 1s)
+    -o, --out <dir>         結果の出力先 (デフォルト: batch-<日時>)
+    -f, --format <format>   結果ファイルの形式 (デフォルト: プロファイルの形式または markdown)
+  daemon <schedule.json>    スケジュールに従って定期検索
+  daemon status             デーモンの実行状況を表示
+    --state <file>          状態ファイル (デフォルト: ~/.news_reporter/daemon_state.json)
+  briefing <topic>...       複数トピックをまとめた音声ブリーフィングを作成
+    -o, --out <file>        音声の保存先 (デフォルト: briefing-<日付>.<音声形式>)
+    --script <file>         原稿の保存先 (デフォルト: 音声と同名の .txt)
+    --title <title>         番組名
+    --topics <file>         トピックをファイルから読み込む
+    -w, --workers <n>       トピックの同時検索数 (デフォルト: 3)
+  fixtures serve            記録したフィクスチャを返すスタンドインサーバーを起動
+    --dir <dir>             フィクスチャのディレクトリ (デフォルト: ~/.news_reporter/fixtures)
+    --addr <addr>           待ち受けアドレス (デフォルト: 127.0.0.1:8089)
+  mockserver                OpenAI API を模したモックサーバーを起動 (APIキー・料金なしで動作確認)
+    --addr <addr>           待ち受けアドレス (デフォルト: 127.0.0.1:8090)
+    --delta <text>          要約として返すテキスト (複数指定で順にストリーミング)
+    --citation <title|url>  要約に付ける引用元 (複数指定可)
+    --no-citations          引用元を付けない
+    --delay <duration>      デルタを送る間隔 (例: 50ms)
+    --api-key <key>         このキー以外のリクエストを 401 にする
+
+機能:
+  ✅ リアルタイムWeb検索
+  ✅ 最新情報の自動取得
+  ✅ 日本語・英語・中国語・韓国語での要約表示 (--lang)
+  ✅ 情報源URL付きの結果
+  ⚡ ストリーミング表示
+  📄 JSON/Markdown/CSV/HTML出力
+  🎵 音声読み上げ機能
+  💾 音声ファイル保存機能
+  🌐 REST APIサーバー
+  📚 検索履歴の保存・再表示
+  📦 バッチ検索
+  🗓️  定期ブリーフィング (デーモン)
+  🎙️  ポッドキャスト風ブリーフィング
+
+音声機能について:
+  • OpenAI TTSを使用した高品質な音声合成
+  • 日本語要約の自動読み上げ
+  • Markdown記号・URL・引用番号を除去し、略語や数値を読みやすく変換
+  • MP3形式での音声ファイル保存
+  • --audio と --save は併用可能 (検索・音声生成は1回のみ)
+  • 長い要約は文単位で分割して並列に合成し、1つの音声に連結
+
+REST APIについて:
+  • POST /search        検索結果をJSONで返す
+  • POST /search/audio  要約をMP3音声で返す
+  • リクエストボディ: {"query": "検索クエリ"}
+
+検索履歴について:
+  • 検索結果は ~/.news_reporter/history.jsonl に自動保存されます
+  • 保存先は NEWS_REPORTER_HISTORY 環境変数で変更できます
+
+設定ファイルについて:
+  • ~/.news_reporter/config.yaml (または .yml/.toml) を自動で読み込みます
+  • 場所は --config または NEWS_REPORTER_CONFIG 環境変数で変更できます
+  • prompt セクションでシステムプロンプト・クエリのテンプレート (text/template) を変更できます
+  • profiles に用途別のプロファイル (モデル・プロンプト・言語・声・出力形式・情報源) を定義できます
+  • 優先順位: コマンドライン > 環境変数 > プロファイル > 設定ファイル > 既定値
+
+注意: OPENAI_API_KEY環境変数の設定が必要です
+`,
+}
@@ -0,0 +1,332 @@
+package i18n
+
+// messagesKo 韓国語のメッセージ。未翻訳のものは英語で表示
+var messagesKo = map[string]string{
+	"layout.date":            "2006년 1월 2일",
+	"layout.datetime":        "2006년 1월 2일 15:04",
+	"period.day":             "%d일",
+	"period.week":            "%d주",
+	"period.month":           "%d개월",
+	"search.start":           "🔍 최신 정보 검색 중: %s (%s 기준)",
+	"search.web_searching":   "🔎 웹 검색 실행 중...",
+	"search.history_failed":  "⚠️  기록 저장에 실패했습니다: %v",
+	"search.interrupted":     "⚠️  중단되었습니다 (지금까지 받은 내용입니다)",
+	"result.header":          "📊 최신 검색 결과 (%s 조회)",
+	"result.web_results":     "🌐 웹 검색 결과 (%d건):",
+	"result.no_results":      "⚠️  최신 웹 검색 결과를 찾지 못했습니다",
+	"result.summary":         "🤖 AI 요약:",
+	"result.citations":       "🌐 출처 (%d건):",
+	"result.fetched_at":      "%s 조회",
+	"result.summary_title":   "요약",
+	"result.no_summary":      "요약이 없습니다.",
+	"result.sources_title":   "출처",
+	"result.no_sources":      "출처가 없습니다.",
+	"result.usage":           "🪙 토큰: 입력 %d / 출력 %d · 웹 검색 %d회",
+	"result.cost":            "💰 예상 비용: $%.4f",
+	"result.cached":          "♻️  %s 에 가져온 캐시 결과를 표시합니다(비용 없음. --refresh 로 다시 가져옵니다)",
+	"usage.record_failed":    "⚠️  사용량 기록에 실패했습니다: %v",
+	"audio.no_summary":       "⚠️  재생할 요약이 없습니다",
+	"audio.confirm":          "🎵 요약을 음성으로 재생할까요? (y/N): ",
+	"audio.generating":       "🎵 음성 생성 중...",
+	"audio.generating_file":  "🎵 음성 파일 생성 중: %s",
+	"audio.chunked":          "🧩 긴 텍스트이므로 %d개로 나누어 음성을 생성합니다",
+	"audio.playing":          "🔊 음성 재생 중...",
+	"audio.played":           "✅ 음성 재생이 끝났습니다!",
+	"audio.play_error":       "⚠️  음성 재생 오류: %v",
+	"audio.saved":            "✅ 음성 파일을 저장했습니다: %s",
+	"audio.budget_skipped":   "💸 %v",
+	"budget.daily":           "전체 프로필 오늘 사용액 $%.4f / 상한 $%.2f",
+	"budget.monthly":         "전체 프로필 이번 달 사용액 $%.4f / 상한 $%.2f",
+	"budget.profile_daily":   "이 프로필 오늘 사용액 $%.4f / 상한 $%.2f",
+	"budget.profile_monthly": "이 프로필 이번 달 사용액 $%.4f / 상한 $%.2f",
+	"budget.exceeded":        "사용 금액이 상한에 도달하여 실행을 중단했습니다(프로필 %s: %s)",
+	"budget.speech_skipped":  "사용 금액이 상한에 가까워 음성 합성을 건너뛰었습니다(%s)",
+	"budget.fallback":        "⚠️  사용 금액이 상한의 %.0f%%에 도달하여 모델을 %s(으)로 전환합니다",
+	"budget.check_failed":    "사용 금액 확인에 실패했습니다",
+	"batch.start":            "📦 일괄 검색 시작: %d건 (병렬 수: %d)",
+	"batch.summary":          "📊 일괄 검색 결과: 성공 %d건 / 실패 %d건",
+	"briefing.default_title": "News Reporter 데일리 브리핑",
+	"briefing.start":         "🎙️  브리핑 작성 중: %s (%d개 주제)",
+	"briefing.topic_done":    "✅ %s (출처 %d건)",
+	"briefing.writing":       "✍️  방송 원고 작성 중...",
+	"briefing.script":        "📜 방송 원고:",
+	"briefing.script_saved":  "📝 원고를 저장했습니다: %s",
+	"briefing.input.title":   "프로그램명: %s",
+	"briefing.input.date":    "날짜: %s",
+	"briefing.input.topics":  "주제 수: %d",
+	"briefing.input.topic":   "=== 주제%d: %s ===",
+	"briefing.done":          "✅ 브리핑을 만들었습니다!",
+	"briefing.input.sources": "출처:",
+	"main.no_query":          "❌ 오류: 검색어가 지정되지 않았습니다",
+	"main.empty_query":       "❌ 오류: 검색어가 비어 있습니다",
+	"main.done":              "✅ 처리가 완료되었습니다!",
+	"main.interrupted":       "🛑 중단되었습니다",
+
+	"main.config_error":            "❌ 설정 오류: %v",
+	"main.error":                   "❌ 오류: %v",
+	"main.missing_value":           "❌ 오류: %s 옵션에는 값(%s)이 필요합니다",
+	"main.unknown_option":          "❌ 오류: 알 수 없는 옵션입니다: %s",
+	"main.invalid_interval":        "❌ 오류: 잘못된 간격입니다: %s (예: %s)",
+	"main.invalid_count":           "❌ 오류: 잘못된 건수입니다: %s",
+	"main.invalid_workers":         "❌ 오류: 잘못된 병렬 수입니다: %s",
+	"main.invalid_month":           "❌ 오류: 잘못된 월입니다: %s (예: 2024-01)",
+	"main.invalid_date":            "❌ 오류: 잘못된 날짜입니다: %s (예: 2024-01-31)",
+	"main.invalid_citation":        "❌ 오류: 출처는 \"제목|URL\" 형식으로 지정하세요: %s",
+	"main.history_command":         "❌ 오류: history 하위 명령(list, show, replay)을 지정하세요",
+	"main.history_id":              "❌ 오류: history %s 에는 기록 ID를 하나 지정하세요",
+	"main.unknown_history_command": "❌ 오류: 알 수 없는 history 하위 명령입니다: %s",
+	"main.batch_file":              "❌ 오류: batch 에는 쿼리 파일을 하나 지정하세요",
+	"main.daemon_file":             "❌ 오류: daemon 에는 스케줄 파일을 하나 지정하세요",
+	"main.briefing_topics":         "❌ 오류: briefing 에는 주제를 하나 이상 지정하세요",
+	"main.fixtures_command":        "❌ 오류: fixtures 하위 명령(serve)을 지정하세요",
+	"main.fixtures_serving":        "🎞️  %s 의 픽스처를 http://%s 에서 제공합니다",
+	"main.mockserver_start":        "🧪 http://%s 에서 모의 서버를 시작합니다 (OPENAI_BASE_URL=http://%s/v1)",
+	"main.server_stopping":         "🛑 서버를 중지하는 중...",
+	"main.server_stop_error":       "⚠️  서버 중지 오류: %v",
+
+	"option.value":     "값",
+	"option.address":   "주소",
+	"option.directory": "디렉터리",
+	"option.file":      "파일 이름",
+	"option.text":      "텍스트",
+	"option.citation":  "출처",
+	"option.interval":  "간격",
+	"option.api_key":   "API 키",
+	"option.count":     "건수",
+	"option.filter":    "검색 문자열",
+	"option.format":    "출력 형식",
+	"option.group":     "집계 단위",
+	"option.month":     "월",
+	"option.date":      "날짜",
+	"option.workers":   "병렬 수",
+	"option.title":     "프로그램명",
+
+	"history.empty":         "📭 저장된 검색 기록이 없습니다",
+	"history.list":          "📚 검색 기록 (%d건)",
+	"history.entry":         "🗂️  기록 %s",
+	"history.query":         "🔍 검색어: %s",
+	"history.time":          "🕒 일시:   %s",
+	"history.model":         "🧠 모델: %s",
+	"history.tokens":        "🪙 토큰: 입력 %d / 출력 %d / 합계 %d",
+	"history.summary":       "🤖 AI 요약:",
+	"history.invalid_entry": "⚠️  손상된 기록을 건너뛰었습니다 (%s:%d): %v",
+
+	"daemon.status.none":         "📭 데몬 실행 기록이 없습니다 (%s)",
+	"daemon.status.title":        "🗓️  데몬 상태",
+	"daemon.status.stopped":      "⚪ 중지됨 (%s 에 중지)",
+	"daemon.status.unresponsive": "🔴 응답 없음 (마지막 갱신: %s, PID: %d)",
+	"daemon.status.running":      "🟢 실행 중 (PID: %d, 시작: %s)",
+	"daemon.status.schedule":     "📄 스케줄: %s",
+	"daemon.status.last":         "   이전: %s",
+	"daemon.status.error":        "   오류: %s",
+	"daemon.status.output":       "   출력: %s",
+	"daemon.status.next":         "   다음: %s",
+	"daemon.status.missed":       "   건너뜀: %d회",
+
+	"search.failed":        "검색에 실패했습니다",
+	"search.speech_failed": "음성 생성에 실패했습니다",
+	"search.render_failed": "결과 출력에 실패했습니다",
+	"search.no_summary":    "저장할 요약이 없습니다",
+	"search.incomplete":    "⚠️  응답이 도중에 종료되었습니다: %s",
+
+	"daemon.started":           "🗓️  데몬을 시작했습니다 (주제: %d개, 출력 위치: %s)",
+	"daemon.topic":             "   • %s [%s] 다음 실행: %s",
+	"daemon.catch_up":          "⏪ 놓친 실행을 수행합니다: %s",
+	"daemon.stopped":           "🛑 데몬을 중지했습니다",
+	"daemon.skipped_late":      "⏭️  %s의 %s 실행은 시간이 지나 건너뛰었습니다",
+	"daemon.skipped_overrun":   "⏭️  %s은(는) 실행이 길어져 %d회분을 건너뛰었습니다",
+	"daemon.skipped_offline":   "⏭️  %s의 중지 중 예정된 실행은 보충 기간을 벗어나 건너뛰었습니다",
+	"daemon.searching":         "🔍 %s 검색 중: %s",
+	"daemon.failed":            "❌ %s: %v",
+	"daemon.done":              "✅ %s → %s (%s)",
+	"daemon.speech_skipped":    "💸 %s의 음성을 생략했습니다: %v",
+	"daemon.speech_failed":     "⚠️  %s의 음성 저장에 실패했습니다: %v",
+	"daemon.state_load_failed": "상태 파일을 읽지 못했습니다",
+	"daemon.state_save_failed": "⚠️  상태 파일 저장에 실패했습니다: %v",
+	"daemon.output_dir_failed": "출력 디렉터리를 만들 수 없습니다",
+
+	"server.started":      "🌐 API 서버를 시작했습니다: %s",
+	"server.start_failed": "서버 시작에 실패했습니다",
+	"server.write_failed": "⚠️  응답 쓰기에 실패했습니다: %v",
+
+	"config.file_not_found":    "설정 파일을 찾을 수 없습니다: %s",
+	"config.file_read":         "설정 파일을 읽을 수 없습니다",
+	"config.unknown_key":       "%s: 알 수 없는 항목이 있습니다: %s",
+	"config.file_format":       "지원하지 않는 설정 파일 형식입니다: %s (.yaml, .yml, .toml 중 하나)",
+	"config.template_conflict": "템플릿 본문과 파일 (%s)은 동시에 지정할 수 없습니다",
+	"config.template_read":     "템플릿 파일을 읽을 수 없습니다",
+	"config.no_profiles":       "프로필을 찾을 수 없습니다: %s (설정 파일에 프로필이 정의되어 있지 않습니다)",
+	"config.profile_not_found": "프로필을 찾을 수 없습니다: %s (사용 가능: %s)",
+	"config.profile":           "프로필 %s",
+	"config.invalid_value":     "%s의 값이 올바르지 않습니다: %q",
+	"config.feed_urls":         "feed 백엔드에는 feed.urls에 피드를 하나 이상 지정해야 합니다",
+	"config.feed_max_items":    "feed.max_items는 1 이상이어야 합니다: %d",
+	"config.backend":           "지원하지 않는 검색 백엔드입니다: %s (지원: %s)",
+	"config.temperature":       "temperature는 0~2 범위여야 합니다: %g",
+	"config.speed":             "speed는 0.25~4.0 범위여야 합니다: %g",
+	"config.audio_format":      "지원하지 않는 오디오 형식입니다: %s (지원: %s)",
+	"config.tts_command":       "command 백엔드에는 tts.command.run에 실행할 명령을 지정해야 합니다",
+	"config.voicevox_url":      "voicevox 백엔드에는 tts.voicevox.url을 지정해야 합니다",
+	"config.voicevox_voice":    "VOICEVOX 음성에는 숫자 화자 ID를 지정해야 합니다: %s",
+	"config.tts_backend":       "지원하지 않는 음성 합성 백엔드입니다: %s (지원: %s)",
+	"config.timeout":           "타임아웃은 양수 시간이어야 합니다",
+	"config.fixture_mode":      "지원하지 않는 fixture 모드입니다: %s (지원: %s, %s)",
+	"config.fixture_dir":       "fixture 디렉터리를 지정해야 합니다",
+	"config.max_retries":       "max_retries는 0 이상이어야 합니다: %d",
+	"config.max_retry_wait":    "max_retry_wait는 양수 시간이어야 합니다",
+	"config.language":          "언어를 지정해야 합니다 (예: %s)",
+	"config.recency_days":      "recency_days는 1일 이상이어야 합니다: %d",
+	"config.timezone":          "알 수 없는 시간대입니다: %s",
+	"config.system_prompt":     "시스템 프롬프트",
+	"config.query_template":    "쿼리 템플릿",
+	"config.cache_ttl":         "cache 유효 기간은 0 이상이어야 합니다",
+	"config.cache_mode":        "지원하지 않는 캐시 설정입니다: %s",
+	"config.cache_dir":         "캐시 디렉터리를 지정해야 합니다",
+	"config.budget_limit":      "budget 상한은 0 이상의 금액이어야 합니다",
+	"config.budget_warn_at":    "budget.warn_at은 0~1 범위여야 합니다: %g",
+	"config.budget_pricing":    "budget를 사용하려면 pricing.models에 모델 %s의 단가를 지정해야 합니다",
+	"config.web_search_price":  "pricing.web_search_per_call은 0 이상의 금액이어야 합니다: %g",
+	"config.model_price":       "pricing.models.%s의 단가는 0 이상이어야 합니다",
+	"config.speech_price":      "pricing.speech.%s의 단가는 0 이상이어야 합니다: %g",
+
+	"transport.retrying":       "⚠️  %s, %s 후 다시 시도합니다(%d번째)",
+	"transport.fixture_failed": "⚠️  fixture 저장에 실패했습니다: %v",
+
+	"feed.fetch_failed": "⚠️  피드 %s을(를) 가져오지 못했습니다: %v",
+	"feed.unavailable":  "피드를 가져오지 못했습니다",
+
+	"cache.result_failed": "⚠️  검색 결과 캐시에 실패했습니다: %v",
+	"cache.speech_failed": "⚠️  음성 캐시에 실패했습니다: %v",
+
+	"hint.missing_key":     "💡 힌트: OPENAI_API_KEY 환경 변수를 설정하세요\n   export OPENAI_API_KEY=\"your-api-key-here\"",
+	"hint.invalid_key":     "💡 힌트: API 키가 유효하지 않습니다. OPENAI_API_KEY(또는 설정 파일의 openai.api_key)를 확인하세요\n   https://platform.openai.com/api-keys",
+	"hint.quota":           "💡 힌트: 사용 한도를 초과했습니다. 결제 설정과 크레딧 잔액을 확인하세요\n   https://platform.openai.com/settings/organization/billing",
+	"hint.model_not_found": "💡 힌트: 모델 %q 이(가) 없거나 이 API 키로 사용할 수 없습니다. --model 또는 설정 파일의 openai.model 을 확인하세요",
+	"hint.content_policy":  "💡 힌트: 요청이 콘텐츠 정책에 위배되어 처리되지 않았습니다. 검색어를 바꿔 다시 시도하세요",
+	"hint.rate_limited":    "💡 힌트: 속도 제한이 해제되지 않았습니다. 잠시 후 다시 실행하거나 batch 의 --workers 와 --interval 로 요청 속도를 낮추세요",
+	"hint.budget":          "💡 힌트: 설정 파일의 budget 에서 지정한 사용 금액 상한에 도달했습니다. usage 명령으로 내역을 확인하고 필요하면 상한을 올리세요",
+
+	"help": `📰 News Reporter - 최신 뉴스 검색
+==================================================
+OpenAI Responses API 와 web_search_preview 를 사용하여
+최신 뉴스와 정보를 실시간으로 검색합니다.
+
+사용법:
+  go run main.go "검색어"
+  go run main.go [옵션] "검색어"
+  go run main.go serve [--addr :8080]
+
+예시:
+  go run main.go --lang ko "오늘의 경제 뉴스"
+  go run main.go --lang ko "AI 최신 동향"
+  go run main.go --lang ko --stream "오늘의 주요 뉴스"
+  go run main.go --lang ko --audio "오늘의 주요 뉴스"
+  go run main.go --lang ko --save summary.mp3 "AI 뉴스"
+  go run main.go --lang ko --stream --audio --save summary.mp3 "AI 뉴스"
+  go run main.go --lang ko --format json "AI 뉴스" > result.json
+  go run main.go --profile korean "오늘의 시장"
+
+옵션:
+  -h, --help                이 도움말을 표시
+  -a, --audio               요약을 음성으로 재생
+      --stream              받는 대로 요약을 출력
+  -f, --format <형식>       출력 형식 (text, json, markdown, csv, html)
+      --no-history          결과를 기록에 저장하지 않음
+      --raw-speech          읽기용으로 정리하지 않고 요약을 그대로 읽음
+  -s, --save <파일 이름>    요약을 음성 파일로 저장
+
+설정 옵션 (모든 명령 공통, 설정 파일과 환경 변수보다 우선):
+      --config <파일>       설정 파일 (YAML/TOML)
+      --lang <언어>         메시지·요약·음성 언어 (ja, en, zh, ko 등, 기본값: ja)
+      --profile <이름>      설정 파일에 정의한 프로필 사용
+      --backend <이름>      검색 백엔드 (openai, feed, 기본값: openai)
+      --model <모델>        검색·요약에 사용할 모델 (기본값: gpt-4o-mini)
+      --temperature <n>     요약의 temperature (0-2, 기본값: 0.3)
+      --timeout <시간>      검색 제한 시간 (기본값: 60s)
+      --tts-backend <이름>  음성 합성 백엔드 (openai, command, voicevox, 기본값: openai)
+      --tts-model <모델>    음성 합성 모델 (기본값: tts-1)
+      --voice <음성>        음성 또는 VOICEVOX 화자 ID (기본값: 언어에 따라 다름)
+      --speed <n>           말하기 속도 (0.25-4.0, 기본값: 1.0)
+      --audio-format <fmt>  음성 형식 (mp3, opus, aac, flac, wav, pcm)
+      --tts-timeout <시간>  음성 합성 제한 시간 (기본값: 120s)
+      --no-cache            캐시한 검색 결과와 음성을 사용하지 않음
+      --refresh             캐시를 무시하고 다시 가져와 캐시를 갱신
+
+하위 명령:
+  serve                     REST API 서버 시작
+    --addr <주소>           수신 주소 (기본값: :8080)
+  history list              검색 기록 목록
+    -n, --limit <n>         표시 건수 (기본값: 20)
+    -q, --query <텍스트>    검색어로 필터링
+  history show <id>         기록 상세 표시
+  history replay <id>       저장된 결과를 다시 출력 (--format 사용 가능)
+  usage                     기록된 사용량과 예상 비용을 일·월·주제별로 집계
+    --by <단위>             집계 단위 day, month, topic, profile (기본값: day)
+    --month <YYYY-MM>       지정한 월만
+    --since <YYYY-MM-DD>    지정한 날짜부터
+    --until <YYYY-MM-DD>    지정한 날짜까지 (해당일 포함)
+    --profile <이름>        지정한 프로필만
+    -f, --format <형식>     출력 형식 (text, json, csv)
+  batch <파일>              파일의 검색어를 일괄 검색 (.txt: 한 줄에 하나, .jsonl: {"query": ...})
+    -w, --workers <n>       병렬 수 (기본값: 3)
+    --interval <시간>       검색 사이의 최소 간격 (기본값: 1s)
+    -o, --out <디렉터리>    출력 디렉터리 (기본값: batch-<일시>)
+    -f, --format <형식>     결과 파일 형식 (기본값: 프로필의 형식 또는 markdown)
+  daemon <schedule.json>    스케줄에 따라 정기 검색
+  daemon status             데몬 상태 표시
+    --state <파일>          상태 파일 (기본값: ~/.news_reporter/daemon_state.json)
+  briefing <주제>...        여러 주제를 하나의 음성 브리핑으로 정리
+    -o, --out <파일>        음성 출력 위치 (기본값: briefing-<날짜>.<음성 형식>)
+    --script <파일>         원고 출력 위치 (기본값: 음성과 같은 이름의 .txt)
+    --title <제목>          프로그램명
+    --topics <파일>         파일에서 주제 읽기
+    -w, --workers <n>       동시에 검색할 주제 수 (기본값: 3)
+  fixtures serve            기록한 데이터를 재생하는 대체 서버 시작
+    --dir <디렉터리>        데이터 디렉터리 (기본값: ~/.news_reporter/fixtures)
+    --addr <주소>           수신 주소 (기본값: 127.0.0.1:8089)
+  mockserver                OpenAI API 모의 서버 시작 (키나 비용 없이 CLI 체험)
+    --addr <주소>           수신 주소 (기본값: 127.0.0.1:8090)
+    --delta <텍스트>        스트리밍할 요약 텍스트 (여러 번 지정 가능)
+    --citation <제목|url>   요약에 붙일 출처 (여러 번 지정 가능)
+    --no-citations          출처를 붙이지 않음
+    --delay <시간>          조각 사이의 간격 (예: 50ms)
+    --api-key <키>          다른 키의 요청을 거부 (401)
+
+기능:
+  ✅ 실시간 웹 검색
+  ✅ 일본어·영어·중국어·한국어 요약 (--lang)
+  ✅ 출처 URL 이 포함된 결과
+  ⚡ 스트리밍 출력
+  📄 JSON/Markdown/CSV/HTML 출력
+  🎵 음성 재생과 MP3 저장
+  🌐 REST API 서버
+  📚 검색 기록
+  📦 일괄 검색
+  🗓️  정기 브리핑 (daemon)
+  🎙️  팟캐스트형 브리핑
+
+음성:
+  • OpenAI TTS 를 사용한 고품질 음성
+  • 읽기 전에 Markdown·URL·인용 표시를 제거
+  • --audio 와 --save 를 함께 사용 가능 (검색 1회, 합성 1회)
+  • 긴 요약은 문장 단위로 나누어 병렬로 합성한 뒤 연결
+
+REST API:
+  • POST /search        결과를 JSON 으로 반환
+  • POST /search/audio  요약을 음성으로 반환
+  • 요청 본문: {"query": "..."}
+
+검색 기록:
+  • 결과는 ~/.news_reporter/history.jsonl 에 저장
+  • NEWS_REPORTER_HISTORY 로 저장 위치 변경
+
+설정 파일:
+  • ~/.news_reporter/config.yaml (또는 .yml/.toml) 을 자동으로 읽음
+  • --config 또는 NEWS_REPORTER_CONFIG 로 위치 변경
+  • prompt 섹션에서 시스템 프롬프트와 검색어 템플릿을 사용자 정의 (text/template)
+  • 프로필로 모델·프롬프트·언어·음성·출력 형식·출처를 묶어서 지정
+  • 우선순위: 명령줄 > 환경 변수 > 프로필 > 설정 파일 > 기본값
+
+참고: OPENAI_API_KEY 환경 변수를 설정해야 합니다
+`,
+}
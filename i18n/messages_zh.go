@@ -0,0 +1,332 @@
+package i18n
+
+// messagesZh 中国語（簡体字）のメッセージ。未翻訳のものは英語で表示
+var messagesZh = map[string]string{
+	"layout.date":            "2006年1月2日",
+	"layout.datetime":        "2006年1月2日 15:04",
+	"period.day":             "%d天",
+	"period.week":            "%d周",
+	"period.month":           "%d个月",
+	"search.start":           "🔍 正在搜索最新信息: %s (截至%s)",
+	"search.web_searching":   "🔎 正在进行网络搜索...",
+	"search.history_failed":  "⚠️  保存历史记录失败: %v",
+	"search.interrupted":     "⚠️  已中断（以下为已接收的内容）",
+	"result.header":          "📊 最新搜索结果 (获取于%s)",
+	"result.web_results":     "🌐 网络搜索结果 (%d条):",
+	"result.no_results":      "⚠️  未找到最新的网络搜索结果",
+	"result.summary":         "🤖 AI摘要:",
+	"result.citations":       "🌐 来源 (%d条):",
+	"result.fetched_at":      "获取于%s",
+	"result.summary_title":   "摘要",
+	"result.no_summary":      "暂无摘要。",
+	"result.sources_title":   "来源",
+	"result.no_sources":      "暂无来源。",
+	"result.usage":           "🪙 令牌: 输入 %d / 输出 %d · 网络搜索 %d次",
+	"result.cost":            "💰 预估费用: $%.4f",
+	"result.cached":          "♻️  显示 %s 获取的缓存结果（不产生费用；使用 --refresh 重新获取）",
+	"usage.record_failed":    "⚠️  记录用量失败: %v",
+	"audio.no_summary":       "⚠️  没有可播放的摘要",
+	"audio.confirm":          "🎵 要用语音播放摘要吗？ (y/N): ",
+	"audio.generating":       "🎵 正在生成语音...",
+	"audio.generating_file":  "🎵 正在生成音频文件: %s",
+	"audio.chunked":          "🧩 文本较长，分为 %d 段生成语音",
+	"audio.playing":          "🔊 正在播放语音...",
+	"audio.played":           "✅ 语音播放完毕！",
+	"audio.play_error":       "⚠️  语音播放错误: %v",
+	"audio.saved":            "✅ 已保存音频文件: %s",
+	"audio.budget_skipped":   "💸 %v",
+	"budget.daily":           "所有配置今日已用 $%.4f / 上限 $%.2f",
+	"budget.monthly":         "所有配置本月已用 $%.4f / 上限 $%.2f",
+	"budget.profile_daily":   "该配置今日已用 $%.4f / 上限 $%.2f",
+	"budget.profile_monthly": "该配置本月已用 $%.4f / 上限 $%.2f",
+	"budget.exceeded":        "已达到费用上限，已停止执行（配置 %s: %s）",
+	"budget.speech_skipped":  "费用接近上限，已跳过语音合成（%s）",
+	"budget.fallback":        "⚠️  已用费用达到上限的%.0f%%，将模型切换为 %s",
+	"budget.check_failed":    "检查费用上限失败",
+	"batch.start":            "📦 开始批量搜索: %d条 (并行数: %d)",
+	"batch.summary":          "📊 批量搜索结果: 成功 %d条 / 失败 %d条",
+	"briefing.default_title": "News Reporter 每日简报",
+	"briefing.start":         "🎙️  正在制作简报: %s (%d个主题)",
+	"briefing.topic_done":    "✅ %s (来源 %d条)",
+	"briefing.writing":       "✍️  正在撰写播报稿...",
+	"briefing.script":        "📜 播报稿:",
+	"briefing.script_saved":  "📝 已保存播报稿: %s",
+	"briefing.input.title":   "节目名称: %s",
+	"briefing.input.date":    "日期: %s",
+	"briefing.input.topics":  "主题数: %d",
+	"briefing.input.topic":   "=== 主题%d: %s ===",
+	"briefing.done":          "✅ 简报已制作完成！",
+	"briefing.input.sources": "来源:",
+	"main.no_query":          "❌ 错误: 未指定搜索关键词",
+	"main.empty_query":       "❌ 错误: 搜索关键词为空",
+	"main.done":              "✅ 处理完成！",
+	"main.interrupted":       "🛑 已中断",
+
+	"main.config_error":            "❌ 配置错误: %v",
+	"main.error":                   "❌ 错误: %v",
+	"main.missing_value":           "❌ 错误: %s 选项需要指定%s",
+	"main.unknown_option":          "❌ 错误: 未知选项: %s",
+	"main.invalid_interval":        "❌ 错误: 无效的间隔: %s (例: %s)",
+	"main.invalid_count":           "❌ 错误: 无效的条数: %s",
+	"main.invalid_workers":         "❌ 错误: 无效的并行数: %s",
+	"main.invalid_month":           "❌ 错误: 无效的月份: %s (例: 2024-01)",
+	"main.invalid_date":            "❌ 错误: 无效的日期: %s (例: 2024-01-31)",
+	"main.invalid_citation":        "❌ 错误: 引用来源请按 \"标题|URL\" 的格式指定: %s",
+	"main.history_command":         "❌ 错误: 请指定 history 子命令 (list, show, replay)",
+	"main.history_id":              "❌ 错误: history %s 需要指定一个历史记录ID",
+	"main.unknown_history_command": "❌ 错误: 未知的 history 子命令: %s",
+	"main.batch_file":              "❌ 错误: batch 需要指定一个查询文件",
+	"main.daemon_file":             "❌ 错误: daemon 需要指定一个计划文件",
+	"main.briefing_topics":         "❌ 错误: briefing 需要至少指定一个主题",
+	"main.fixtures_command":        "❌ 错误: 请指定 fixtures 子命令 (serve)",
+	"main.fixtures_serving":        "🎞️  正在提供 %s 中的固定数据 (http://%s)",
+	"main.mockserver_start":        "🧪 在 http://%s 上启动模拟服务器 (OPENAI_BASE_URL=http://%s/v1)",
+	"main.server_stopping":         "🛑 正在停止服务器...",
+	"main.server_stop_error":       "⚠️  服务器停止错误: %v",
+
+	"option.value":     "值",
+	"option.address":   "地址",
+	"option.directory": "目录",
+	"option.file":      "文件名",
+	"option.text":      "文本",
+	"option.citation":  "引用来源",
+	"option.interval":  "间隔",
+	"option.api_key":   "API 密钥",
+	"option.count":     "条数",
+	"option.filter":    "搜索字符串",
+	"option.format":    "输出格式",
+	"option.group":     "汇总单位",
+	"option.month":     "月份",
+	"option.date":      "日期",
+	"option.workers":   "并行数",
+	"option.title":     "节目名称",
+
+	"history.empty":         "📭 没有保存的搜索历史",
+	"history.list":          "📚 搜索历史 (%d条)",
+	"history.entry":         "🗂️  历史记录 %s",
+	"history.query":         "🔍 查询: %s",
+	"history.time":          "🕒 时间: %s",
+	"history.model":         "🧠 模型: %s",
+	"history.tokens":        "🪙 令牌: 输入 %d / 输出 %d / 合计 %d",
+	"history.summary":       "🤖 AI摘要:",
+	"history.invalid_entry": "⚠️  已跳过损坏的历史记录 (%s:%d): %v",
+
+	"daemon.status.none":         "📭 没有守护进程的运行记录 (%s)",
+	"daemon.status.title":        "🗓️  守护进程状态",
+	"daemon.status.stopped":      "⚪ 已停止 (于 %s)",
+	"daemon.status.unresponsive": "🔴 无响应 (最后更新: %s, PID: %d)",
+	"daemon.status.running":      "🟢 运行中 (PID: %d, 启动: %s)",
+	"daemon.status.schedule":     "📄 计划: %s",
+	"daemon.status.last":         "   上次: %s",
+	"daemon.status.error":        "   错误: %s",
+	"daemon.status.output":       "   输出: %s",
+	"daemon.status.next":         "   下次: %s",
+	"daemon.status.missed":       "   跳过: %d次",
+
+	"search.failed":        "搜索失败",
+	"search.speech_failed": "语音生成失败",
+	"search.render_failed": "结果输出失败",
+	"search.no_summary":    "没有可保存的摘要",
+	"search.incomplete":    "⚠️  响应提前结束: %s",
+
+	"daemon.started":           "🗓️  守护进程已启动 (主题: %d个, 输出目录: %s)",
+	"daemon.topic":             "   • %s [%s] 下次: %s",
+	"daemon.catch_up":          "⏪ 执行错过的一次: %s",
+	"daemon.stopped":           "🛑 守护进程已停止",
+	"daemon.skipped_late":      "⏭️  %s 在 %s 的一次已过时，已跳过",
+	"daemon.skipped_overrun":   "⏭️  %s 因执行时间过长，跳过了 %d 次",
+	"daemon.skipped_offline":   "⏭️  %s 在停止期间预定的执行超出补跑期限，已跳过",
+	"daemon.searching":         "🔍 正在搜索 %s: %s",
+	"daemon.failed":            "❌ %s: %v",
+	"daemon.done":              "✅ %s → %s (%s)",
+	"daemon.speech_skipped":    "💸 已省略 %s 的音频: %v",
+	"daemon.speech_failed":     "⚠️  %s 的音频保存失败: %v",
+	"daemon.state_load_failed": "状态文件读取失败",
+	"daemon.state_save_failed": "⚠️  状态文件保存失败: %v",
+	"daemon.output_dir_failed": "无法创建输出目录",
+
+	"server.started":      "🌐 API 服务器已启动: %s",
+	"server.start_failed": "服务器启动失败",
+	"server.write_failed": "⚠️  响应写入失败: %v",
+
+	"config.file_not_found":    "找不到配置文件: %s",
+	"config.file_read":         "无法读取配置文件",
+	"config.unknown_key":       "%s: 未知的配置项: %s",
+	"config.file_format":       "不支持的配置文件格式: %s (请使用 .yaml、.yml 或 .toml)",
+	"config.template_conflict": "不能同时指定模板正文和模板文件 (%s)",
+	"config.template_read":     "无法读取模板文件",
+	"config.no_profiles":       "找不到配置档: %s (配置文件中没有定义配置档)",
+	"config.profile_not_found": "找不到配置档: %s (可用: %s)",
+	"config.profile":           "配置档 %s",
+	"config.invalid_value":     "%s 的值无效: %q",
+	"config.feed_urls":         "feed 后端需要在 feed.urls 中至少指定一个订阅源",
+	"config.feed_max_items":    "feed.max_items 必须至少为 1: %d",
+	"config.backend":           "不支持的搜索后端: %s (支持: %s)",
+	"config.temperature":       "temperature 必须在 0 到 2 之间: %g",
+	"config.speed":             "speed 必须在 0.25 到 4.0 之间: %g",
+	"config.audio_format":      "不支持的音频格式: %s (支持: %s)",
+	"config.tts_command":       "command 后端需要在 tts.command.run 中指定要执行的命令",
+	"config.voicevox_url":      "voicevox 后端需要指定 tts.voicevox.url",
+	"config.voicevox_voice":    "VOICEVOX 的声音必须是数字说话人 ID: %s",
+	"config.tts_backend":       "不支持的语音合成后端: %s (支持: %s)",
+	"config.timeout":           "超时时间必须为正数",
+	"config.fixture_mode":      "不支持的 fixture 模式: %s (支持: %s, %s)",
+	"config.fixture_dir":       "请指定 fixture 目录",
+	"config.max_retries":       "max_retries 必须为 0 或以上: %d",
+	"config.max_retry_wait":    "max_retry_wait 必须为正的时间",
+	"config.language":          "请指定语言 (例如: %s)",
+	"config.recency_days":      "recency_days 必须至少为 1 天: %d",
+	"config.timezone":          "未知的时区: %s",
+	"config.system_prompt":     "系统提示词",
+	"config.query_template":    "查询模板",
+	"config.cache_ttl":         "cache 的有效期必须为 0 或以上",
+	"config.cache_mode":        "不支持的缓存设置: %s",
+	"config.cache_dir":         "请指定缓存目录",
+	"config.budget_limit":      "budget 上限必须为 0 或以上的金额",
+	"config.budget_warn_at":    "budget.warn_at 必须在 0 到 1 之间: %g",
+	"config.budget_pricing":    "要使用 budget，请在 pricing.models 中指定模型 %s 的单价",
+	"config.web_search_price":  "pricing.web_search_per_call 必须为 0 或以上的金额: %g",
+	"config.model_price":       "pricing.models.%s 的单价必须为 0 或以上",
+	"config.speech_price":      "pricing.speech.%s 的单价必须为 0 或以上: %g",
+
+	"transport.retrying":       "⚠️  %s，%s 后重试（第%d次）",
+	"transport.fixture_failed": "⚠️  fixture 保存失败: %v",
+
+	"feed.fetch_failed": "⚠️  无法获取订阅源 %s: %v",
+	"feed.unavailable":  "无法获取任何订阅源",
+
+	"cache.result_failed": "⚠️  搜索结果缓存失败: %v",
+	"cache.speech_failed": "⚠️  音频缓存失败: %v",
+
+	"hint.missing_key":     "💡 提示：请设置 OPENAI_API_KEY 环境变量\n   export OPENAI_API_KEY=\"your-api-key-here\"",
+	"hint.invalid_key":     "💡 提示：API 密钥无效。请检查 OPENAI_API_KEY（或配置文件中的 openai.api_key）\n   https://platform.openai.com/api-keys",
+	"hint.quota":           "💡 提示：已超出使用额度。请检查账单设置和余额\n   https://platform.openai.com/settings/organization/billing",
+	"hint.model_not_found": "💡 提示：模型 %q 不存在或此 API 密钥无法使用。请检查 --model 或配置文件中的 openai.model",
+	"hint.content_policy":  "💡 提示：请求违反了内容政策，未被处理。请换一种说法重试",
+	"hint.rate_limited":    "💡 提示：速率限制未解除。请稍后重试，或通过 batch 的 --workers 和 --interval 降低请求频率",
+	"hint.budget":          "💡 提示：已达到配置文件 budget 中设置的费用上限。请用 usage 命令查看明细，必要时提高上限",
+
+	"help": `📰 News Reporter - 最新新闻搜索
+==================================================
+使用 OpenAI Responses API 和 web_search_preview
+实时搜索最新的新闻和信息。
+
+用法:
+  go run main.go "查询"
+  go run main.go [选项] "查询"
+  go run main.go serve [--addr :8080]
+
+示例:
+  go run main.go --lang zh "今天的财经新闻"
+  go run main.go --lang zh "AI 最新动态"
+  go run main.go --lang zh --stream "今日头条"
+  go run main.go --lang zh --audio "今日头条"
+  go run main.go --lang zh --save summary.mp3 "AI 新闻"
+  go run main.go --lang zh --stream --audio --save summary.mp3 "AI 新闻"
+  go run main.go --lang zh --format json "AI 新闻" > result.json
+  go run main.go --profile chinese "今日市场"
+
+选项:
+  -h, --help                显示此帮助信息
+  -a, --audio               以语音播放摘要
+      --stream              边接收边输出摘要
+  -f, --format <格式>       输出格式 (text, json, markdown, csv, html)
+      --no-history          不将结果保存到历史记录
+      --raw-speech          不规范化文本，直接朗读摘要
+  -s, --save <文件名>       将摘要保存为音频文件
+
+配置选项 (所有命令通用，优先于配置文件和环境变量):
+      --config <文件>       配置文件 (YAML/TOML)
+      --lang <语言>         消息、摘要和语音的语言 (ja, en, zh, ko 等，默认: ja)
+      --profile <名称>      使用配置文件中定义的配置档
+      --backend <名称>      搜索后端 (openai, feed，默认: openai)
+      --model <模型>        搜索和摘要使用的模型 (默认: gpt-4o-mini)
+      --temperature <n>     摘要的温度 (0-2，默认: 0.3)
+      --timeout <时长>      搜索超时 (默认: 60s)
+      --tts-backend <名称>  语音合成后端 (openai, command, voicevox，默认: openai)
+      --tts-model <模型>    语音合成模型 (默认: tts-1)
+      --voice <声音>        语音的声音或 VOICEVOX 的说话人 ID (默认: 取决于语言)
+      --speed <n>           语速 (0.25-4.0，默认: 1.0)
+      --audio-format <fmt>  音频格式 (mp3, opus, aac, flac, wav, pcm)
+      --tts-timeout <时长>  语音合成超时 (默认: 120s)
+      --no-cache            不使用缓存的搜索结果和音频
+      --refresh             忽略缓存重新获取，并更新缓存
+
+子命令:
+  serve                     启动 REST API 服务器
+    --addr <地址>           监听地址 (默认: :8080)
+  history list              列出搜索历史
+    -n, --limit <n>         显示条数 (默认: 20)
+    -q, --query <文本>      按查询筛选
+  history show <id>         显示历史记录详情
+  history replay <id>       重新输出保存的结果 (可使用 --format)
+  usage                     按日、月、主题汇总记录的用量和预估费用
+    --by <单位>             汇总单位 day, month, topic, profile (默认: day)
+    --month <YYYY-MM>       仅指定月份
+    --since <YYYY-MM-DD>    从指定日期开始
+    --until <YYYY-MM-DD>    到指定日期为止 (含当天)
+    --profile <名称>        仅指定的配置档
+    -f, --format <格式>     输出格式 (text, json, csv)
+  batch <文件>              批量搜索文件中的查询 (.txt: 每行一个, .jsonl: {"query": ...})
+    -w, --workers <n>       并行数 (默认: 3)
+    --interval <时长>       搜索之间的最小间隔 (默认: 1s)
+    -o, --out <目录>        输出目录 (默认: batch-<日期时间>)
+    -f, --format <格式>     结果文件格式 (默认: 配置档的格式或 markdown)
+  daemon <schedule.json>    按计划定期搜索
+  daemon status             显示守护进程状态
+    --state <文件>          状态文件 (默认: ~/.news_reporter/daemon_state.json)
+  briefing <主题>...        将多个主题整理为一段语音简报
+    -o, --out <文件>        音频输出位置 (默认: briefing-<日期>.<音频格式>)
+    --script <文件>         稿件输出位置 (默认: 与音频同名的 .txt)
+    --title <标题>          节目名称
+    --topics <文件>         从文件读取主题
+    -w, --workers <n>       同时搜索的主题数 (默认: 3)
+  fixtures serve            启动回放已录制数据的替代服务器
+    --dir <目录>            数据目录 (默认: ~/.news_reporter/fixtures)
+    --addr <地址>           监听地址 (默认: 127.0.0.1:8089)
+  mockserver                启动模拟 OpenAI API 的服务器 (无需密钥和费用即可试用 CLI)
+    --addr <地址>           监听地址 (默认: 127.0.0.1:8090)
+    --delta <文本>          流式返回的摘要文本 (可多次指定)
+    --citation <标题|url>   附加到摘要的引用来源 (可多次指定)
+    --no-citations          不附加引用来源
+    --delay <时长>          各段之间的间隔 (例: 50ms)
+    --api-key <密钥>        拒绝其他密钥的请求 (401)
+
+功能:
+  ✅ 实时网络搜索
+  ✅ 日语、英语、中文、韩语摘要 (--lang)
+  ✅ 附带来源 URL 的结果
+  ⚡ 流式输出
+  📄 JSON/Markdown/CSV/HTML 输出
+  🎵 语音播放和 MP3 导出
+  🌐 REST API 服务器
+  📚 搜索历史
+  📦 批量搜索
+  🗓️  定时简报 (daemon)
+  🎙️  播客式简报
+
+语音:
+  • 使用 OpenAI TTS 的高质量语音
+  • 朗读前去除 Markdown、URL 和引用标记
+  • --audio 和 --save 可同时使用 (一次搜索，一次合成)
+  • 长摘要按句子拆分，并行合成后拼接
+
+REST API:
+  • POST /search        以 JSON 返回结果
+  • POST /search/audio  以音频返回摘要
+  • 请求体: {"query": "..."}
+
+搜索历史:
+  • 结果保存在 ~/.news_reporter/history.jsonl
+  • 可通过 NEWS_REPORTER_HISTORY 更改保存位置
+
+配置文件:
+  • 自动读取 ~/.news_reporter/config.yaml (或 .yml/.toml)
+  • 可通过 --config 或 NEWS_REPORTER_CONFIG 更改位置
+  • prompt 部分可自定义系统提示词和查询模板 (text/template)
+  • 配置档可组合模型、提示词、语言、声音、输出格式和来源
+  • 优先级: 命令行 > 环境变量 > 配置档 > 配置文件 > 默认值
+
+注意: 需要设置 OPENAI_API_KEY 环境变量
+`,
+}
@@ -1,69 +1,731 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"news_reporter/audio"
 	"news_reporter/client"
 	"news_reporter/config"
 	"news_reporter/handlers"
+	"news_reporter/history"
+	"news_reporter/i18n"
+	"news_reporter/mockserver"
+	"news_reporter/scheduler"
+	"news_reporter/server"
+	"news_reporter/transport"
+	"news_reporter/usage"
+)
+
+// 終了コード（スクリプトから失敗の理由を判別できるようにする）
+const (
+	exitError         = 1   // その他のエラー
+	exitInvalidAPIKey = 3   // APIキーが未設定・無効
+	exitQuotaExceeded = 4   // 利用上限（クォータ）を超えた
+	exitModelNotFound = 5   // モデルが見つからない・利用できない
+	exitContentPolicy = 6   // コンテンツポリシーに抵触した
+	exitRateLimited   = 7   // 再送してもレート制限が解除されなかった
+	exitAPIError      = 8   // その他のAPIエラー
+	exitBudget        = 9   // 設定した使用額の上限に達した
+	exitInterrupted   = 130 // Ctrl-C などで中断した（シェルの慣例に合わせる）
 )
 
 func showHelp() {
-	fmt.Println("📰 News Reporter - 最新ニュース検索アプリ")
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Println("OpenAI Responses API と web_search_preview を使用して")
-	fmt.Println("リアルタイムで最新のニュースや情報を検索します。")
-	fmt.Println("")
-	fmt.Println("使用方法:")
-	fmt.Println("  go run main.go \"検索クエリ\"")
-	fmt.Println("  go run main.go [オプション] \"検索クエリ\"")
-	fmt.Println("")
-	fmt.Println("例:")
-	fmt.Println("  go run main.go \"今日の経済ニュース\"")
-	fmt.Println("  go run main.go \"最新のAI技術動向\"")
-	fmt.Println("  go run main.go \"円安ドル高の最新状況\"")
-	fmt.Println("  go run main.go --audio \"今日のニュース\"")
-	fmt.Println("  go run main.go --save summary.mp3 \"AIニュース\"")
-	fmt.Println("")
-	fmt.Println("オプション:")
-	fmt.Println("  -h, --help                このヘルプメッセージを表示")
-	fmt.Println("  -a, --audio               音声再生機能付きで実行")
-	fmt.Println("  -s, --save <filename>     要約を音声ファイルに保存")
-	fmt.Println("")
-	fmt.Println("機能:")
-	fmt.Println("  ✅ リアルタイムWeb検索")
-	fmt.Println("  ✅ 最新情報の自動取得")
-	fmt.Println("  ✅ 日本語での要約表示")
-	fmt.Println("  ✅ 情報源URL付きの結果")
-	fmt.Println("  🎵 音声読み上げ機能")
-	fmt.Println("  💾 音声ファイル保存機能")
-	fmt.Println("")
-	fmt.Println("音声機能について:")
-	fmt.Println("  • OpenAI TTSを使用した高品質な音声合成")
-	fmt.Println("  • 日本語要約の自動読み上げ")
-	fmt.Println("  • MP3形式での音声ファイル保存")
-	fmt.Println("")
-	fmt.Println("注意: OPENAI_API_KEY環境変数の設定が必要です")
+	fmt.Print(i18n.T("help"))
+}
+
+// configFlags コマンドラインで指定された設定（サブコマンドを含め全体で共通）
+var configFlags config.Flags
+
+// extractConfigFlags 引数から設定用のオプションを取り除き configFlags に反映する
+func extractConfigFlags(args []string) []string {
+	targets := map[string]*string{
+		"--config":       &configFlags.ConfigFile,
+		"--profile":      &configFlags.Profile,
+		"--lang":         &configFlags.Language,
+		"--backend":      &configFlags.Backend,
+		"--model":        &configFlags.Model,
+		"--temperature":  &configFlags.Temperature,
+		"--timeout":      &configFlags.SearchTimeout,
+		"--tts-model":    &configFlags.TTSModel,
+		"--voice":        &configFlags.Voice,
+		"--speed":        &configFlags.Speed,
+		"--audio-format": &configFlags.ResponseFormat,
+		"--tts-timeout":  &configFlags.TTSTimeout,
+		"--tts-backend":  &configFlags.TTSBackend,
+	}
+
+	switches := map[string]*bool{
+		"--no-cache": &configFlags.NoCache,
+		"--refresh":  &configFlags.Refresh,
+	}
+
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if target, ok := targets[args[i]]; ok {
+			*target = optionValue(args, &i, "option.value")
+			continue
+		}
+		if target, ok := switches[args[i]]; ok {
+			*target = true
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	return rest
+}
+
+// loadConfig 設定を読み込み、失敗時はヒントを表示して終了
+func loadConfig() *config.Config {
+	cfg, err := config.LoadConfig(configFlags)
+	if err != nil {
+		fmt.Println(i18n.T("main.config_error", err))
+		code, hint := diagnose(nil, err)
+		if hint != "" {
+			fmt.Println()
+			fmt.Println(hint)
+		}
+		os.Exit(code)
+	}
+
+	// プロファイルや設定ファイルで指定された言語でメッセージを表示
+	i18n.SetLanguage(cfg.Language)
+	return cfg
+}
+
+// interruptContext Ctrl-C（SIGINT）と SIGTERM でキャンセルされるコンテキストを作成
+// 1回目のシグナルで処理を中断し、もう一度受け取った場合は通常どおり強制終了する
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// fail エラーを表示し、原因に応じたヒントを添えて終了する
+func fail(cfg *config.Config, err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Println("\n" + i18n.T("main.interrupted"))
+	} else {
+		fmt.Printf("❌ %v\n", err)
+	}
+	code, hint := diagnose(cfg, err)
+	if hint != "" {
+		fmt.Println()
+		fmt.Println(hint)
+	}
+	os.Exit(code)
+}
+
+// diagnose エラーの原因から終了コードとヒントを決める（cfg は設定の読み込みに失敗した場合は nil）
+func diagnose(cfg *config.Config, err error) (int, string) {
+	if errors.Is(err, config.ErrMissingAPIKey) {
+		return exitInvalidAPIKey, i18n.T("hint.missing_key")
+	}
+	if errors.Is(err, context.Canceled) {
+		return exitInterrupted, ""
+	}
+	var budgetErr *usage.BudgetError
+	if errors.As(err, &budgetErr) {
+		return exitBudget, i18n.T("hint.budget")
+	}
+
+	apiErr := apiErrorOf(err)
+	if apiErr == nil {
+		return exitError, ""
+	}
+	switch {
+	case apiErr.InvalidAPIKey():
+		return exitInvalidAPIKey, i18n.T("hint.invalid_key")
+	case apiErr.QuotaExceeded():
+		return exitQuotaExceeded, i18n.T("hint.quota")
+	case apiErr.ModelNotFound():
+		return exitModelNotFound, i18n.T("hint.model_not_found", modelName(cfg, apiErr))
+	case apiErr.ContentPolicy():
+		return exitContentPolicy, i18n.T("hint.content_policy")
+	case apiErr.RateLimited():
+		return exitRateLimited, i18n.T("hint.rate_limited")
+	default:
+		return exitAPIError, ""
+	}
+}
+
+// apiErrorOf エラーから APIError を取り出す（ストリーム中に通知されたエラーも同じように扱う）
+func apiErrorOf(err error) *transport.APIError {
+	var apiErr *transport.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var streamErr *client.StreamError
+	if errors.As(err, &streamErr) {
+		return &transport.APIError{Code: streamErr.Code, Message: streamErr.Message}
+	}
+	return nil
+}
+
+// modelName ヒントに表示するモデル名（メッセージから取り出せなければプロファイルや設定ファイルを反映したモデル）
+func modelName(cfg *config.Config, apiErr *transport.APIError) string {
+	if start := strings.Index(apiErr.Message, "`"); start >= 0 {
+		if end := strings.Index(apiErr.Message[start+1:], "`"); end > 0 {
+			return apiErr.Message[start+1 : start+1+end]
+		}
+	}
+	if cfg != nil {
+		return cfg.Model
+	}
+	return config.DefaultModel
+}
+
+// optionValue オプションの値を取り出す。値がない場合はエラーを表示して終了（what は値の名前のメッセージID）
+func optionValue(args []string, i *int, what string) string {
+	if *i+1 >= len(args) {
+		fmt.Println(i18n.T("main.missing_value", args[*i], i18n.T(what)))
+		os.Exit(1)
+	}
+	*i++
+	return args[*i]
+}
+
+// newSearchHandler 設定から検索ハンドラーを組み立てる
+func newSearchHandler(cfg *config.Config) *handlers.SearchHandler {
+	// 使用量の記録と上限の確認は同じ記録ファイルを共有する
+	ledger := usage.NewLedger(cfg.UsagePath)
+	budget := usage.NewBudget(cfg, ledger)
+
+	// 検索バックエンド（OpenAI または RSS/Atom フィード）を初期化
+	searcher := client.NewSearcher(cfg, budget)
+
+	// TTSクライアントを初期化
+	ttsClient := audio.NewTTSClient(cfg)
+
+	// 検索ハンドラーを初期化
+	searchHandler := handlers.NewSearchHandler(searcher, ttsClient)
+	searchHandler.SetHistory(history.NewStore(cfg.HistoryPath))
+	searchHandler.SetUsage(usage.NewRecorder(cfg, ledger))
+	searchHandler.SetBudget(budget)
+	searchHandler.SetSpeechNormalizer(audio.SpeechNormalizerFor(cfg.Language))
+	return searchHandler
+}
+
+// runServe serveサブコマンド: REST APIサーバーを起動
+func runServe(args []string) {
+	addr := ":8080"
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showHelp()
+			os.Exit(0)
+		case "--addr":
+			addr = optionValue(args, &i, "option.address")
+		default:
+			fmt.Println(i18n.T("main.unknown_option", args[i]))
+			os.Exit(1)
+		}
+	}
+
+	cfg := loadConfig()
+	srv := server.NewServer(addr, newSearchHandler(cfg))
+
+	// シグナル受信時に処理中のリクエストを待って停止
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+		<-sigCh
+
+		fmt.Println("\n" + i18n.T("main.server_stopping"))
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			fmt.Println(i18n.T("main.server_stop_error", err))
+		}
+	}()
+
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runFixtures fixturesサブコマンド: 記録したフィクスチャを返すスタンドインサーバーを起動
+// OPENAI_BASE_URL=http://<addr>/v1 とすると、APIキーなしでオフラインに動作を確認できる
+func runFixtures(args []string) {
+	if len(args) == 0 || args[0] != "serve" {
+		fmt.Println(i18n.T("main.fixtures_command"))
+		os.Exit(1)
+	}
+
+	addr := "127.0.0.1:8089"
+	dir := filepath.Join(config.DataDir(), "fixtures")
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showHelp()
+			os.Exit(0)
+		case "--addr":
+			addr = optionValue(args, &i, "option.address")
+		case "--dir":
+			dir = optionValue(args, &i, "option.directory")
+		default:
+			fmt.Println(i18n.T("main.unknown_option", args[i]))
+			os.Exit(1)
+		}
+	}
+
+	replayer, err := transport.NewReplayer(dir)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(i18n.T("main.fixtures_serving", dir, addr))
+	if err := http.ListenAndServe(addr, replayer); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runMockServer mockserverサブコマンド: OpenAI API を模したモックサーバーを起動
+// OPENAI_BASE_URL=http://<addr>/v1 とすると、APIキーや料金なしで CLI 全体の動作を確認できる
+func runMockServer(args []string) {
+	addr := "127.0.0.1:8090"
+	var opts mockserver.Options
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showHelp()
+			os.Exit(0)
+		case "--addr":
+			addr = optionValue(args, &i, "option.address")
+		case "--delta":
+			opts.Deltas = append(opts.Deltas, optionValue(args, &i, "option.text"))
+		case "--citation":
+			value := optionValue(args, &i, "option.citation")
+			title, url, ok := strings.Cut(value, "|")
+			if !ok || url == "" {
+				fmt.Println(i18n.T("main.invalid_citation", value))
+				os.Exit(1)
+			}
+			opts.Citations = append(opts.Citations, mockserver.Citation{Title: title, URL: url})
+		case "--no-citations":
+			opts.Citations = []mockserver.Citation{}
+		case "--delay":
+			value := optionValue(args, &i, "option.interval")
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				fmt.Println(i18n.T("main.invalid_interval", value, "50ms"))
+				os.Exit(1)
+			}
+			opts.DeltaDelay = d
+		case "--api-key":
+			opts.APIKey = optionValue(args, &i, "option.api_key")
+		default:
+			fmt.Println(i18n.T("main.unknown_option", args[i]))
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println(i18n.T("main.mockserver_start", addr, addr))
+	if err := http.ListenAndServe(addr, mockserver.New(opts)); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runHistory historyサブコマンド: 保存された検索履歴を参照
+func runHistory(args []string) {
+	if len(args) == 0 {
+		fmt.Println(i18n.T("main.history_command"))
+		os.Exit(1)
+	}
+
+	historyHandler := handlers.NewHistoryHandler(history.NewStore(config.HistoryPath()))
+
+	command := args[0]
+	limit := 20
+	var filter string
+	format := handlers.FormatText
+	var positional []string
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--limit", "-n":
+			value := optionValue(args, &i, "option.count")
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				fmt.Println(i18n.T("main.invalid_count", value))
+				os.Exit(1)
+			}
+			limit = n
+		case "--query", "-q":
+			filter = optionValue(args, &i, "option.filter")
+		case "--format", "-f":
+			format = optionValue(args, &i, "option.format")
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	var err error
+	switch command {
+	case "list":
+		err = historyHandler.List(limit, filter)
+	case "show", "replay":
+		if len(positional) != 1 {
+			fmt.Println(i18n.T("main.history_id", command))
+			os.Exit(1)
+		}
+		if command == "show" {
+			err = historyHandler.Show(positional[0])
+			break
+		}
+		renderer, rendererErr := handlers.NewRenderer(format)
+		if rendererErr != nil {
+			fmt.Println(i18n.T("main.error", rendererErr))
+			os.Exit(1)
+		}
+		err = historyHandler.Replay(positional[0], renderer)
+	default:
+		fmt.Println(i18n.T("main.unknown_history_command", command))
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runUsage usageサブコマンド: 記録された使用量と概算費用を日・月・トピックごとに集計
+func runUsage(args []string) {
+	// --profile は全コマンド共通の設定オプションとして取り除かれているため、ここで絞り込みに使う
+	opts := handlers.UsageReportOptions{Group: usage.GroupDay}
+	opts.Filter.Profile = configFlags.Profile
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showHelp()
+			os.Exit(0)
+		case "--by":
+			opts.Group = strings.ToLower(optionValue(args, &i, "option.group"))
+		case "--month":
+			value := optionValue(args, &i, "option.month")
+			month, err := time.ParseInLocation("2006-01", value, time.Local)
+			if err != nil {
+				fmt.Println(i18n.T("main.invalid_month", value))
+				os.Exit(1)
+			}
+			opts.Filter.Since = month
+			opts.Filter.Until = month.AddDate(0, 1, 0)
+		case "--since":
+			opts.Filter.Since = parseDate(optionValue(args, &i, "option.date"))
+		case "--until":
+			// 指定した日を含める
+			opts.Filter.Until = parseDate(optionValue(args, &i, "option.date")).AddDate(0, 0, 1)
+		case "--format", "-f":
+			opts.Format = optionValue(args, &i, "option.format")
+		default:
+			fmt.Println(i18n.T("main.unknown_option", args[i]))
+			os.Exit(1)
+		}
+	}
+
+	usageHandler := handlers.NewUsageHandler(usage.NewLedger(config.UsagePath()))
+	if err := usageHandler.Report(opts); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseDate YYYY-MM-DD 形式の日付を解釈（不正な場合は終了）
+func parseDate(value string) time.Time {
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		fmt.Println(i18n.T("main.invalid_date", value))
+		os.Exit(1)
+	}
+	return date
+}
+
+// runBatch batchサブコマンド: ファイルのクエリをまとめて検索
+func runBatch(args []string) {
+	opts := handlers.BatchOptions{
+		Workers:   3,
+		Interval:  time.Second,
+		OutputDir: "batch-" + time.Now().Format("20060102-150405"),
+	}
+	var positional []string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showHelp()
+			os.Exit(0)
+		case "--workers", "-w":
+			value := optionValue(args, &i, "option.workers")
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				fmt.Println(i18n.T("main.invalid_workers", value))
+				os.Exit(1)
+			}
+			opts.Workers = n
+		case "--interval":
+			value := optionValue(args, &i, "option.interval")
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				fmt.Println(i18n.T("main.invalid_interval", value, "500ms, 2s"))
+				os.Exit(1)
+			}
+			opts.Interval = d
+		case "--out", "-o":
+			opts.OutputDir = optionValue(args, &i, "option.directory")
+		case "--format", "-f":
+			opts.Format = optionValue(args, &i, "option.format")
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	if len(positional) != 1 {
+		fmt.Println(i18n.T("main.batch_file"))
+		os.Exit(1)
+	}
+
+	queries, err := handlers.ReadBatchQueries(positional[0])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg := loadConfig()
+
+	// --format がなければプロファイルの出力形式、それもなければ markdown
+	if opts.Format == "" {
+		opts.Format = cfg.Format
+	}
+	if opts.Format == "" {
+		opts.Format = handlers.FormatMarkdown
+	}
+	if _, err := handlers.NewRenderer(opts.Format); err != nil {
+		fmt.Println(i18n.T("main.error", err))
+		os.Exit(1)
+	}
+
+	batchHandler := handlers.NewBatchHandler(newSearchHandler(cfg))
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	results, err := batchHandler.Run(ctx, queries, opts)
+	if err != nil {
+		fail(cfg, err)
+	}
+
+	// 1件でも失敗があればスクリプトから検知できるよう終了コードで通知（最初の失敗の理由を使う）
+	for _, result := range results {
+		if result.Err != nil {
+			code, hint := diagnose(cfg, result.Err)
+			if hint != "" {
+				fmt.Println()
+				fmt.Println(hint)
+			}
+			os.Exit(code)
+		}
+	}
+}
+
+// runDaemon daemonサブコマンド: スケジュールに従って定期検索
+func runDaemon(args []string) {
+	statePath := config.DaemonStatePath()
+	var positional []string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showHelp()
+			os.Exit(0)
+		case "--state":
+			statePath = optionValue(args, &i, "option.file")
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	if len(positional) == 1 && positional[0] == "status" {
+		if err := handlers.ShowDaemonStatus(os.Stdout, statePath); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(positional) != 1 {
+		fmt.Println(i18n.T("main.daemon_file"))
+		os.Exit(1)
+	}
+
+	schedule, err := scheduler.LoadSchedule(positional[0])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg := loadConfig()
+	daemonHandler := handlers.NewDaemonHandler(newSearchHandler(cfg), schedule, positional[0], statePath)
+
+	// シグナル受信時は実行中の検索の完了を待って停止（もう一度受け取ると強制終了）
+	ctx, stop := interruptContext()
+	defer stop()
+
+	if err := daemonHandler.Run(ctx); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runBriefing briefingサブコマンド: 複数トピックを1本の音声ブリーフィングにまとめる
+func runBriefing(args []string) {
+	date := time.Now().Format("20060102")
+	opts := handlers.BriefingOptions{
+		Workers: 3,
+	}
+	var scriptPath string
+	var topicsFile string
+	var queries []string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			showHelp()
+			os.Exit(0)
+		case "--out", "-o":
+			opts.OutputPath = optionValue(args, &i, "option.file")
+		case "--script":
+			scriptPath = optionValue(args, &i, "option.file")
+		case "--title":
+			opts.Title = optionValue(args, &i, "option.title")
+		case "--topics":
+			topicsFile = optionValue(args, &i, "option.file")
+		case "--workers", "-w":
+			value := optionValue(args, &i, "option.workers")
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				fmt.Println(i18n.T("main.invalid_workers", value))
+				os.Exit(1)
+			}
+			opts.Workers = n
+		default:
+			queries = append(queries, args[i])
+		}
+	}
+
+	if topicsFile != "" {
+		topics, err := handlers.ReadBatchQueries(topicsFile)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		for _, topic := range topics {
+			queries = append(queries, topic.Query)
+		}
+	}
+	if len(queries) == 0 {
+		fmt.Println(i18n.T("main.briefing_topics"))
+		os.Exit(1)
+	}
+
+	cfg := loadConfig()
+
+	// 既定のファイル名の拡張子は音声合成バックエンドの出力形式に合わせる
+	if opts.OutputPath == "" {
+		opts.OutputPath = "briefing-" + date + "." + cfg.AudioFormat()
+	}
+
+	// 原稿は音声と同じ名前のテキストファイルにも保存
+	if scriptPath == "" {
+		scriptPath = strings.TrimSuffix(opts.OutputPath, filepath.Ext(opts.OutputPath)) + ".txt"
+	}
+	opts.ScriptPath = scriptPath
+	if opts.Title == "" {
+		opts.Title = i18n.T("briefing.default_title")
+	}
+	briefingHandler := handlers.NewBriefingHandler(newSearchHandler(cfg))
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	if _, err := briefingHandler.Run(ctx, queries, opts); err != nil {
+		fail(cfg, err)
+	}
+
+	fmt.Println("\n" + i18n.T("briefing.done"))
 }
 
 func main() {
 	// 使用方法を表示する関数
 	showUsage := showHelp
 
+	// 設定用のオプションはサブコマンドの前後どちらでも指定できる
+	os.Args = append(os.Args[:1], extractConfigFlags(os.Args[1:])...)
+
+	// 設定を読み込む前のメッセージ（ヘルプなど）も指定された言語で表示
+	if configFlags.Language != "" {
+		i18n.SetLanguage(configFlags.Language)
+	} else if lang := os.Getenv("NEWS_REPORTER_LANG"); lang != "" {
+		i18n.SetLanguage(lang)
+	}
+
 	// コマンドライン引数をチェック
 	if len(os.Args) < 2 {
 		showUsage()
-		fmt.Println("❌ エラー: 検索クエリが指定されていません")
+		fmt.Println(i18n.T("main.no_query"))
 		os.Exit(1)
 	}
 
+	// サブコマンドを判定
+	switch os.Args[1] {
+	case "serve":
+		runServe(os.Args[2:])
+		return
+	case "history":
+		runHistory(os.Args[2:])
+		return
+	case "usage":
+		runUsage(os.Args[2:])
+		return
+	case "batch":
+		runBatch(os.Args[2:])
+		return
+	case "daemon":
+		runDaemon(os.Args[2:])
+		return
+	case "briefing":
+		runBriefing(os.Args[2:])
+		return
+	case "fixtures":
+		runFixtures(os.Args[2:])
+		return
+	case "mockserver":
+		runMockServer(os.Args[2:])
+		return
+	}
+
 	// フラグとクエリの解析
-	var audioMode bool
-	var saveMode bool
-	var saveFilename string
+	var opts handlers.SearchOptions
+	var noHistory bool
+	var rawSpeech bool
+	var format string
 	var query string
 	var args []string
 
@@ -75,16 +737,17 @@ func main() {
 			showUsage()
 			os.Exit(0)
 		case "--audio", "-a":
-			audioMode = true
+			opts.PlayAudio = true
+		case "--stream":
+			opts.Stream = true
 		case "--save", "-s":
-			saveMode = true
-			if i+1 < len(os.Args) {
-				saveFilename = os.Args[i+1]
-				i++ // 次の引数をスキップ
-			} else {
-				fmt.Println("❌ エラー: --save オプションにはファイル名が必要です")
-				os.Exit(1)
-			}
+			opts.SaveAudio = optionValue(os.Args, &i, "option.file")
+		case "--format", "-f":
+			format = optionValue(os.Args, &i, "option.format")
+		case "--no-history":
+			noHistory = true
+		case "--raw-speech":
+			rawSpeech = true
 		default:
 			args = append(args, arg)
 		}
@@ -94,49 +757,49 @@ func main() {
 	query = strings.Join(args, " ")
 	if strings.TrimSpace(query) == "" {
 		showUsage()
-		fmt.Println("❌ エラー: 空の検索クエリです")
+		fmt.Println(i18n.T("main.empty_query"))
 		os.Exit(1)
 	}
 
 	// 設定を読み込み
-	cfg, err := config.LoadConfig()
+	cfg := loadConfig()
+
+	// 出力形式を検証（--format がなければプロファイルの出力形式）
+	if format == "" {
+		format = cfg.Format
+	}
+	if format == "" {
+		format = handlers.FormatText
+	}
+	renderer, err := handlers.NewRenderer(format)
 	if err != nil {
-		fmt.Printf("❌ 設定エラー: %v\n", err)
-		fmt.Println()
-		fmt.Println("💡 ヒント: OPENAI_API_KEY環境変数を設定してください")
-		fmt.Println("   export OPENAI_API_KEY=\"your-api-key-here\"")
+		fmt.Println(i18n.T("main.error", err))
 		os.Exit(1)
 	}
 
-	// OpenAIクライアントを初期化
-	openaiClient := client.NewOpenAIClient(cfg)
+	// 検索ハンドラーを初期化
+	searchHandler := newSearchHandler(cfg)
+	searchHandler.SetRenderer(renderer)
+	if noHistory {
+		searchHandler.SetHistory(nil)
+	}
+	if rawSpeech {
+		searchHandler.SetSpeechNormalizer(nil)
+	}
 
-	// TTSクライアントを初期化
-	ttsClient := audio.NewTTSClient(cfg)
+	// Ctrl-C で検索・音声の生成・再生を中断し、途中までの結果を表示する
+	ctx, stop := interruptContext()
+	defer stop()
 
-	// 検索ハンドラーを初期化
-	searchHandler := handlers.NewSearchHandler(openaiClient, ttsClient)
+	// 検索 → 表示 → 音声保存 → 音声再生 を1回の検索結果で実行
+	if err := searchHandler.Run(ctx, query, opts); err != nil {
+		fail(cfg, err)
+	}
 
-	// モードに応じて実行
-	if saveMode {
-		// 音声ファイル保存モード
-		if err := searchHandler.SaveAudioSummary(query, saveFilename); err != nil {
-			fmt.Printf("❌ %v\n", err)
-			os.Exit(1)
-		}
-	} else if audioMode {
-		// 音声再生モード
-		if err := searchHandler.HandleSearchWithAudio(query); err != nil {
-			fmt.Printf("❌ %v\n", err)
-			os.Exit(1)
-		}
+	// テキスト以外の形式では標準出力を結果のみにする
+	if _, ok := renderer.(*handlers.TextRenderer); ok {
+		fmt.Println("\n" + i18n.T("main.done"))
 	} else {
-		// 通常の検索モード
-		if err := searchHandler.HandleSearch(query); err != nil {
-			fmt.Printf("❌ %v\n", err)
-			os.Exit(1)
-		}
+		fmt.Fprintln(os.Stderr, "\n"+i18n.T("main.done"))
 	}
-
-	fmt.Println("\n✅ 処理が完了しました！")
 }
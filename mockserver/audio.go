@@ -0,0 +1,53 @@
+package mockserver
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	// mp3FrameSize MPEG-1 Layer III・128kbps・44.1kHz・パディングなしの1フレームのバイト数
+	mp3FrameSize = 417
+	// mp3FrameSamples 1フレームあたりのサンプル数
+	mp3FrameSamples = 1152
+	// sampleRate 返す音声のサンプリング周波数
+	sampleRate = 44100
+	// defaultSpeechFrames /audio/speech が返すフレーム数の既定値（約0.5秒）
+	defaultSpeechFrames = 20
+	// maxSpeechInput /audio/speech が受け付ける入力の最大文字数（OpenAI API と同じ）
+	maxSpeechInput = 4096
+)
+
+// mp3FrameHeader MPEG-1 Layer III・CRCなし・128kbps・44.1kHz・ジョイントステレオのフレームヘッダー
+var mp3FrameHeader = []byte{0xFF, 0xFB, 0x90, 0x44}
+
+// SilentMP3 frames フレーム分の無音のMP3（サイド情報・メインデータがすべて0のフレームは無音として復号できる）
+func SilentMP3(frames int) []byte {
+	data := make([]byte, 0, frames*mp3FrameSize)
+	frame := make([]byte, mp3FrameSize)
+	copy(frame, mp3FrameHeader)
+	for i := 0; i < frames; i++ {
+		data = append(data, frame...)
+	}
+	return data
+}
+
+// SilentWAV samples サンプル分の無音のWAV（16bit・モノラル・44.1kHz）
+func SilentWAV(samples int) []byte {
+	dataSize := samples * 2
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))         // fmt チャンクのサイズ
+	binary.Write(&buf, binary.LittleEndian, uint16(1))          // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1))          // チャンネル数
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate)) // サンプリング周波数
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(2))  // ブロックサイズ
+	binary.Write(&buf, binary.LittleEndian, uint16(16)) // ビット深度
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
@@ -0,0 +1,345 @@
+// Package mockserver OpenAI API の /v1/responses と /v1/audio/speech を模したローカル開発・テスト用のサーバー
+//
+// APIキーや料金なしで CLI 全体を動かせるように、決まった要約と url_citation 注釈をストリーミングで返し、
+// 音声合成には無音の小さな MP3 を返す
+//
+//	srv := httptest.NewServer(mockserver.New(mockserver.Options{}))
+//	// OPENAI_BASE_URL=srv.URL + "/v1"
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"news_reporter/models"
+)
+
+// DefaultModel リクエストでモデルが指定されなかった場合に返すモデル名
+const DefaultModel = "gpt-4o-mini-mock"
+
+// maxRequestBody 受け付けるリクエスト本文の上限
+const maxRequestBody = 1 << 20
+
+// Citation 要約に付ける url_citation 注釈
+type Citation struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// DefaultCitations Options.Citations を省略した場合の引用元
+var DefaultCitations = []Citation{
+	{Title: "モックニュース: 今日の主な出来事", URL: "https://example.com/news/today"},
+	{Title: "モックニュース: 解説記事", URL: "https://example.org/analysis"},
+}
+
+// Options モックサーバーの応答内容
+type Options struct {
+	// Deltas 要約として順に送るテキストの断片（省略時はクエリを含む決まった文章）
+	Deltas []string
+	// Citations 要約に付ける引用元（省略時は DefaultCitations。空のスライスを指定すると引用なし）
+	Citations []Citation
+	// DeltaDelay デルタを送る間隔（ストリーミング表示の確認用。0 の場合は待たない）
+	DeltaDelay time.Duration
+	// APIKey 指定した場合、このキー以外のリクエストには 401 invalid_api_key を返す
+	APIKey string
+	// SpeechFrames /audio/speech が返す MP3 のフレーム数（省略時は約0.5秒分）
+	SpeechFrames int
+}
+
+// Request モックサーバーが受け取ったリクエスト（テストでの確認用）
+type Request struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// Server OpenAI API を模した http.Handler
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+// New モックサーバーを作成
+func New(opts Options) *Server {
+	if opts.Citations == nil {
+		opts.Citations = DefaultCitations
+	}
+	if opts.SpeechFrames <= 0 {
+		opts.SpeechFrames = defaultSpeechFrames
+	}
+
+	s := &Server{opts: opts, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/v1/responses", s.handleResponses)
+	s.mux.HandleFunc("/v1/audio/speech", s.handleSpeech)
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "invalid_request_error", "unknown_url", fmt.Sprintf("Unrecognized request URL (%s %s) in mock server", r.Method, r.URL.Path))
+	})
+	return s
+}
+
+// ServeHTTP リクエストを記録して各エンドポイントに振り分ける
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", err.Error())
+		return
+	}
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Body: body})
+	seq := len(s.requests)
+	s.mu.Unlock()
+
+	w.Header().Set("X-Request-Id", fmt.Sprintf("req_mock_%d", seq))
+	if s.opts.APIKey != "" && r.Header.Get("Authorization") != "Bearer "+s.opts.APIKey {
+		writeError(w, http.StatusUnauthorized, "invalid_request_error", "invalid_api_key", "Incorrect API key provided (mock server).")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// Requests これまでに受け取ったリクエスト
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// handleResponses POST /v1/responses 要約と引用元を Responses API の形式でストリーミングする
+func (s *Server) handleResponses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "", "Only POST is supported")
+		return
+	}
+	var request models.ResponseRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid JSON body: "+err.Error())
+		return
+	}
+	if !request.Stream {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "The mock server only supports stream: true")
+		return
+	}
+
+	model := request.Model
+	if model == "" {
+		model = DefaultModel
+	}
+	deltas := s.opts.Deltas
+	if len(deltas) == 0 {
+		deltas = defaultDeltas(userInput(request.Input))
+	}
+
+	stream := newEventStream(w)
+	response := models.ResponseObject{
+		ID:        fmt.Sprintf("resp_mock_%d", time.Now().UnixNano()),
+		Object:    "response",
+		CreatedAt: time.Now().Unix(),
+		Status:    "in_progress",
+		Model:     model,
+	}
+	stream.send(&models.ResponseLifecycleEvent{ResponseEvent: stream.next(models.EventResponseCreated), Response: response})
+	stream.send(&models.ResponseLifecycleEvent{ResponseEvent: stream.next(models.EventResponseInProgress), Response: response})
+
+	// Web検索ツールが指定されている場合は検索の進行を通知する
+	output := 0
+	if len(request.Tools) > 0 {
+		for _, eventType := range []string{models.EventWebSearchCallInProgress, models.EventWebSearchCallSearching, models.EventWebSearchCallCompleted} {
+			stream.send(&models.WebSearchCallEvent{ResponseEvent: stream.next(eventType), ItemID: "ws_mock", OutputIndex: output})
+		}
+		output++
+	}
+
+	// 引用は要約全体に均等に割り振り、対応するデルタの直後に送る
+	citationAfter := make(map[int][]int)
+	for i := range s.opts.Citations {
+		after := (i+1)*len(deltas)/len(s.opts.Citations) - 1
+		citationAfter[after] = append(citationAfter[after], i)
+	}
+
+	var text strings.Builder
+	for i, delta := range deltas {
+		if s.opts.DeltaDelay > 0 {
+			select {
+			case <-time.After(s.opts.DeltaDelay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		start := utf8.RuneCountInString(text.String())
+		text.WriteString(delta)
+		stream.send(&models.OutputTextDeltaEvent{ResponseEvent: stream.next(models.EventOutputTextDelta), ItemID: "msg_mock", OutputIndex: output, Delta: delta})
+
+		for _, index := range citationAfter[i] {
+			citation := s.opts.Citations[index]
+			stream.send(&models.OutputTextAnnotationEvent{
+				ResponseEvent:   stream.next(models.EventOutputTextAnnotation),
+				ItemID:          "msg_mock",
+				OutputIndex:     output,
+				AnnotationIndex: index,
+				Annotation: models.Annotation{
+					Type:       "url_citation",
+					URL:        citation.URL,
+					Title:      citation.Title,
+					StartIndex: start,
+					EndIndex:   utf8.RuneCountInString(text.String()),
+				},
+			})
+		}
+	}
+	stream.send(&struct {
+		models.ResponseEvent
+		ItemID string `json:"item_id"`
+		Text   string `json:"text"`
+	}{ResponseEvent: stream.next(models.EventOutputTextDone), ItemID: "msg_mock", Text: text.String()})
+
+	response.Status = "completed"
+	response.Usage = &models.Usage{
+		InputTokens:  estimateTokens(request.Input),
+		OutputTokens: utf8.RuneCountInString(text.String()),
+	}
+	response.Usage.TotalTokens = response.Usage.InputTokens + response.Usage.OutputTokens
+	stream.send(&models.ResponseLifecycleEvent{ResponseEvent: stream.next(models.EventResponseCompleted), Response: response})
+}
+
+// speechRequest /v1/audio/speech のリクエスト本文
+type speechRequest struct {
+	Model  string  `json:"model"`
+	Input  string  `json:"input"`
+	Voice  string  `json:"voice"`
+	Format string  `json:"response_format"`
+	Speed  float64 `json:"speed"`
+}
+
+// handleSpeech POST /v1/audio/speech 無音の音声を返す
+func (s *Server) handleSpeech(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "", "Only POST is supported")
+		return
+	}
+	var request speechRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid JSON body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(request.Input) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Missing required parameter: 'input'.")
+		return
+	}
+	if utf8.RuneCountInString(request.Input) > maxSpeechInput {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "string_above_max_length", fmt.Sprintf("'input' is too long: %d characters (max %d)", utf8.RuneCountInString(request.Input), maxSpeechInput))
+		return
+	}
+
+	switch request.Format {
+	case "", "mp3":
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write(SilentMP3(s.opts.SpeechFrames))
+	case "wav":
+		w.Header().Set("Content-Type", "audio/wav")
+		w.Write(SilentWAV(s.opts.SpeechFrames * mp3FrameSamples))
+	case "pcm":
+		w.Header().Set("Content-Type", "audio/L16")
+		w.Write(make([]byte, s.opts.SpeechFrames*mp3FrameSamples*2))
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", fmt.Sprintf("The mock server does not support response_format %q (mp3, wav, pcm)", request.Format))
+	}
+}
+
+// userInput 最後のユーザーメッセージ（検索クエリ）
+func userInput(input []models.InputItem) string {
+	for i := len(input) - 1; i >= 0; i-- {
+		if input[i].Role == "user" {
+			return input[i].Content
+		}
+	}
+	return ""
+}
+
+// defaultDeltas Deltas を省略した場合の要約（クエリを含めて、どのリクエストへの応答か分かるようにする）
+func defaultDeltas(query string) []string {
+	if query == "" {
+		query = "ニュース"
+	}
+	return []string{
+		"「" + query + "」に関するモックの要約です。",
+		"これはローカルのモックサーバーが返した文章で、",
+		"実際のニュースではありません。",
+		"APIキーや料金なしで動作を確認できます。",
+	}
+}
+
+// estimateTokens 入力のトークン数の目安（文字数で代用する）
+func estimateTokens(input []models.InputItem) int {
+	total := 0
+	for _, item := range input {
+		total += utf8.RuneCountInString(item.Content)
+	}
+	return total
+}
+
+// eventStream Server-Sent Events の書き出し
+type eventStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	seq     int
+}
+
+func newEventStream(w http.ResponseWriter) *eventStream {
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	return &eventStream{w: w, flusher: flusher}
+}
+
+// next 次に送るイベントの共通部分（type・sequence_number）
+func (e *eventStream) next(eventType string) models.ResponseEvent {
+	event := models.ResponseEvent{Type: eventType, SequenceNumber: e.seq}
+	e.seq++
+	return event
+}
+
+// send イベントを1件送る（event は models.ResponseEvent を埋め込んだ構造体）
+func (e *eventStream) send(event interface{}) {
+	data, _ := json.Marshal(event)
+	var header models.ResponseEvent
+	json.Unmarshal(data, &header)
+
+	fmt.Fprintf(e.w, "event: %s\ndata: %s\n\n", header.Type, data)
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+}
+
+// writeError OpenAI API と同じ {"error": {...}} 形式でエラーを返す
+func writeError(w http.ResponseWriter, status int, errType, code, message string) {
+	body := map[string]interface{}{
+		"error": map[string]interface{}{
+			"message": message,
+			"type":    errType,
+			"param":   nil,
+			"code":    nilIfEmpty(code),
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func nilIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,137 @@
+package mockserver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/go-mp3"
+
+	"news_reporter/models"
+)
+
+func post(t *testing.T, url, apiKey, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestResponsesStream(t *testing.T) {
+	server := httptest.NewServer(New(Options{
+		Deltas:    []string{"一つ目。", "二つ目。", "三つ目。"},
+		Citations: []Citation{{Title: "A", URL: "https://example.com/a"}},
+	}))
+	defer server.Close()
+
+	resp := post(t, server.URL+"/v1/responses", "", `{"model":"gpt-4o-mini","stream":true,"tools":[{"type":"web_search_preview"}],"input":[{"type":"message","role":"user","content":"q"}]}`)
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	var types []string
+	var annotation models.OutputTextAnnotationEvent
+	var completed models.ResponseLifecycleEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event models.ResponseEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatal(err)
+		}
+		if event.SequenceNumber != len(types) {
+			t.Errorf("sequence_number = %d, want %d", event.SequenceNumber, len(types))
+		}
+		types = append(types, event.Type)
+		switch event.Type {
+		case models.EventOutputTextAnnotation:
+			json.Unmarshal([]byte(data), &annotation)
+		case models.EventResponseCompleted:
+			json.Unmarshal([]byte(data), &completed)
+		}
+	}
+
+	if types[0] != models.EventResponseCreated || types[len(types)-1] != models.EventResponseCompleted {
+		t.Errorf("events = %v", types)
+	}
+	// 引用は最後のデルタ（三つ目。）の範囲を指す
+	if annotation.Annotation.URL != "https://example.com/a" || annotation.Annotation.StartIndex != 8 || annotation.Annotation.EndIndex != 12 {
+		t.Errorf("annotation = %+v", annotation.Annotation)
+	}
+	if completed.Response.Model != "gpt-4o-mini" || completed.Response.Usage == nil || completed.Response.Usage.OutputTokens != 12 {
+		t.Errorf("completed response = %+v", completed.Response)
+	}
+}
+
+func TestSpeech(t *testing.T) {
+	server := httptest.NewServer(New(Options{}))
+	defer server.Close()
+
+	resp := post(t, server.URL+"/v1/audio/speech", "", `{"model":"tts-1","input":"こんにちは","voice":"alloy","response_format":"mp3"}`)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "audio/mpeg" {
+		t.Fatalf("status = %d, Content-Type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	data, _ := io.ReadAll(resp.Body)
+	decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("response is not MP3: %v", err)
+	}
+	if n, err := io.Copy(io.Discard, decoder); err != nil || n == 0 {
+		t.Errorf("decoded %d bytes: %v", n, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	server := httptest.NewServer(New(Options{APIKey: "sk-mock"}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		apiKey     string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"wrong key", "/v1/responses", "sk-wrong", `{"stream":true}`, http.StatusUnauthorized, "invalid_api_key"},
+		{"unknown path", "/v1/models", "sk-mock", `{}`, http.StatusNotFound, "unknown_url"},
+		{"unsupported format", "/v1/audio/speech", "sk-mock", `{"input":"a","response_format":"opus"}`, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := post(t, server.URL+tt.path, tt.apiKey, tt.body)
+			var body struct {
+				Error struct {
+					Code    *string `json:"code"`
+					Message string  `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			code := ""
+			if body.Error.Code != nil {
+				code = *body.Error.Code
+			}
+			if resp.StatusCode != tt.wantStatus || code != tt.wantCode || body.Error.Message == "" {
+				t.Errorf("status = %d, error = %+v", resp.StatusCode, body.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+// ChatRequest Chat Completions APIへのリクエスト構造体
+// Ollama や vLLM などの互換サーバーでも受け付けられる項目だけを使う
+type ChatRequest struct {
+	Model         string             `json:"model"`
+	Messages      []Message          `json:"messages"`
+	Stream        bool               `json:"stream,omitempty"`
+	StreamOptions *ChatStreamOptions `json:"stream_options,omitempty"`
+	Temperature   *float64           `json:"temperature,omitempty"`
+}
+
+// ChatStreamOptions ストリーミングのオプション
+type ChatStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // 最後のチャンクで使用量を受け取る
+}
+
+// ChatChunk ストリーミングで届く1チャンク（delta に差分のテキストが入る）
+type ChatChunk struct {
+	ID      string     `json:"id"`
+	Model   string     `json:"model"`
+	Choices []Choice   `json:"choices"`
+	Usage   *ChatUsage `json:"usage,omitempty"`
+}
+
+// ChatUsage Chat Completions APIの使用量情報
+type ChatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
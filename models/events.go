@@ -0,0 +1,98 @@
+package models
+
+// Responses APIのストリーミングイベント種別
+const (
+	EventResponseCreated         = "response.created"
+	EventResponseInProgress      = "response.in_progress"
+	EventResponseCompleted       = "response.completed"
+	EventResponseFailed          = "response.failed"
+	EventResponseIncomplete      = "response.incomplete"
+	EventOutputTextDelta         = "response.output_text.delta"
+	EventOutputTextDone          = "response.output_text.done"
+	EventOutputTextAnnotation    = "response.output_text.annotation.added"
+	EventWebSearchCallInProgress = "response.web_search_call.in_progress"
+	EventWebSearchCallSearching  = "response.web_search_call.searching"
+	EventWebSearchCallCompleted  = "response.web_search_call.completed"
+	EventError                   = "error"
+
+	// EventWebSearchCallPrefix response.web_search_call.* イベントの共通接頭辞
+	EventWebSearchCallPrefix = "response.web_search_call."
+)
+
+// ResponseEvent ストリーミングイベントの共通部分
+type ResponseEvent struct {
+	Type           string `json:"type"`
+	SequenceNumber int    `json:"sequence_number,omitempty"`
+}
+
+// ResponseObject イベントに含まれるレスポンス本体
+type ResponseObject struct {
+	ID                string             `json:"id"`
+	Object            string             `json:"object"`
+	CreatedAt         int64              `json:"created_at"`
+	Status            string             `json:"status"`
+	Model             string             `json:"model"`
+	Error             *ResponseError     `json:"error,omitempty"`
+	IncompleteDetails *IncompleteDetails `json:"incomplete_details,omitempty"`
+	Usage             *Usage             `json:"usage,omitempty"`
+}
+
+// ResponseError レスポンス失敗時のエラー詳細
+type ResponseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// IncompleteDetails レスポンスが途中で終了した理由
+type IncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+// ResponseLifecycleEvent response.created / completed / failed / incomplete イベント
+type ResponseLifecycleEvent struct {
+	ResponseEvent
+	Response ResponseObject `json:"response"`
+}
+
+// OutputTextDeltaEvent response.output_text.delta イベント
+type OutputTextDeltaEvent struct {
+	ResponseEvent
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	Delta        string `json:"delta"`
+}
+
+// Annotation 出力テキストに付与された注釈
+type Annotation struct {
+	Type       string `json:"type"`
+	URL        string `json:"url,omitempty"`
+	Title      string `json:"title,omitempty"`
+	StartIndex int    `json:"start_index,omitempty"`
+	EndIndex   int    `json:"end_index,omitempty"`
+}
+
+// OutputTextAnnotationEvent response.output_text.annotation.added イベント
+type OutputTextAnnotationEvent struct {
+	ResponseEvent
+	ItemID          string     `json:"item_id"`
+	OutputIndex     int        `json:"output_index"`
+	ContentIndex    int        `json:"content_index"`
+	AnnotationIndex int        `json:"annotation_index"`
+	Annotation      Annotation `json:"annotation"`
+}
+
+// WebSearchCallEvent response.web_search_call.* イベント
+type WebSearchCallEvent struct {
+	ResponseEvent
+	ItemID      string `json:"item_id"`
+	OutputIndex int    `json:"output_index"`
+}
+
+// ErrorEvent error イベント
+type ErrorEvent struct {
+	ResponseEvent
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"param"`
+}
@@ -9,7 +9,7 @@ type ResponseRequest struct {
 	Tools       []Tool      `json:"tools,omitempty"`
 	ToolChoice  string      `json:"tool_choice,omitempty"`
 	Stream      bool        `json:"stream,omitempty"`
-	Temperature float64     `json:"temperature,omitempty"`
+	Temperature *float64    `json:"temperature,omitempty"`
 }
 
 // InputItem 入力アイテム
@@ -90,22 +90,58 @@ type WebSearchResult struct {
 
 // Usage 使用量情報
 type Usage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	InputTokens    int `json:"input_tokens"`
+	OutputTokens   int `json:"output_tokens"`
+	TotalTokens    int `json:"total_tokens"`
+	WebSearchCalls int `json:"web_search_calls,omitempty"` // 実行されたWeb検索の回数
 }
 
+// Cost 料金表から見積もった費用（USD）
+type Cost struct {
+	Input     float64 `json:"input"`
+	Output    float64 `json:"output"`
+	WebSearch float64 `json:"web_search,omitempty"`
+	Total     float64 `json:"total"`
+}
+
+// StreamEventType ストリーミングイベントの種類
+type StreamEventType string
+
+const (
+	// StreamEventCreated レスポンスの生成開始
+	StreamEventCreated StreamEventType = "created"
+	// StreamEventWebSearch Web検索の進行状況
+	StreamEventWebSearch StreamEventType = "web_search"
+	// StreamEventDelta 要約テキストの差分
+	StreamEventDelta StreamEventType = "delta"
+	// StreamEventCitation 新しい引用元
+	StreamEventCitation StreamEventType = "citation"
+	// StreamEventDone ストリーム完了
+	StreamEventDone StreamEventType = "done"
+)
+
 // StreamEvent ストリーミングイベント
 type StreamEvent struct {
-	Type      string                 `json:"type"`
-	Data      map[string]interface{} `json:"data,omitempty"`
-	Timestamp time.Time              `json:"timestamp,omitempty"`
+	Type StreamEventType `json:"type"`
+	// Status Web検索の状態（in_progress, searching, completed）
+	Status    string           `json:"status,omitempty"`
+	Delta     string           `json:"delta,omitempty"`
+	Citation  *WebSearchResult `json:"citation,omitempty"`
+	Timestamp time.Time        `json:"timestamp,omitempty"`
 }
 
+// StreamCallback ストリーミングイベントを受け取るコールバック
+type StreamCallback func(event StreamEvent)
+
 // SearchResult 検索結果の統合表現
 type SearchResult struct {
 	Query     string            `json:"query"`
 	Results   []WebSearchResult `json:"results"`
 	Summary   string            `json:"summary,omitempty"`
 	Timestamp time.Time         `json:"timestamp"`
+	Model     string            `json:"model,omitempty"`
+	Usage     *Usage            `json:"usage,omitempty"`
+	Cost      *Cost             `json:"cost,omitempty"`    // 料金表にないモデルでは省略
+	Partial   bool              `json:"partial,omitempty"` // 中断されたため途中までの内容
+	Cached    bool              `json:"cached,omitempty"`  // キャッシュから取り出した結果（Timestamp は取得した時刻）
 }
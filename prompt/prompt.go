@@ -0,0 +1,172 @@
+package prompt
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"news_reporter/i18n"
+)
+
+// 組み込みテンプレートの種類
+const (
+	KindSystem   = "system"   // 検索用のシステムプロンプト
+	KindQuery    = "query"    // 検索クエリの強化
+	KindBriefing = "briefing" // ブリーフィング原稿の指示
+	KindFeed     = "feed"     // フィード記事を要約する際のシステムプロンプト
+)
+
+// templates 言語ごとの組み込みテンプレート（templates/<言語>/<種類>.tmpl）
+//
+//go:embed templates
+var templates embed.FS
+
+// DefaultTemplate 言語に対応する組み込みテンプレートを返す（未対応の言語は英語）
+func DefaultTemplate(kind, lang string) string {
+	for _, candidate := range []string{i18n.Normalize(lang), i18n.English} {
+		data, err := templates.ReadFile("templates/" + candidate + "/" + kind + ".tmpl")
+		if err == nil {
+			return string(data)
+		}
+	}
+	return ""
+}
+
+// Vars テンプレートで使える変数
+type Vars struct {
+	Query        string    // ユーザーの検索クエリ
+	Now          time.Time // 現在時刻（Timezone のタイムゾーン）
+	Date         string    // 現在の日付（例: 2024年1月15日）
+	Time         string    // 現在の時刻（例: 15:04）
+	Timezone     string    // タイムゾーン名
+	Language     string    // 要約の言語コード（例: ja）
+	LanguageName string    // 要約の言語名（例: 日本語）
+	RecencyDays  int       // 最新とみなす期間（日数）
+	OutputLength string    // 要約の長さの目安（例: 300字程度）
+	Audience     string    // 想定読者（例: 経営層）
+	Sources      []string  // 優先して参照する情報源
+}
+
+// NewVars 現在時刻・タイムゾーン・言語から変数を組み立てる
+func NewVars(query string, now time.Time, loc *time.Location, lang string) Vars {
+	if loc == nil {
+		loc = time.Local
+	}
+	now = now.In(loc)
+	if lang == "" {
+		lang = i18n.Japanese
+	}
+
+	// ローカル時刻の場合は "Local" ではなく JST のような略称を使う
+	timezone := loc.String()
+	if loc == time.Local {
+		timezone, _ = now.Zone()
+	}
+
+	return Vars{
+		Query:        query,
+		Now:          now,
+		Date:         now.Format(i18n.Lookup(lang, "layout.date")),
+		Time:         now.Format("15:04"),
+		Timezone:     timezone,
+		Language:     lang,
+		LanguageName: LanguageName(lang),
+		RecencyDays:  7,
+	}
+}
+
+// funcs テンプレートで使える関数（期間の表記は言語に合わせる）
+func funcs(lang string) template.FuncMap {
+	return template.FuncMap{
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"period": func(days int) string {
+			return period(lang, days)
+		},
+		"date": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+	}
+}
+
+// Parse テンプレートを解析する（設定の読み込み時に誤りを検出するため）
+func Parse(name, text string) (*template.Template, error) {
+	return parse(name, text, i18n.Japanese)
+}
+
+// parse 指定した言語の関数を使ってテンプレートを解析する
+func parse(name, text, lang string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(funcs(lang)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("テンプレートの解析に失敗しました: %w", err)
+	}
+	return tmpl, nil
+}
+
+// System システムプロンプトを生成する（text が空の場合は言語に対応する組み込みのテンプレート）
+func System(text string, vars Vars) (string, error) {
+	return Render(KindSystem, text, vars)
+}
+
+// Query 検索クエリを強化する（text が空の場合は言語に対応する組み込みのテンプレート）
+func Query(text string, vars Vars) (string, error) {
+	return Render(KindQuery, text, vars)
+}
+
+// Render テンプレートを解析して変数を埋め込む（text が空の場合は kind の組み込みテンプレート）
+func Render(kind, text string, vars Vars) (string, error) {
+	if text == "" {
+		text = DefaultTemplate(kind, vars.Language)
+	}
+
+	tmpl, err := parse(kind, text, vars.Language)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("テンプレートの展開に失敗しました: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// period 日数を「1週間」「1か月」のような読みやすい期間に変換
+func period(lang string, days int) string {
+	switch {
+	case days <= 0:
+		return i18n.Plural(lang, "period.day", 1)
+	case days%30 == 0:
+		return i18n.Plural(lang, "period.month", days/30)
+	case days%7 == 0:
+		return i18n.Plural(lang, "period.week", days/7)
+	default:
+		return i18n.Plural(lang, "period.day", days)
+	}
+}
+
+// languageNames 言語コードと言語名（その言語自身での表記）の対応
+var languageNames = map[string]string{
+	"ja": "日本語",
+	"en": "English",
+	"zh": "中文",
+	"ko": "한국어",
+	"fr": "Français",
+	"de": "Deutsch",
+	"es": "Español",
+}
+
+// LanguageName 言語コードをプロンプト用の言語名に変換（未知のコードはそのまま）
+func LanguageName(code string) string {
+	code = i18n.Normalize(code)
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	if code == "" {
+		return languageNames[i18n.Japanese]
+	}
+	return code
+}
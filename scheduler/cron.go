@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule 5フィールド（分 時 日 月 曜日）のcron式
+type CronSchedule struct {
+	expr   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// 日と曜日の両方が指定された場合はどちらかに一致すれば実行する（cronの慣例）
+	domRestricted bool
+	dowRestricted bool
+}
+
+// cronField フィールドごとの取りうる値の範囲
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteField = cronField{"minute", 0, 59}
+	hourField   = cronField{"hour", 0, 23}
+	domField    = cronField{"day of month", 1, 31}
+	monthField  = cronField{"month", 1, 12}
+	dowField    = cronField{"day of week", 0, 7}
+)
+
+// cronAliases よく使う定義済みスケジュール
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron cron式を解析する
+// 各フィールドは *, 数値, 範囲(1-5), 列挙(1,3,5), 間隔(*/15, 0-30/10) に対応
+func ParseCron(expr string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	if alias, ok := cronAliases[spec]; ok {
+		spec = alias
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	schedule := &CronSchedule{expr: expr}
+	targets := []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&schedule.minute, minuteField},
+		{&schedule.hour, hourField},
+		{&schedule.dom, domField},
+		{&schedule.month, monthField},
+		{&schedule.dow, dowField},
+	}
+
+	for i, target := range targets {
+		bits, err := parseCronField(fields[i], target.field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		*target.bits = bits
+	}
+
+	// 曜日の 7 は日曜日（0）として扱う
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+		schedule.dow &^= 1 << 7
+	}
+
+	schedule.domRestricted = fields[2] != "*"
+	schedule.dowRestricted = fields[4] != "*"
+
+	return schedule, nil
+}
+
+// parseCronField 1フィールドを解析してビット集合に変換
+func parseCronField(spec string, field cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(spec, ",") {
+		rangeSpec, stepSpec, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepSpec)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepSpec, field.name)
+			}
+			step = n
+		}
+
+		var low, high int
+		switch {
+		case rangeSpec == "*":
+			low, high = field.min, field.max
+		case strings.Contains(rangeSpec, "-"):
+			lowSpec, highSpec, _ := strings.Cut(rangeSpec, "-")
+			var err error
+			if low, err = parseCronValue(lowSpec, field); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(highSpec, field); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangeSpec, field.name)
+			}
+		default:
+			value, err := parseCronValue(rangeSpec, field)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			high = value
+			// "5/10" は 5 から最大値まで10刻みを意味する
+			if hasStep {
+				high = field.max
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// parseCronValue 単一の値を解析して範囲を検証
+func parseCronValue(spec string, field cronField) (int, error) {
+	value, err := strconv.Atoi(spec)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", spec, field.name)
+	}
+	if value < field.min || value > field.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d] in %s field", value, field.min, field.max, field.name)
+	}
+	return value, nil
+}
+
+// String 元のcron式を返す
+func (s *CronSchedule) String() string {
+	return s.expr
+}
+
+// Next t より後で最初に実行すべき時刻を返す（見つからない場合はゼロ値）
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	// 分単位に切り捨てた次の分から探索
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(5, 0, 0)
+
+	for next.Before(limit) {
+		if s.month&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !s.matchDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if s.hour&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			continue
+		}
+		if s.minute&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+
+	return time.Time{}
+}
+
+// matchDay 日付が日・曜日フィールドに一致するか判定
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domRestricted && s.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
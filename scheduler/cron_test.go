@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"*/15 9-17 * * 1-5",
+		"0,30 8 1,15 * *",
+		"0-30/10 * * 1-12/3 0,7",
+		"@daily",
+		"@hourly",
+	}
+	for _, expr := range valid {
+		if _, err := ParseCron(expr); err != nil {
+			t.Errorf("ParseCron(%q) error: %v", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every",
+	}
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	// 2024-01-01 は月曜日
+	tests := []struct {
+		name string
+		expr string
+		from string
+		want string
+	}{
+		{"every minute", "* * * * *", "2024-01-01 10:07", "2024-01-01 10:08"},
+		{"step", "*/15 * * * *", "2024-01-01 10:07", "2024-01-01 10:15"},
+		{"step wraps to next hour", "*/15 * * * *", "2024-01-01 10:45", "2024-01-01 11:00"},
+		{"step within range", "0-30/10 * * * *", "2024-01-01 10:21", "2024-01-01 10:30"},
+		{"step within range wraps", "0-30/10 * * * *", "2024-01-01 10:31", "2024-01-01 11:00"},
+		{"step from value", "5/20 * * * *", "2024-01-01 10:26", "2024-01-01 10:45"},
+		{"list", "0,30 8 * * *", "2024-01-01 08:00", "2024-01-01 08:30"},
+		{"weekday range skips weekend", "0 9 * * 1-5", "2024-01-05 10:00", "2024-01-08 09:00"},
+		{"sunday as 7", "0 0 * * 7", "2024-01-01 00:00", "2024-01-07 00:00"},
+		{"day of month or day of week (weekday first)", "0 0 13 * 5", "2024-01-01 00:00", "2024-01-05 00:00"},
+		{"day of month or day of week (day first)", "0 0 13 * 5", "2024-01-12 00:00", "2024-01-13 00:00"},
+		{"day of month only", "0 0 13 * *", "2024-01-01 00:00", "2024-01-13 00:00"},
+		{"month rollover", "0 0 1 * *", "2024-01-31 12:00", "2024-02-01 00:00"},
+		{"skips short months", "30 23 31 * *", "2024-01-31 23:45", "2024-03-31 23:30"},
+		{"leap day", "0 0 29 2 *", "2024-03-01 00:00", "2028-02-29 00:00"},
+		{"year rollover", "@yearly", "2024-06-15 00:00", "2025-01-01 00:00"},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := schedule.Next(at(tt.from)); !got.Equal(at(tt.want)) {
+			t.Errorf("%s: Next(%s) for %q = %s, want %s", tt.name, tt.from, tt.expr, got.Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+
+	// 存在しない日付は見つからない
+	schedule, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := schedule.Next(at("2024-01-01 00:00")); !got.IsZero() {
+		t.Errorf("Next for Feb 30 = %s, want zero time", got)
+	}
+}
+
+func TestTopicNextAfter(t *testing.T) {
+	schedule, err := ParseCron("*/10 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic := &Topic{Name: "test", schedule: schedule}
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		want        time.Time
+		wantSkipped int
+	}{
+		{"finished in time", base.Add(3 * time.Minute), base.Add(10 * time.Minute), 0},
+		{"finished on the next run", base.Add(10 * time.Minute), base.Add(10 * time.Minute), 0},
+		{"overran one run", base.Add(25 * time.Minute), base.Add(20 * time.Minute), 1},
+		{"overran several runs", base.Add(47 * time.Minute), base.Add(40 * time.Minute), 3},
+	}
+	for _, tt := range tests {
+		next, skipped := topic.NextAfter(base, tt.now)
+		if !next.Equal(tt.want) || skipped != tt.wantSkipped {
+			t.Errorf("%s: NextAfter = %s, %d; want %s, %d", tt.name, next.Format("15:04"), skipped, tt.want.Format("15:04"), tt.wantSkipped)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Topic 定期実行する検索トピック
+type Topic struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	Cron  string `json:"cron"`
+	// Audio true の場合は要約をMP3ファイルとしても保存する
+	Audio bool `json:"audio,omitempty"`
+	// Format 結果ファイルの出力形式（省略時はスケジュール全体の設定）
+	Format string `json:"format,omitempty"`
+
+	schedule *CronSchedule
+}
+
+// Schedule スケジュールファイルの内容
+type Schedule struct {
+	Topics []*Topic `json:"topics"`
+	// OutputDir 結果ファイルの出力先
+	OutputDir string `json:"output_dir,omitempty"`
+	// Format 結果ファイルのデフォルト出力形式
+	Format string `json:"format,omitempty"`
+	// Timezone cron式を評価するタイムゾーン（例: Asia/Tokyo）
+	Timezone string `json:"timezone,omitempty"`
+	// CatchUp 停止中に実行されなかった回を起動時に取り戻す期間（例: 2h）
+	CatchUp string `json:"catch_up,omitempty"`
+
+	location    *time.Location
+	catchUpSpan time.Duration
+}
+
+// LoadSchedule スケジュールファイル（JSON）を読み込んで検証する
+func LoadSchedule(path string) (*Schedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule file: %w", err)
+	}
+
+	var schedule Schedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule file %s: %w", path, err)
+	}
+
+	if err := schedule.init(); err != nil {
+		return nil, fmt.Errorf("invalid schedule file %s: %w", path, err)
+	}
+
+	return &schedule, nil
+}
+
+// init デフォルト値を補完し、cron式などを解析する
+func (s *Schedule) init() error {
+	if len(s.Topics) == 0 {
+		return errors.New("no topics defined")
+	}
+	if s.OutputDir == "" {
+		s.OutputDir = "briefings"
+	}
+
+	s.location = time.Local
+	if s.Timezone != "" {
+		location, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+		}
+		s.location = location
+	}
+
+	s.catchUpSpan = time.Hour
+	if s.CatchUp != "" {
+		span, err := time.ParseDuration(s.CatchUp)
+		if err != nil || span < 0 {
+			return fmt.Errorf("invalid catch_up %q", s.CatchUp)
+		}
+		s.catchUpSpan = span
+	}
+
+	names := make(map[string]bool)
+	for i, topic := range s.Topics {
+		if topic.Name == "" {
+			return fmt.Errorf("topic #%d: name is required", i+1)
+		}
+		if names[topic.Name] {
+			return fmt.Errorf("topic %q: duplicate name", topic.Name)
+		}
+		names[topic.Name] = true
+
+		if topic.Query == "" {
+			return fmt.Errorf("topic %q: query is required", topic.Name)
+		}
+		cron, err := ParseCron(topic.Cron)
+		if err != nil {
+			return fmt.Errorf("topic %q: %w", topic.Name, err)
+		}
+		topic.schedule = cron
+
+		if topic.Format == "" {
+			topic.Format = s.Format
+		}
+	}
+
+	return nil
+}
+
+// Location cron式を評価するタイムゾーンを返す
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// CatchUpSpan 取りこぼした回を取り戻す期間を返す
+func (s *Schedule) CatchUpSpan() time.Duration {
+	return s.catchUpSpan
+}
+
+// Next t より後でこのトピックを実行すべき時刻を返す
+func (t *Topic) Next(after time.Time) time.Time {
+	return t.schedule.Next(after)
+}
+
+// maxSkipScan NextAfter で飛ばす回を数える上限
+const maxSkipScan = 10000
+
+// NextAfter prev の回の次に実行すべき時刻と、now までに過ぎたため飛ばす回数を返す
+// 実行が長引くなどして複数の回が過ぎていた場合は最後の回だけを実行し、それより前の回を飛ばす
+func (t *Topic) NextAfter(prev, now time.Time) (time.Time, int) {
+	next := t.Next(prev)
+	skipped := 0
+	for !next.IsZero() && skipped < maxSkipScan {
+		following := t.Next(next)
+		if following.IsZero() || following.After(now) {
+			break
+		}
+		next = following
+		skipped++
+	}
+	return next, skipped
+}
+
+// TopicState トピックごとの実行状況
+type TopicState struct {
+	Cron       string    `json:"cron"`
+	LastRun    time.Time `json:"last_run,omitempty"`
+	LastStatus string    `json:"last_status,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+	LastOutput string    `json:"last_output,omitempty"`
+	NextRun    time.Time `json:"next_run,omitempty"`
+	// Missed 実行されずにスキップされた回数（取り戻し期間を過ぎた回・実行が長引いて過ぎた回）
+	Missed int `json:"missed,omitempty"`
+}
+
+// 実行結果の状態
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusRunning = "running"
+)
+
+// State デーモンの状態（status コマンドから参照される）
+type State struct {
+	PID          int                    `json:"pid"`
+	ScheduleFile string                 `json:"schedule_file"`
+	StartedAt    time.Time              `json:"started_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+	StoppedAt    time.Time              `json:"stopped_at,omitempty"`
+	Topics       map[string]*TopicState `json:"topics"`
+}
+
+// LoadState 状態ファイルを読み込む。存在しない場合は空の状態を返す
+func LoadState(path string) (*State, error) {
+	state := &State{Topics: make(map[string]*TopicState)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if state.Topics == nil {
+		state.Topics = make(map[string]*TopicState)
+	}
+
+	return state, nil
+}
+
+// Save 状態ファイルを書き込む（一時ファイル経由で置き換える）
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"news_reporter/handlers"
+	"news_reporter/i18n"
+	"news_reporter/usage"
+)
+
+const (
+	// maxRequestBodySize リクエストボディの上限サイズ
+	maxRequestBodySize = 64 * 1024
+	// maxQueryLength 検索クエリの最大文字数
+	maxQueryLength = 500
+)
+
+// SearchRequest 検索APIのリクエストボディ
+type SearchRequest struct {
+	Query string `json:"query"`
+}
+
+// ErrorResponse エラー時のレスポンスボディ
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+type Server struct {
+	searchHandler *handlers.SearchHandler
+	httpServer    *http.Server
+}
+
+// NewServer 新しいHTTPサーバーを作成
+func NewServer(addr string, searchHandler *handlers.SearchHandler) *Server {
+	s := &Server{
+		searchHandler: searchHandler,
+	}
+
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s
+}
+
+// Handler APIのルーティングを設定したハンドラーを返す
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", s.handleSearch)
+	mux.HandleFunc("/search/audio", s.handleSearchAudio)
+	mux.HandleFunc("/healthz", s.handleHealth)
+	return logRequests(mux)
+}
+
+// ListenAndServe サーバーを起動
+func (s *Server) ListenAndServe() error {
+	log.Print(i18n.T("server.started", s.httpServer.Addr))
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", i18n.T("server.start_failed"), err)
+	}
+	return nil
+}
+
+// Shutdown 処理中のリクエストを待ってサーバーを停止
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// handleSearch POST /search 検索結果をJSONで返す
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query, ok := s.parseSearchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// クライアントが切断した場合は検索を中断する
+	result, err := s.searchHandler.Search(r.Context(), query)
+	if err != nil {
+		writeError(w, searchErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleSearchAudio POST /search/audio 検索結果の要約を音声で返す
+func (s *Server) handleSearchAudio(w http.ResponseWriter, r *http.Request) {
+	query, ok := s.parseSearchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := s.searchHandler.Search(r.Context(), query)
+	if err != nil {
+		writeError(w, searchErrorStatus(err), err.Error())
+		return
+	}
+
+	audioData, err := s.searchHandler.SynthesizeSummary(r.Context(), result)
+	if errors.Is(err, handlers.ErrNoSummary) {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if errors.Is(err, usage.ErrSpeechSkipped) {
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", s.searchHandler.AudioContentType())
+	w.Header().Set("Content-Length", fmt.Sprint(len(audioData)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(audioData); err != nil {
+		log.Print(i18n.T("server.write_failed", err))
+	}
+}
+
+// handleHealth GET /healthz ヘルスチェック
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// parseSearchRequest リクエストを検証して検索クエリを取り出す
+func (s *Server) parseSearchRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return "", false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return "", false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var req SearchRequest
+	if err := decoder.Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return "", false
+		}
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return "", false
+	}
+
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		writeError(w, http.StatusUnprocessableEntity, "query is required")
+		return "", false
+	}
+	if utf8.RuneCountInString(query) > maxQueryLength {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("query must be at most %d characters", maxQueryLength))
+		return "", false
+	}
+
+	return query, true
+}
+
+// writeJSON JSONレスポンスを書き込む
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Print(i18n.T("server.write_failed", err))
+	}
+}
+
+// searchErrorStatus 検索のエラーに対応するステータスコード
+// 使用額の上限に達した場合はクライアントに時間をおいて再試行させるため 429 を返す
+func searchErrorStatus(err error) int {
+	var budgetErr *usage.BudgetError
+	if errors.As(err, &budgetErr) {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusBadGateway
+}
+
+// writeError エラーレスポンスを書き込む
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
+}
+
+// statusRecorder レスポンスのステータスコードを記録する
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests アクセスログを出力するミドルウェア
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		log.Printf("%s %s %d %s", r.Method, r.URL.Path, recorder.status, time.Since(start).Round(time.Millisecond))
+	})
+}
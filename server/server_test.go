@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"news_reporter/audio"
+	"news_reporter/config"
+	"news_reporter/handlers"
+	"news_reporter/mockserver"
+	"news_reporter/models"
+	"news_reporter/prompt"
+	"news_reporter/usage"
+)
+
+// fakeSearcher クエリをそのまま要約として返す検索バックエンド（err を設定するとエラーを返す）
+type fakeSearcher struct {
+	err     error
+	summary string
+	queries []string
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, query string) (*models.SearchResult, error) {
+	return f.SearchStream(ctx, query, nil)
+}
+
+func (f *fakeSearcher) SearchStream(ctx context.Context, query string, onEvent models.StreamCallback) (*models.SearchResult, error) {
+	f.queries = append(f.queries, query)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.SearchResult{Query: query, Summary: f.summary, Timestamp: time.Now()}, nil
+}
+
+func (f *fakeSearcher) Generate(ctx context.Context, instructions, input string) (*models.SearchResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeSearcher) PromptVars(query string) prompt.Vars {
+	return prompt.Vars{Query: query}
+}
+
+// newTestServer fake を検索バックエンドにしたサーバーを作成（音声合成はモックサーバーに接続する）
+func newTestServer(t *testing.T, fake *fakeSearcher) http.Handler {
+	t.Helper()
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	mock := httptest.NewServer(mockserver.New(mockserver.Options{}))
+	t.Cleanup(mock.Close)
+	cfg := config.Default()
+	cfg.BaseURL = mock.URL + "/v1"
+	cfg.Voice = config.DefaultVoice
+	cfg.CacheDir = t.TempDir()
+
+	h := handlers.NewSearchHandler(fake, audio.NewTTSClient(cfg))
+	return NewServer("127.0.0.1:0", h).Handler()
+}
+
+// post path に body をPOSTしてレスポンスを返す
+func post(handler http.Handler, path, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleSearchErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		err         error
+		status      int
+		message     string
+	}{
+		{"wrong method", http.MethodGet, "application/json", `{"query":"AI"}`, nil, http.StatusMethodNotAllowed, "method not allowed"},
+		{"missing content type", http.MethodPost, "", `{"query":"AI"}`, nil, http.StatusUnsupportedMediaType, "Content-Type must be application/json"},
+		{"wrong content type", http.MethodPost, "text/plain", `{"query":"AI"}`, nil, http.StatusUnsupportedMediaType, "Content-Type must be application/json"},
+		{"body too large", http.MethodPost, "application/json", `{"query":"` + strings.Repeat("a", maxRequestBodySize) + `"}`, nil, http.StatusRequestEntityTooLarge, "request body too large"},
+		{"bad json", http.MethodPost, "application/json", `{"query":`, nil, http.StatusBadRequest, "invalid request body"},
+		{"unknown field", http.MethodPost, "application/json", `{"q":"AI"}`, nil, http.StatusBadRequest, "invalid request body"},
+		{"empty query", http.MethodPost, "application/json", `{"query":"  "}`, nil, http.StatusUnprocessableEntity, "query is required"},
+		{"query too long", http.MethodPost, "application/json", `{"query":"` + strings.Repeat("あ", maxQueryLength+1) + `"}`, nil, http.StatusUnprocessableEntity, "query must be at most 500 characters"},
+		{"upstream error", http.MethodPost, "application/json", `{"query":"AI"}`, errors.New("upstream unavailable"), http.StatusBadGateway, "upstream unavailable"},
+		{"budget exceeded", http.MethodPost, "application/json", `{"query":"AI"}`, &usage.BudgetError{}, http.StatusTooManyRequests, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSearcher{err: tt.err}
+			req := httptest.NewRequest(tt.method, "/search", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			newTestServer(t, fake).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode error response: %v", err)
+			}
+			if !strings.Contains(resp.Error, tt.message) {
+				t.Errorf("error = %q, want %q", resp.Error, tt.message)
+			}
+			// 不正なリクエストは検索しない
+			if tt.err == nil && len(fake.queries) != 0 {
+				t.Errorf("searched %v for an invalid request", fake.queries)
+			}
+		})
+	}
+}
+
+func TestHandleSearch(t *testing.T) {
+	fake := &fakeSearcher{summary: "今日のニュースです。"}
+	rec := post(newTestServer(t, fake), "/search", "application/json; charset=utf-8", `{"query":"  AI ニュース "}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	var result models.SearchResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Query != "AI ニュース" || result.Summary != "今日のニュースです。" {
+		t.Errorf("result = %+v", result)
+	}
+	if len(fake.queries) != 1 || fake.queries[0] != "AI ニュース" {
+		t.Errorf("queries = %q, want the trimmed query", fake.queries)
+	}
+}
+
+func TestHandleSearchAudio(t *testing.T) {
+	rec := post(newTestServer(t, &fakeSearcher{summary: "今日のニュースです。"}), "/search/audio", "application/json", `{"query":"AI"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "audio/mpeg" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if rec.Body.Len() == 0 {
+		t.Error("empty audio")
+	}
+
+	// 要約がなければ読み上げられない
+	rec = post(newTestServer(t, &fakeSearcher{}), "/search/audio", "application/json", `{"query":"AI"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("no summary: status = %d, want 422: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestHandleHealth(t *testing.T) {
+	handler := newTestServer(t, &fakeSearcher{})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"ok"`) {
+		t.Errorf("GET /healthz = %d %s", rec.Code, rec.Body)
+	}
+
+	rec = post(handler, "/healthz", "application/json", "{}")
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodGet {
+		t.Errorf("POST /healthz = %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
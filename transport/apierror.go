@@ -0,0 +1,121 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIError OpenAI API が返したエラー（{"error": {...}} 形式の本文を解析したもの）
+// 互換サーバーなどで本文を解析できない場合は Message に本文をそのまま入れる
+type APIError struct {
+	StatusCode int
+	Type       string // 例: invalid_request_error
+	Code       string // 例: invalid_api_key, insufficient_quota, model_not_found
+	Param      string // 誤りのあったパラメーター（例: model）
+	Message    string
+	RequestID  string // x-request-id ヘッダー（問い合わせ用）
+}
+
+// errorEnvelope エラーレスポンスの本文
+type errorEnvelope struct {
+	Error *struct {
+		Type    string          `json:"type"`
+		Code    json.RawMessage `json:"code"` // 文字列または数値
+		Param   *string         `json:"param"`
+		Message string          `json:"message"`
+	} `json:"error"`
+}
+
+// NewAPIError 成功以外のレスポンスから APIError を作成（本文は読み切って閉じる）
+func NewAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return ParseAPIError(resp.StatusCode, resp.Header, body)
+}
+
+// ParseAPIError ステータスコード・ヘッダー・本文から APIError を組み立てる
+func ParseAPIError(statusCode int, header http.Header, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		RequestID:  header.Get("X-Request-Id"),
+	}
+
+	var envelope errorEnvelope
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Error != nil {
+		apiErr.Type = envelope.Error.Type
+		apiErr.Code = strings.Trim(string(envelope.Error.Code), `"`)
+		if apiErr.Code == "null" {
+			apiErr.Code = ""
+		}
+		if envelope.Error.Param != nil {
+			apiErr.Param = *envelope.Error.Param
+		}
+		apiErr.Message = envelope.Error.Message
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(body))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "API error (status %d", e.StatusCode)
+	if code := e.code(); code != "" {
+		b.WriteString(", " + code)
+	}
+	b.WriteString("): " + e.Message)
+	if e.RequestID != "" {
+		b.WriteString(" [request ID: " + e.RequestID + "]")
+	}
+	return b.String()
+}
+
+// code エラーの種類を表す文字列（code がなければ type）
+func (e *APIError) code() string {
+	if e.Code != "" {
+		return e.Code
+	}
+	return e.Type
+}
+
+// InvalidAPIKey APIキーが無効・失効している
+func (e *APIError) InvalidAPIKey() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.Code == "invalid_api_key"
+}
+
+// QuotaExceeded 利用上限（クォータ）を超えている
+func (e *APIError) QuotaExceeded() bool {
+	return e.Code == "insufficient_quota" || e.Type == "insufficient_quota" || e.Code == "billing_hard_limit_reached"
+}
+
+// ModelNotFound 指定したモデルが存在しないか利用できない
+func (e *APIError) ModelNotFound() bool {
+	if e.Code == "model_not_found" {
+		return true
+	}
+	return e.StatusCode == http.StatusNotFound && e.Param == "model"
+}
+
+// ContentPolicy 入力がコンテンツポリシーに抵触した
+func (e *APIError) ContentPolicy() bool {
+	switch e.Code {
+	case "content_policy_violation", "content_filter", "invalid_prompt":
+		return true
+	}
+	return false
+}
+
+// RateLimited 一時的なレート制限（クォータ不足を除く）
+func (e *APIError) RateLimited() bool {
+	if e.QuotaExceeded() {
+		return false
+	}
+	return e.StatusCode == http.StatusTooManyRequests || e.Code == "rate_limit_exceeded"
+}
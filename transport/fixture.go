@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Fixture 記録した1回分のリクエストとレスポンス（1ファイルに1件のJSONで保存する）
+type Fixture struct {
+	Method      string      `json:"method"`
+	Path        string      `json:"path"`              // 例: /v1/responses
+	RequestHash string      `json:"request_sha256"`    // リクエスト本文のハッシュ（再生時の照合に使う）
+	Request     string      `json:"request,omitempty"` // リクエスト本文（確認用。照合には使わない）
+	StatusCode  int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`      // レスポンスヘッダー
+	Body        string      `json:"body,omitempty"`        // テキストの本文（SSE・JSON）
+	BodyBase64  []byte      `json:"body_base64,omitempty"` // バイナリの本文（音声など）
+}
+
+// recordedHeaders 記録するレスポンスヘッダー（Set-Cookie などは残さない）
+var recordedHeaders = []string{
+	"Content-Type",
+	"X-Request-Id",
+	"Retry-After",
+	"Retry-After-Ms",
+	"X-Ratelimit-Remaining-Requests",
+	"X-Ratelimit-Remaining-Tokens",
+	"X-Ratelimit-Reset-Requests",
+	"X-Ratelimit-Reset-Tokens",
+}
+
+// ResponseBody 記録したレスポンス本文
+func (f *Fixture) ResponseBody() []byte {
+	if f.BodyBase64 != nil {
+		return f.BodyBase64
+	}
+	return []byte(f.Body)
+}
+
+// setResponseBody 本文がテキストであれば文字列のまま、そうでなければBase64で保存する
+func (f *Fixture) setResponseBody(body []byte) {
+	if isText(f.Header.Get("Content-Type")) && utf8.Valid(body) {
+		f.Body = string(body)
+		f.BodyBase64 = nil
+		return
+	}
+	f.Body = ""
+	f.BodyBase64 = body
+}
+
+// isText 本文をそのまま読める形式で保存する Content-Type か判定
+func isText(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// hashBody リクエスト本文のハッシュ（先頭16桁）
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// fixtureName 記録するファイル名（連番-メソッド-パス-ハッシュ.json）
+func fixtureName(seq int, f *Fixture) string {
+	slug := strings.Trim(strings.ReplaceAll(f.Path, "/", "_"), "_")
+	if slug == "" {
+		slug = "root"
+	}
+	return fmt.Sprintf("%04d-%s-%s-%s.json", seq, strings.ToLower(f.Method), slug, f.RequestHash)
+}
+
+// LoadFixtures ディレクトリ内のフィクスチャをファイル名順（記録順）に読み込む
+func LoadFixtures(dir string) ([]*Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	fixtures := make([]*Fixture, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("%s: invalid fixture: %w", path, err)
+		}
+		fixtures = append(fixtures, &fixture)
+	}
+	return fixtures, nil
+}
+
+// WriteFixture フィクスチャをJSONファイルとして保存
+func WriteFixture(path string, f *Fixture) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
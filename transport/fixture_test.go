@@ -0,0 +1,163 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// roundTrip rt でリクエストを送って本文を読み切る
+func roundTrip(t *testing.T, rt http.RoundTripper, method, url, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, data
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	audioData := []byte{0xFF, 0xFB, 0x90, 0x44, 0x00, 0x80, 0xFF}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_123")
+		w.Header().Set("Set-Cookie", "session=secret")
+		switch r.URL.Path {
+		case "/v1/responses":
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, "event: response.created\ndata: {}\n\n")
+			w.(http.Flusher).Flush()
+			io.WriteString(w, "data: [DONE]\n\n")
+		case "/v1/audio/speech":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			w.Write(audioData)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := NewRecordTransport(nil, dir)
+	_, stream := roundTrip(t, recorder, "POST", server.URL+"/v1/responses", `{"input":"a"}`)
+	_, speech := roundTrip(t, recorder, "POST", server.URL+"/v1/audio/speech", `{"input":"b"}`)
+
+	if !bytes.Equal(speech, audioData) {
+		t.Errorf("recorded response body = %v, want %v", speech, audioData)
+	}
+
+	fixtures, err := LoadFixtures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != 2 {
+		t.Fatalf("recorded %d fixtures, want 2", len(fixtures))
+	}
+	// SSE はテキストのまま、音声はBase64で保存し、Cookie は残さない
+	if fixtures[0].Body != string(stream) || fixtures[0].BodyBase64 != nil {
+		t.Errorf("stream fixture body = %q", fixtures[0].Body)
+	}
+	if !bytes.Equal(fixtures[1].BodyBase64, audioData) {
+		t.Errorf("speech fixture body = %v", fixtures[1].BodyBase64)
+	}
+	if fixtures[0].Request != `{"input":"a"}` || fixtures[0].Header.Get("Set-Cookie") != "" {
+		t.Errorf("fixture = %+v", fixtures[0])
+	}
+
+	// 再生はネットワークに接続せず、記録したレスポンスを返す
+	server.Close()
+	replayer := NewReplayTransport(dir)
+	resp, replayed := roundTrip(t, replayer, "POST", "https://api.openai.com/v1/responses", `{"input":"a"}`)
+	if string(replayed) != string(stream) || resp.Header.Get("X-Request-Id") != "req_123" {
+		t.Errorf("replayed stream = %q (headers %v)", replayed, resp.Header)
+	}
+	_, replayed = roundTrip(t, replayer, "POST", "https://api.openai.com/v1/audio/speech", `{"input":"b"}`)
+	if !bytes.Equal(replayed, audioData) {
+		t.Errorf("replayed speech = %v", replayed)
+	}
+}
+
+func TestReplayerMatch(t *testing.T) {
+	newFixture := func(path, request, body string) *Fixture {
+		return &Fixture{Method: "POST", Path: path, RequestHash: hashBody([]byte(request)), StatusCode: 200, Body: body}
+	}
+	r := &Replayer{
+		fixtures: []*Fixture{
+			newFixture("/v1/responses", "first", "1"),
+			newFixture("/v1/responses", "second", "2"),
+			newFixture("/v1/audio/speech", "speech", "audio"),
+		},
+		used: map[*Fixture]bool{},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		request string
+		want    string
+	}{
+		{"exact match out of order", "/v1/responses", "second", "2"},
+		{"same request again", "/v1/responses", "second", "2"},
+		{"changed request uses the next unused", "/v1/responses", "changed", "1"},
+		{"all used falls back to the last", "/v1/responses", "changed again", "2"},
+		{"other path", "/v1/audio/speech", "other text", "audio"},
+	}
+	for _, tt := range tests {
+		fixture, err := r.Match("POST", tt.path, []byte(tt.request))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if fixture.Body != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, fixture.Body, tt.want)
+		}
+	}
+
+	if _, err := r.Match("GET", "/v1/models", nil); err == nil {
+		t.Error("Match succeeded for an unrecorded path")
+	}
+}
+
+func TestReplayerServeHTTP(t *testing.T) {
+	r := &Replayer{
+		fixtures: []*Fixture{{
+			Method:     "POST",
+			Path:       "/v1/responses",
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Content-Type": {"application/json"}, "Retry-After": {"1"}},
+			Body:       `{"error":{"type":"rate_limit_exceeded","code":"rate_limit_exceeded","message":"slow down"}}`,
+		}},
+		used: map[*Fixture]bool{},
+	}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/responses", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiErr := NewAPIError(resp)
+	if !apiErr.RateLimited() || resp.Header.Get("Retry-After") != "1" {
+		t.Errorf("stand-in response = %v (headers %v)", apiErr, resp.Header)
+	}
+
+	resp, err = http.Get(server.URL + "/v1/models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status for an unrecorded path = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,144 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"news_reporter/i18n"
+)
+
+// RecordTransport 実際のAPIとのやり取りをフィクスチャとして保存する http.RoundTripper
+// レスポンスの本文は呼び出し側にそのまま流し（SSEも逐次届く）、読み終えるか閉じた時点でファイルに書き出す
+type RecordTransport struct {
+	Base http.RoundTripper // 実際に送信する RoundTripper（nil の場合は http.DefaultTransport）
+	Dir  string            // 保存先のディレクトリ
+
+	mu   sync.Mutex
+	seq  int
+	init bool
+}
+
+// NewRecordTransport dir に記録する RoundTripper を作成
+func NewRecordTransport(base http.RoundTripper, dir string) *RecordTransport {
+	return &RecordTransport{Base: base, Dir: dir}
+}
+
+// RoundTrip リクエストを送信し、レスポンスを記録しながら返す
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{
+		Method:      req.Method,
+		Path:        req.URL.Path,
+		RequestHash: hashBody(requestBody),
+		StatusCode:  resp.StatusCode,
+		Header:      http.Header{},
+	}
+	if isText(req.Header.Get("Content-Type")) {
+		fixture.Request = string(requestBody)
+	}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			fixture.Header.Set(name, value)
+		}
+	}
+
+	path, err := t.nextPath(fixture)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = &recordingBody{body: resp.Body, fixture: fixture, path: path}
+	return resp, nil
+}
+
+// nextPath 次に記録するファイルのパス（既存のフィクスチャの後ろに連番を振る）
+func (t *RecordTransport) nextPath(f *Fixture) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.init {
+		if err := os.MkdirAll(t.Dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create fixture dir: %w", err)
+		}
+		existing, _ := filepath.Glob(filepath.Join(t.Dir, "*.json"))
+		t.seq = len(existing)
+		t.init = true
+	}
+	t.seq++
+	return filepath.Join(t.Dir, fixtureName(t.seq, f)), nil
+}
+
+// readRequestBody 送信するリクエストの本文を読む（本文は読み直せるように戻す）
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// recordingBody 読み取った本文を貯めておき、読み終えるか閉じた時点でフィクスチャを書き出す
+type recordingBody struct {
+	body    io.ReadCloser
+	fixture *Fixture
+	path    string
+	buf     bytes.Buffer
+	once    sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.save()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.save()
+	return b.body.Close()
+}
+
+// save 受信した本文を保存（途中で閉じられた場合は受信済みの分だけを保存する）
+func (b *recordingBody) save() {
+	b.once.Do(func() {
+		b.fixture.setResponseBody(b.buf.Bytes())
+		if err := WriteFixture(b.path, b.fixture); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("transport.fixture_failed", err))
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Replayer 記録したフィクスチャからレスポンスを返す
+// ReplayTransport でAPIクライアントに直接組み込むか、http.Handler としてローカルのスタンドインサーバーに使う
+//
+// リクエストは次の順に照合する
+//  1. 未使用で、メソッド・パス・本文が一致するもの
+//  2. 使用済みでも、メソッド・パス・本文が一致するもの（同じリクエストの繰り返し）
+//  3. 未使用で、メソッド・パスが一致するもの（日付などで本文が変わっても記録順に返す）
+//  4. メソッド・パスが一致する最後のもの
+type Replayer struct {
+	fixtures []*Fixture
+
+	mu   sync.Mutex
+	used map[*Fixture]bool
+}
+
+// NewReplayer dir のフィクスチャを読み込む
+func NewReplayer(dir string) (*Replayer, error) {
+	fixtures, err := LoadFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(fixtures) == 0 {
+		return nil, fmt.Errorf("no fixtures in %s", dir)
+	}
+	return &Replayer{fixtures: fixtures, used: make(map[*Fixture]bool)}, nil
+}
+
+// Match リクエストに対応するフィクスチャを選ぶ
+func (r *Replayer) Match(method, path string, body []byte) (*Fixture, error) {
+	hash := hashBody(body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sameBody, unused, last *Fixture
+	for _, f := range r.fixtures {
+		if f.Method != method || f.Path != path {
+			continue
+		}
+		if f.RequestHash == hash {
+			if !r.used[f] {
+				r.used[f] = true
+				return f, nil
+			}
+			if sameBody == nil {
+				sameBody = f
+			}
+		}
+		if unused == nil && !r.used[f] {
+			unused = f
+		}
+		last = f
+	}
+
+	switch {
+	case sameBody != nil:
+		return sameBody, nil
+	case unused != nil:
+		r.used[unused] = true
+		return unused, nil
+	case last != nil:
+		return last, nil
+	default:
+		return nil, fmt.Errorf("no fixture for %s %s", method, path)
+	}
+}
+
+// ServeHTTP 記録したレスポンスを返す（ローカルのスタンドインサーバー用）
+func (r *Replayer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fixture, err := r.Match(req.Method, req.URL.Path, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	for name, values := range fixture.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	responseBody := fixture.ResponseBody()
+	w.Header().Set("Content-Length", strconv.Itoa(len(responseBody)))
+	w.WriteHeader(fixture.StatusCode)
+	w.Write(responseBody)
+}
+
+// ReplayTransport ネットワークに接続せず、記録したフィクスチャからレスポンスを返す http.RoundTripper
+// フィクスチャは最初のリクエストで読み込む
+type ReplayTransport struct {
+	Dir string
+
+	once     sync.Once
+	replayer *Replayer
+	err      error
+}
+
+// NewReplayTransport dir のフィクスチャを再生する RoundTripper を作成
+func NewReplayTransport(dir string) *ReplayTransport {
+	return &ReplayTransport{Dir: dir}
+}
+
+// RoundTrip リクエストに対応する記録済みのレスポンスを返す
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.once.Do(func() {
+		t.replayer, t.err = NewReplayer(t.Dir)
+	})
+	if t.err != nil {
+		return nil, fmt.Errorf("replay: %w", t.err)
+	}
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	fixture, err := t.replayer.Match(req.Method, req.URL.Path, body)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+
+	header := fixture.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	responseBody := fixture.ResponseBody()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.StatusCode, http.StatusText(fixture.StatusCode)),
+		StatusCode:    fixture.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(responseBody)),
+		ContentLength: int64(len(responseBody)),
+		Request:       req,
+	}, nil
+}
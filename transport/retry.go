@@ -0,0 +1,348 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"news_reporter/config"
+	"news_reporter/i18n"
+)
+
+// リトライの既定値
+const (
+	DefaultBaseDelay = 500 * time.Millisecond // 1回目のリトライまでの待ち時間の目安
+	DefaultMaxDelay  = 20 * time.Second       // 指数的に伸ばす待ち時間の上限
+)
+
+// maxErrorBody エラーとして読み取るレスポンス本文の上限
+const maxErrorBody = 64 << 10
+
+// RetryTransport 一時的な失敗（429・5xx・接続エラー）をジッター付きの指数バックオフで再送する http.RoundTripper
+// Retry-After と x-ratelimit-* ヘッダーを見て、サーバーが指定した時刻より前には再送しない
+type RetryTransport struct {
+	Base       http.RoundTripper // 実際に送信する RoundTripper（nil の場合は http.DefaultTransport）
+	MaxRetries int               // 最初の送信に加えて再送する最大回数
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	MaxWait    time.Duration // サーバーが指定した待ち時間の上限（これを超える場合は再送せずに諦める）
+
+	// OnRetry 再送する前に呼ばれる（nil の場合は何もしない）
+	OnRetry func(attempt int, wait time.Duration, reason string)
+
+	mu        sync.Mutex
+	notBefore time.Time // レート制限が解除されるまで次のリクエストを待たせる時刻
+}
+
+// NewRetryTransport 設定に合わせたリトライ付きの RoundTripper を作成
+// フィクスチャの記録・再生が設定されている場合は、その下で実際の送信を記録・再生する
+func NewRetryTransport(cfg *config.Config) *RetryTransport {
+	return &RetryTransport{
+		Base:       baseTransport(cfg),
+		MaxRetries: cfg.MaxRetries,
+		BaseDelay:  DefaultBaseDelay,
+		MaxDelay:   DefaultMaxDelay,
+		MaxWait:    cfg.RetryMaxWait,
+		OnRetry:    logRetry,
+	}
+}
+
+// NewHTTPClient リトライ付きの http.Client を作成（timeout は再送を含めた全体の制限時間）
+func NewHTTPClient(cfg *config.Config, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: NewRetryTransport(cfg),
+	}
+}
+
+// baseTransport フィクスチャの設定に合わせて、実際に送信する RoundTripper を選ぶ
+func baseTransport(cfg *config.Config) http.RoundTripper {
+	switch cfg.FixtureMode {
+	case config.FixtureRecord:
+		return NewRecordTransport(http.DefaultTransport, cfg.FixtureDir)
+	case config.FixtureReplay:
+		return NewReplayTransport(cfg.FixtureDir)
+	default:
+		return http.DefaultTransport
+	}
+}
+
+// RetryError 再送しても成功しなかった、または再送できない待ち時間を指定された
+// Err は最後のレスポンスの *APIError か、接続エラー（StatusCode が 0）
+type RetryError struct {
+	Attempts   int           // 送信した回数
+	StatusCode int           // 最後のレスポンスのステータスコード
+	RetryAfter time.Duration // サーバーが指定した待ち時間（なければ 0）
+	Err        error
+}
+
+func (e *RetryError) Error() string {
+	msg := fmt.Sprintf("request failed after %d attempts", e.Attempts)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RoundTrip リクエストを送信し、再送できる失敗であれば待ってから送り直す
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// 本文を再送できないリクエストはそのまま送る
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := t.waitRateLimit(req); err != nil {
+			return nil, err
+		}
+
+		attemptReq, err := rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := base.RoundTrip(attemptReq)
+		if err == nil {
+			t.observe(resp.Header)
+		}
+
+		reason, retryable, serverWait := t.classify(req, resp, err)
+		if !retryable {
+			return resp, err
+		}
+
+		// 諦める場合はAPIのエラー内容を残す
+		giveUp := func(retryAfter time.Duration) (*http.Response, error) {
+			retryErr := &RetryError{Attempts: attempt + 1, RetryAfter: retryAfter, Err: err}
+			if resp != nil {
+				retryErr.StatusCode = resp.StatusCode
+				retryErr.Err = NewAPIError(resp)
+			}
+			return nil, retryErr
+		}
+
+		if attempt >= t.MaxRetries {
+			return giveUp(serverWait)
+		}
+		if t.MaxWait > 0 && serverWait > t.MaxWait {
+			return giveUp(serverWait)
+		}
+
+		wait := t.backoff(attempt)
+		if serverWait > wait {
+			wait = serverWait
+		}
+		// 制限時間内に再送できない場合は待たずに諦める
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < wait {
+			return giveUp(serverWait)
+		}
+
+		if resp != nil {
+			drain(resp)
+		}
+		if serverWait > 0 {
+			t.delayUntil(time.Now().Add(serverWait))
+		}
+		if t.OnRetry != nil {
+			t.OnRetry(attempt+1, wait, reason)
+		}
+		if err := sleep(req, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// classify 再送できる失敗か判定し、理由とサーバーが指定した待ち時間を返す
+func (t *RetryTransport) classify(req *http.Request, resp *http.Response, err error) (string, bool, time.Duration) {
+	if err != nil {
+		// キャンセルやタイムアウトは再送しない
+		if req.Context().Err() != nil {
+			return "", false, 0
+		}
+		// 送信済みかもしれないリクエストは、冪等なメソッドか接続前の失敗の場合だけ再送する
+		if !idempotent(req) && !notSent(err) {
+			return "", false, 0
+		}
+		return err.Error(), true, 0
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// クォータ不足は待っても解消しないため再送しない
+		if insufficientQuota(resp) {
+			return "", false, 0
+		}
+		return resp.Status, true, retryDelay(resp.Header)
+	case http.StatusRequestTimeout, http.StatusConflict,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return resp.Status, true, retryDelay(resp.Header)
+	default:
+		return "", false, 0
+	}
+}
+
+// backoff attempt 回目の再送までの待ち時間（上限つきの指数バックオフに ±50% のジッター）
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	delay := t.BaseDelay
+	if delay <= 0 {
+		delay = DefaultBaseDelay
+	}
+	maxDelay := t.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxDelay
+	}
+	for i := 0; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)))
+}
+
+// observe 成功したレスポンスのレート制限ヘッダーから、残りがなくなった場合に次の送信を遅らせる
+func (t *RetryTransport) observe(header http.Header) {
+	if wait := rateLimitReset(header); wait > 0 {
+		t.delayUntil(time.Now().Add(wait))
+	}
+}
+
+// delayUntil 次のリクエストを送る時刻を遅らせる（並列の合成リクエストにも効かせる）
+func (t *RetryTransport) delayUntil(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.After(t.notBefore) {
+		t.notBefore = at
+	}
+}
+
+// waitRateLimit レート制限が解除されるまで待つ
+func (t *RetryTransport) waitRateLimit(req *http.Request) error {
+	t.mu.Lock()
+	wait := time.Until(t.notBefore)
+	t.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	if t.MaxWait > 0 && wait > t.MaxWait {
+		wait = t.MaxWait
+	}
+	return sleep(req, wait)
+}
+
+// retryDelay Retry-After 系のヘッダーとレート制限ヘッダーから、サーバーが指定した待ち時間を求める
+func retryDelay(header http.Header) time.Duration {
+	// OpenAI はミリ秒単位の retry-after-ms を返すことがある
+	if ms, err := strconv.ParseFloat(header.Get("Retry-After-Ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			if wait := time.Until(at); wait > 0 {
+				return wait
+			}
+		}
+	}
+	return rateLimitReset(header)
+}
+
+// rateLimitReset 使い切ったレート制限（リクエスト数・トークン数）が解除されるまでの時間
+// x-ratelimit-reset-* は "1s" や "6m0s" のような形式
+func rateLimitReset(header http.Header) time.Duration {
+	var wait time.Duration
+	for _, kind := range []string{"requests", "tokens"} {
+		if header.Get("X-Ratelimit-Remaining-"+kind) != "0" {
+			continue
+		}
+		reset, err := time.ParseDuration(header.Get("X-Ratelimit-Reset-" + kind))
+		if err == nil && reset > wait {
+			wait = reset
+		}
+	}
+	return wait
+}
+
+// insufficientQuota 429 が利用上限（クォータ不足）によるものか判定（本文は読み直せるように戻す）
+func insufficientQuota(resp *http.Response) bool {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return err == nil && bytes.Contains(body, []byte("insufficient_quota"))
+}
+
+// rewind 再送用に本文を読み直したリクエストを作成（最初の送信は元のリクエストのまま）
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// drain 再送する前にレスポンスの本文を読み捨てて閉じる（接続を再利用するため）
+func drain(resp *http.Response) {
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+}
+
+// sleep リクエストのキャンセルを見ながら待つ
+func sleep(req *http.Request, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// idempotent 送り直しても副作用が重ならないメソッドか判定
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// notSent サーバーに届く前（名前解決・接続）に失敗したか判定
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// logRetry 再送することを標準エラー出力に表示
+func logRetry(attempt int, wait time.Duration, reason string) {
+	fmt.Fprintln(os.Stderr, i18n.T("transport.retrying", reason, wait.Round(10*time.Millisecond), attempt))
+}
@@ -0,0 +1,268 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// retryServer 呼び出しごとに handlers を順に使う（最後のものは繰り返し使う）テストサーバー
+func retryServer(t *testing.T, handlers ...http.HandlerFunc) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1)) - 1
+		if n >= len(handlers) {
+			n = len(handlers) - 1
+		}
+		handlers[n](w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// respond ステータスコードとヘッダー・本文を返すハンドラー
+func respond(status int, body string, header ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i+1 < len(header); i += 2 {
+			w.Header().Set(header[i], header[i+1])
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+}
+
+const (
+	rateLimitBody = `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`
+	quotaBody     = `{"error":{"message":"You exceeded your current quota","type":"insufficient_quota","code":"insufficient_quota"}}`
+)
+
+func TestRetryTransportBackoff(t *testing.T) {
+	rt := &RetryTransport{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 50 * time.Millisecond, 150 * time.Millisecond},
+		{1, 100 * time.Millisecond, 300 * time.Millisecond},
+		{2, 200 * time.Millisecond, 600 * time.Millisecond},
+		{10, 500 * time.Millisecond, 1500 * time.Millisecond}, // 上限で止まる
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if wait := rt.backoff(tt.attempt); wait < tt.min || wait >= tt.max {
+				t.Fatalf("backoff(%d) = %s, want [%s, %s)", tt.attempt, wait, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	future := time.Now().Add(3 * time.Second).UTC().Format(http.TimeFormat)
+	tests := []struct {
+		name     string
+		header   []string
+		min, max time.Duration
+	}{
+		{"none", nil, 0, 0},
+		{"seconds", []string{"Retry-After", "2"}, 2 * time.Second, 2 * time.Second},
+		{"milliseconds", []string{"Retry-After-Ms", "250"}, 250 * time.Millisecond, 250 * time.Millisecond},
+		{"milliseconds take precedence", []string{"Retry-After-Ms", "250", "Retry-After", "2"}, 250 * time.Millisecond, 250 * time.Millisecond},
+		{"http date", []string{"Retry-After", future}, time.Second, 3 * time.Second},
+		{"past http date", []string{"Retry-After", "Mon, 01 Jan 2001 00:00:00 GMT"}, 0, 0},
+		{"invalid", []string{"Retry-After", "soon"}, 0, 0},
+		{"exhausted rate limit", []string{"X-Ratelimit-Remaining-Requests", "0", "X-Ratelimit-Reset-Requests", "1.5s"}, 1500 * time.Millisecond, 1500 * time.Millisecond},
+		{"longest exhausted limit", []string{"X-Ratelimit-Remaining-Requests", "0", "X-Ratelimit-Reset-Requests", "1s", "X-Ratelimit-Remaining-Tokens", "0", "X-Ratelimit-Reset-Tokens", "6m0s"}, 6 * time.Minute, 6 * time.Minute},
+		{"remaining rate limit", []string{"X-Ratelimit-Remaining-Requests", "5", "X-Ratelimit-Reset-Requests", "1s"}, 0, 0},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		for i := 0; i+1 < len(tt.header); i += 2 {
+			header.Set(tt.header[i], tt.header[i+1])
+		}
+		if got := retryDelay(header); got < tt.min || got > tt.max {
+			t.Errorf("%s: retryDelay = %s, want [%s, %s]", tt.name, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestRetryTransportRetriesServerErrors(t *testing.T) {
+	var bodies []string
+	record := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			next(w, r)
+		}
+	}
+	server, calls := retryServer(t,
+		record(respond(http.StatusServiceUnavailable, "busy")),
+		record(respond(http.StatusBadGateway, "busy")),
+		record(respond(http.StatusOK, "ok")),
+	)
+
+	var retries []int
+	rt := &RetryTransport{
+		MaxRetries: 3,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   5 * time.Millisecond,
+		OnRetry:    func(attempt int, wait time.Duration, reason string) { retries = append(retries, attempt) },
+	}
+	resp, data := roundTrip(t, rt, "POST", server.URL, `{"input":"a"}`)
+
+	if resp.StatusCode != http.StatusOK || string(data) != "ok" {
+		t.Errorf("response = %d %q, want 200 ok", resp.StatusCode, data)
+	}
+	if *calls != 3 || len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+		t.Errorf("calls = %d, retries = %v; want 3 calls and retries [1 2]", *calls, retries)
+	}
+	// 再送でも同じ本文を送る
+	for i, body := range bodies {
+		if body != `{"input":"a"}` {
+			t.Errorf("request %d body = %q", i+1, body)
+		}
+	}
+}
+
+func TestRetryTransportHonorsRetryAfter(t *testing.T) {
+	server, calls := retryServer(t,
+		respond(http.StatusTooManyRequests, rateLimitBody, "Retry-After-Ms", "150"),
+		respond(http.StatusOK, "ok"),
+	)
+
+	var waited time.Duration
+	rt := &RetryTransport{
+		MaxRetries: 2,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   5 * time.Millisecond,
+		OnRetry:    func(attempt int, wait time.Duration, reason string) { waited = wait },
+	}
+	start := time.Now()
+	resp, _ := roundTrip(t, rt, "GET", server.URL, "")
+	elapsed := time.Since(start)
+
+	if resp.StatusCode != http.StatusOK || *calls != 2 {
+		t.Fatalf("status = %d, calls = %d; want 200 after 2 calls", resp.StatusCode, *calls)
+	}
+	if waited != 150*time.Millisecond || elapsed < 150*time.Millisecond {
+		t.Errorf("waited %s (elapsed %s), want the server's 150ms", waited, elapsed)
+	}
+}
+
+func TestRetryTransportGivesUpOnLongRetryAfter(t *testing.T) {
+	server, calls := retryServer(t, respond(http.StatusTooManyRequests, rateLimitBody, "Retry-After", "120"))
+
+	rt := &RetryTransport{MaxRetries: 3, BaseDelay: time.Millisecond, MaxWait: time.Second}
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	start := time.Now()
+	_, err := rt.RoundTrip(req)
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("err = %v, want *RetryError", err)
+	}
+	if retryErr.Attempts != 1 || retryErr.StatusCode != http.StatusTooManyRequests || retryErr.RetryAfter != 2*time.Minute {
+		t.Errorf("RetryError = %+v", retryErr)
+	}
+	if *calls != 1 || time.Since(start) > time.Second {
+		t.Errorf("calls = %d after %s, want 1 call without waiting", *calls, time.Since(start))
+	}
+}
+
+func TestRetryTransportClassifiesTooManyRequests(t *testing.T) {
+	t.Run("quota", func(t *testing.T) {
+		server, calls := retryServer(t, respond(http.StatusTooManyRequests, quotaBody))
+		rt := &RetryTransport{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+		// クォータ不足は再送せず、本文を読めるままレスポンスを返す
+		resp, data := roundTrip(t, rt, "GET", server.URL, "")
+		if *calls != 1 || resp.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("calls = %d, status = %d; want one 429", *calls, resp.StatusCode)
+		}
+		apiErr := ParseAPIError(resp.StatusCode, resp.Header, data)
+		if !apiErr.QuotaExceeded() {
+			t.Errorf("APIError %+v is not a quota error", apiErr)
+		}
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		server, calls := retryServer(t, respond(http.StatusTooManyRequests, rateLimitBody))
+		rt := &RetryTransport{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		_, err := rt.RoundTrip(req)
+		if *calls != 3 {
+			t.Errorf("calls = %d, want 3 (1 + MaxRetries)", *calls)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.RateLimited() || apiErr.QuotaExceeded() {
+			t.Errorf("err = %v, want a rate limit APIError", err)
+		}
+	})
+}
+
+func TestRetryTransportStopsOnCancel(t *testing.T) {
+	t.Run("cancel while waiting", func(t *testing.T) {
+		server, calls := retryServer(t, respond(http.StatusServiceUnavailable, "busy", "Retry-After", "30"))
+		rt := &RetryTransport{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+		start := time.Now()
+		_, err := rt.RoundTrip(req)
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+		if *calls != 1 || time.Since(start) > 5*time.Second {
+			t.Errorf("calls = %d after %s, want 1 call and a prompt return", *calls, time.Since(start))
+		}
+	})
+
+	t.Run("deadline before retry", func(t *testing.T) {
+		server, calls := retryServer(t, respond(http.StatusServiceUnavailable, "busy", "Retry-After", "30"))
+		rt := &RetryTransport{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+		// 制限時間内に再送できない場合は待たずに諦める
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+		start := time.Now()
+		_, err := rt.RoundTrip(req)
+
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) || retryErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("err = %v, want *RetryError for 503", err)
+		}
+		if *calls != 1 || time.Since(start) > time.Second {
+			t.Errorf("calls = %d after %s, want 1 call without waiting", *calls, time.Since(start))
+		}
+	})
+
+	t.Run("cancelled request is not retried", func(t *testing.T) {
+		blocked := make(chan struct{})
+		server, calls := retryServer(t, func(w http.ResponseWriter, r *http.Request) {
+			close(blocked)
+			<-r.Context().Done()
+		})
+		rt := &RetryTransport{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-blocked
+			cancel()
+		}()
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+		_, err := rt.RoundTrip(req)
+
+		if !errors.Is(err, context.Canceled) || *calls != 1 {
+			t.Errorf("err = %v, calls = %d; want context.Canceled after 1 call", err, *calls)
+		}
+	})
+}
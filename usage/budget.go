@@ -0,0 +1,254 @@
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"news_reporter/config"
+	"news_reporter/i18n"
+)
+
+// BudgetLevel 使用額の上限に対する状況
+type BudgetLevel int
+
+const (
+	BudgetOK          BudgetLevel = iota // 上限まで余裕がある
+	BudgetApproaching                    // 上限の WarnAt 倍を超えた（安価なモデルに切り替え、音声合成を省略する）
+	BudgetExceeded                       // 上限に達した（検索しない）
+)
+
+// BudgetStatus 1日・1か月の使用額と上限
+// 全体の上限はすべてのプロファイルの使用額の合計と、プロファイルの上限はそのプロファイルの使用額と比べる
+type BudgetStatus struct {
+	Level               BudgetLevel
+	DailySpent          float64
+	DailyLimit          float64 // 0 の場合は上限なし
+	MonthlySpent        float64
+	MonthlyLimit        float64 // 0 の場合は上限なし
+	ProfileDailySpent   float64
+	ProfileDailyLimit   float64 // 0 の場合は上限なし
+	ProfileMonthlySpent float64
+	ProfileMonthlyLimit float64 // 0 の場合は上限なし
+}
+
+// period 最も上限に近い期間のメッセージID・使用額・上限を返す
+func (s BudgetStatus) period() (string, float64, float64) {
+	periods := []struct {
+		id           string
+		spent, limit float64
+	}{
+		{"budget.daily", s.DailySpent, s.DailyLimit},
+		{"budget.monthly", s.MonthlySpent, s.MonthlyLimit},
+		{"budget.profile_daily", s.ProfileDailySpent, s.ProfileDailyLimit},
+		{"budget.profile_monthly", s.ProfileMonthlySpent, s.ProfileMonthlyLimit},
+	}
+	nearest := periods[0]
+	for _, p := range periods[1:] {
+		if ratio(p.spent, p.limit) > ratio(nearest.spent, nearest.limit) {
+			nearest = p
+		}
+	}
+	return nearest.id, nearest.spent, nearest.limit
+}
+
+// Describe 最も上限に近い期間の使用額を表示用に整形
+func (s BudgetStatus) Describe() string {
+	id, spent, limit := s.period()
+	return i18n.T(id, spent, limit)
+}
+
+// Ratio 最も上限に近い期間の上限に対する使用額の割合
+func (s BudgetStatus) Ratio() float64 {
+	_, spent, limit := s.period()
+	return ratio(spent, limit)
+}
+
+// ratio 上限に対する使用額の割合（上限なしの場合は 0）
+func ratio(spent, limit float64) float64 {
+	if limit <= 0 {
+		return 0
+	}
+	return spent / limit
+}
+
+// BudgetError 使用額が上限に達したため検索・音声合成を行わなかったエラー
+type BudgetError struct {
+	Status  BudgetStatus
+	Profile string
+}
+
+func (e *BudgetError) Error() string {
+	profile := e.Profile
+	if profile == "" {
+		profile = "(default)"
+	}
+	return i18n.T("budget.exceeded", profile, e.Status.Describe())
+}
+
+// ErrSpeechSkipped 使用額が上限に近づいたため音声合成を省略した（errors.Is で判定する）
+var ErrSpeechSkipped = errors.New("speech synthesis skipped because the spending cap is near")
+
+// SpeechSkippedError 音声合成を省略したときの使用額の状況
+type SpeechSkippedError struct {
+	Status BudgetStatus
+}
+
+func (e *SpeechSkippedError) Error() string {
+	return i18n.T("budget.speech_skipped", e.Status.Describe())
+}
+
+// Is errors.Is(err, ErrSpeechSkipped) で判定できるようにする
+func (e *SpeechSkippedError) Is(target error) bool {
+	return target == ErrSpeechSkipped
+}
+
+// Budget 記録された使用額を設定の上限と比べる
+// nil の場合は上限なしとして扱う
+type Budget struct {
+	ledger  *Ledger
+	limits  config.Budget
+	profile string
+	// paidSpeech 音声合成に費用がかかるか（ローカルの音声合成は上限に近づいても省略しない）
+	paidSpeech bool
+	now        func() time.Time
+
+	mu     sync.Mutex
+	totals spendTotals // 集計済みの今月の使用額（Check のたびに追記された記録だけを読み足す）
+}
+
+// spendTotals 記録ファイルの offset までを集計した、ある月の日ごとの使用額
+type spendTotals struct {
+	month   time.Time          // 集計している月の初め
+	offset  int64              // 次に読み始める記録ファイルの位置
+	all     map[string]float64 // 日付（2006-01-02）ごとのすべての記録の合計
+	profile map[string]float64 // 日付ごとの同じプロファイルの記録の合計
+}
+
+// NewBudget 新しい予算の確認係を作成（上限が設定されていなければ nil）
+// ledger は使用量を記録する Recorder と同じものを渡す
+func NewBudget(cfg *config.Config, ledger *Ledger) *Budget {
+	if !cfg.Budget.Enabled() {
+		return nil
+	}
+	return &Budget{
+		ledger:     ledger,
+		limits:     cfg.Budget,
+		profile:    cfg.Profile,
+		paidSpeech: cfg.TTSBackend == config.TTSBackendOpenAI,
+		now:        time.Now,
+	}
+}
+
+// FallbackModel 上限に近づいたときに使うモデル（未設定なら空）
+func (b *Budget) FallbackModel() string {
+	if b == nil {
+		return ""
+	}
+	return b.limits.FallbackModel
+}
+
+// Check 今日・今月の使用額を集計して上限に対する状況を返す
+// 全体の上限にはすべての記録を、プロファイルの上限には同じプロファイルの記録だけを合計する
+func (b *Budget) Check() (BudgetStatus, error) {
+	if b == nil {
+		return BudgetStatus{}, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if err := b.update(now); err != nil {
+		return BudgetStatus{}, err
+	}
+
+	today := now.Format("2006-01-02")
+	status := BudgetStatus{
+		DailySpent:          b.totals.all[today],
+		DailyLimit:          b.limits.Daily,
+		MonthlyLimit:        b.limits.Monthly,
+		ProfileDailySpent:   b.totals.profile[today],
+		ProfileDailyLimit:   b.limits.ProfileDaily,
+		ProfileMonthlyLimit: b.limits.ProfileMonthly,
+	}
+	for _, cost := range b.totals.all {
+		status.MonthlySpent += cost
+	}
+	for _, cost := range b.totals.profile {
+		status.ProfileMonthlySpent += cost
+	}
+
+	_, spent, limit := status.period()
+	switch {
+	case limit > 0 && spent >= limit:
+		status.Level = BudgetExceeded
+	case limit > 0 && spent >= limit*b.limits.WarnAt:
+		status.Level = BudgetApproaching
+	}
+	return status, nil
+}
+
+// update 前回の集計以降に追記された記録を今月の使用額に加える
+// 月が変わった場合と記録ファイルが作り直された場合は先頭から集計し直す
+func (b *Budget) update(now time.Time) error {
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if !b.totals.month.Equal(month) {
+		b.totals = newSpendTotals(month)
+	}
+
+	entries, offset, err := b.ledger.ReadFrom(b.totals.offset)
+	if errors.Is(err, ErrLedgerRewritten) {
+		b.totals = newSpendTotals(month)
+		entries, offset, err = b.ledger.ReadFrom(0)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if e.Time.Before(month) {
+			continue
+		}
+		day := e.Time.In(now.Location()).Format("2006-01-02")
+		b.totals.all[day] += e.Cost
+		if e.Profile == b.profile {
+			b.totals.profile[day] += e.Cost
+		}
+	}
+	b.totals.offset = offset
+	return nil
+}
+
+// newSpendTotals month の集計を最初から始める
+func newSpendTotals(month time.Time) spendTotals {
+	return spendTotals{
+		month:   month,
+		all:     make(map[string]float64),
+		profile: make(map[string]float64),
+	}
+}
+
+// Err 上限に達していれば BudgetError を返す
+func (b *Budget) Err(status BudgetStatus) error {
+	if status.Level < BudgetExceeded {
+		return nil
+	}
+	return &BudgetError{Status: status, Profile: b.profile}
+}
+
+// CheckSpeech 音声合成してよいか確認する
+// 使用額が上限に近づいていれば SpeechSkippedError（ErrSpeechSkipped）を返す
+func (b *Budget) CheckSpeech() error {
+	if b == nil || !b.paidSpeech {
+		return nil
+	}
+	status, err := b.Check()
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("budget.check_failed"), err)
+	}
+	if status.Level >= BudgetApproaching {
+		return &SpeechSkippedError{Status: status}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package usage
+
+import (
+	"news_reporter/config"
+	"news_reporter/models"
+)
+
+// perMillion 料金表の単価（100万トークン・100万文字あたり）を1単位あたりに換算する
+const perMillion = 1_000_000
+
+// Estimate トークン数とWeb検索の回数から費用を見積もる（料金表にないモデルや使用量が不明な場合は nil）
+func Estimate(pricing config.Pricing, model string, u *models.Usage) *models.Cost {
+	if u == nil {
+		return nil
+	}
+	price, ok := pricing.ModelPrice(model)
+	if !ok {
+		return nil
+	}
+	cost := &models.Cost{
+		Input:     float64(u.InputTokens) * price.Input / perMillion,
+		Output:    float64(u.OutputTokens) * price.Output / perMillion,
+		WebSearch: float64(u.WebSearchCalls) * pricing.WebSearchPerCall,
+	}
+	cost.Total = cost.Input + cost.Output + cost.WebSearch
+	return cost
+}
+
+// SpeechCost 音声合成の文字数から費用を見積もる（料金表にないモデルの場合は false）
+func SpeechCost(pricing config.Pricing, model string, characters int) (float64, bool) {
+	price, ok := pricing.SpeechPrice(model)
+	if !ok {
+		return 0, false
+	}
+	return float64(characters) * price / perMillion, true
+}
@@ -0,0 +1,142 @@
+// Package usage 検索・音声合成の使用量と見積もった費用を記録し、日・月・トピックごとに集計する
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 記録の種類
+const (
+	KindSearch   = "search"   // Web検索と要約
+	KindGenerate = "generate" // ブリーフィング原稿などの生成（検索なし）
+	KindSpeech   = "speech"   // 音声合成
+)
+
+// Entry 1回のAPI呼び出しの使用量と費用
+type Entry struct {
+	Time           time.Time `json:"time"`
+	Kind           string    `json:"kind"`
+	Topic          string    `json:"topic,omitempty"`   // 検索クエリ、ブリーフィングの番組名など
+	Profile        string    `json:"profile,omitempty"` // 適用していたプロファイル
+	Model          string    `json:"model,omitempty"`
+	InputTokens    int       `json:"input_tokens,omitempty"`
+	OutputTokens   int       `json:"output_tokens,omitempty"`
+	WebSearchCalls int       `json:"web_search_calls,omitempty"`
+	Characters     int       `json:"characters,omitempty"` // 音声合成した文字数
+	Cost           float64   `json:"cost_usd"`
+	Unpriced       bool      `json:"unpriced,omitempty"` // 料金表にないモデルのため費用に含めていない
+}
+
+// Ledger 追記専用のJSONLファイルに使用量を記録する
+type Ledger struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLedger 新しい使用量の記録を作成
+func NewLedger(path string) *Ledger {
+	return &Ledger{path: path}
+}
+
+// Path 記録ファイルのパスを返す
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// Append 使用量を1件追記
+func (l *Ledger) Append(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create usage directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open usage file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(line); err != nil {
+		return fmt.Errorf("failed to write usage entry: %w", err)
+	}
+	return nil
+}
+
+// ErrLedgerRewritten 記録ファイルが前回読んだ位置より短くなっている（削除・作り直された）
+var ErrLedgerRewritten = errors.New("usage file was rewritten")
+
+// List 記録を古い順に返す（ファイルがなければ空）
+func (l *Ledger) List() ([]Entry, error) {
+	entries, _, err := l.ReadFrom(0)
+	return entries, err
+}
+
+// ReadFrom ファイルの offset バイト目以降の記録を古い順に返し、次に読み始める位置を返す
+// 追記途中の最後の行は読まずに残す。ファイルが offset より短い場合は ErrLedgerRewritten を返す
+func (l *Ledger) ReadFrom(offset int64) ([]Entry, int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		if offset > 0 {
+			return nil, 0, ErrLedgerRewritten
+		}
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, offset, fmt.Errorf("failed to open usage file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, offset, fmt.Errorf("failed to read usage file: %w", err)
+	}
+	if info.Size() < offset {
+		return nil, 0, ErrLedgerRewritten
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, fmt.Errorf("failed to read usage file: %w", err)
+	}
+
+	var entries []Entry
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break // 改行で終わっていない行は追記の途中
+		}
+		if err != nil {
+			return nil, offset, fmt.Errorf("failed to read usage file: %w", err)
+		}
+		start := offset
+		offset += int64(len(line))
+		text := strings.TrimSpace(string(line))
+		if text == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return nil, start, fmt.Errorf("invalid usage entry at %s (byte %d): %w", l.path, start, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, offset, nil
+}
@@ -0,0 +1,78 @@
+package usage
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"news_reporter/config"
+	"news_reporter/models"
+)
+
+// Recorder 設定の料金表・プロファイルを使って使用量を Ledger に記録する
+type Recorder struct {
+	ledger     *Ledger
+	pricing    config.Pricing
+	profile    string
+	ttsBackend string
+	ttsModel   string
+}
+
+// NewRecorder 新しい使用量の記録係を作成
+func NewRecorder(cfg *config.Config, ledger *Ledger) *Recorder {
+	return &Recorder{
+		ledger:     ledger,
+		pricing:    cfg.Pricing,
+		profile:    cfg.Profile,
+		ttsBackend: cfg.TTSBackend,
+		ttsModel:   cfg.TTSModel,
+	}
+}
+
+// Ledger 記録先を返す
+func (r *Recorder) Ledger() *Ledger {
+	return r.ledger
+}
+
+// Record 検索・生成の使用量を記録
+// 使用量が返されなかった結果と、キャッシュから取り出した（APIを呼んでいない）結果は記録しない
+func (r *Recorder) Record(kind, topic string, result *models.SearchResult) error {
+	if result == nil || result.Usage == nil || result.Cached {
+		return nil
+	}
+	entry := Entry{
+		Time:           time.Now(),
+		Kind:           kind,
+		Topic:          topic,
+		Profile:        r.profile,
+		Model:          result.Model,
+		InputTokens:    result.Usage.InputTokens,
+		OutputTokens:   result.Usage.OutputTokens,
+		WebSearchCalls: result.Usage.WebSearchCalls,
+	}
+	if result.Cost != nil {
+		entry.Cost = result.Cost.Total
+	} else {
+		entry.Unpriced = true
+	}
+	return r.ledger.Append(entry)
+}
+
+// RecordSpeech 音声合成した文字数と費用を記録
+// ローカルの音声合成（command・voicevox）は費用がかからないため記録しない
+func (r *Recorder) RecordSpeech(topic, text string) error {
+	if r.ttsBackend != config.TTSBackendOpenAI {
+		return nil
+	}
+	entry := Entry{
+		Time:       time.Now(),
+		Kind:       KindSpeech,
+		Topic:      topic,
+		Profile:    r.profile,
+		Model:      r.ttsModel,
+		Characters: utf8.RuneCountInString(text),
+	}
+	cost, ok := SpeechCost(r.pricing, r.ttsModel, entry.Characters)
+	entry.Cost = cost
+	entry.Unpriced = !ok
+	return r.ledger.Append(entry)
+}
@@ -0,0 +1,130 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 集計の単位
+const (
+	GroupDay     = "day"
+	GroupMonth   = "month"
+	GroupTopic   = "topic"
+	GroupProfile = "profile"
+)
+
+// Groups 対応している集計の単位
+var Groups = []string{GroupDay, GroupMonth, GroupTopic, GroupProfile}
+
+// Row 集計の1行
+type Row struct {
+	Key            string  `json:"key"`
+	Searches       int     `json:"searches"`
+	Speeches       int     `json:"speeches"`
+	InputTokens    int     `json:"input_tokens"`
+	OutputTokens   int     `json:"output_tokens"`
+	WebSearchCalls int     `json:"web_search_calls"`
+	Characters     int     `json:"characters"`
+	Cost           float64 `json:"cost_usd"`
+	Unpriced       int     `json:"unpriced,omitempty"` // 費用に含められなかった記録の数
+}
+
+// add 1件の記録を集計に加える
+func (r *Row) add(e Entry) {
+	switch e.Kind {
+	case KindSearch:
+		r.Searches++
+	case KindSpeech:
+		r.Speeches++
+	}
+	r.InputTokens += e.InputTokens
+	r.OutputTokens += e.OutputTokens
+	r.WebSearchCalls += e.WebSearchCalls
+	r.Characters += e.Characters
+	r.Cost += e.Cost
+	if e.Unpriced {
+		r.Unpriced++
+	}
+}
+
+// Filter 集計の対象にする記録の条件（ゼロ値の項目は絞り込まない）
+type Filter struct {
+	Since   time.Time // この時刻以降
+	Until   time.Time // この時刻より前
+	Profile string
+}
+
+// match 記録が条件に合うか判定
+func (f Filter) match(e Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	return f.Profile == "" || e.Profile == f.Profile
+}
+
+// Summarize 記録を group ごとに集計し、合計と一緒に返す
+// 日・月は古い順、トピック・プロファイルは費用の多い順に並べる
+func Summarize(entries []Entry, group string, filter Filter, loc *time.Location) ([]Row, Row, error) {
+	key, err := groupKey(group, loc)
+	if err != nil {
+		return nil, Row{}, err
+	}
+
+	total := Row{Key: "total"}
+	rows := make(map[string]*Row)
+	for _, e := range entries {
+		if !filter.match(e) {
+			continue
+		}
+		k := key(e)
+		row, ok := rows[k]
+		if !ok {
+			row = &Row{Key: k}
+			rows[k] = row
+		}
+		row.add(e)
+		total.add(e)
+	}
+
+	result := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if group == GroupTopic || group == GroupProfile {
+			if result[i].Cost != result[j].Cost {
+				return result[i].Cost > result[j].Cost
+			}
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, total, nil
+}
+
+// groupKey 集計の単位に対応するキーの関数
+func groupKey(group string, loc *time.Location) (func(Entry) string, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	switch group {
+	case GroupDay:
+		return func(e Entry) string { return e.Time.In(loc).Format("2006-01-02") }, nil
+	case GroupMonth:
+		return func(e Entry) string { return e.Time.In(loc).Format("2006-01") }, nil
+	case GroupTopic:
+		return func(e Entry) string { return e.Topic }, nil
+	case GroupProfile:
+		return func(e Entry) string {
+			if e.Profile == "" {
+				return "(default)"
+			}
+			return e.Profile
+		}, nil
+	default:
+		return nil, fmt.Errorf("未対応の集計単位です: %s (対応: day, month, topic, profile)", group)
+	}
+}
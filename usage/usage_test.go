@@ -0,0 +1,293 @@
+package usage
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"news_reporter/config"
+	"news_reporter/i18n"
+	"news_reporter/models"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEstimate(t *testing.T) {
+	pricing := config.DefaultPricing()
+	u := &models.Usage{InputTokens: 1_000_000, OutputTokens: 500_000, WebSearchCalls: 2}
+
+	// 日付付きのモデル名は最も長く前方一致する gpt-4o-mini の単価になる
+	cost := Estimate(pricing, "gpt-4o-mini-2024-07-18", u)
+	if cost == nil {
+		t.Fatal("Estimate returned nil for gpt-4o-mini")
+	}
+	if !almostEqual(cost.Input, 0.15) || !almostEqual(cost.Output, 0.30) || !almostEqual(cost.WebSearch, 0.05) || !almostEqual(cost.Total, 0.50) {
+		t.Errorf("cost = %+v", cost)
+	}
+
+	if cost := Estimate(pricing, "llama3.1", u); cost != nil {
+		t.Errorf("cost for an unpriced model = %+v, want nil", cost)
+	}
+	if cost := Estimate(pricing, "gpt-4o", nil); cost != nil {
+		t.Errorf("cost without usage = %+v, want nil", cost)
+	}
+
+	if cost, ok := SpeechCost(pricing, "tts-1-hd", 10_000); !ok || !almostEqual(cost, 0.30) {
+		t.Errorf("SpeechCost = %v, %v", cost, ok)
+	}
+}
+
+func TestRecorderAndSummarize(t *testing.T) {
+	cfg := config.Default()
+	cfg.Profile = "weekly"
+	ledger := NewLedger(filepath.Join(t.TempDir(), "usage.jsonl"))
+	recorder := NewRecorder(cfg, ledger)
+
+	search := func(query string, cost *models.Cost) *models.SearchResult {
+		return &models.SearchResult{Query: query, Model: "gpt-4o-mini", Usage: &models.Usage{InputTokens: 100, OutputTokens: 50, WebSearchCalls: 1}, Cost: cost}
+	}
+	for _, err := range []error{
+		recorder.Record(KindSearch, "AI", search("AI", &models.Cost{Total: 0.03})),
+		recorder.Record(KindSearch, "AI", search("AI", &models.Cost{Total: 0.02})),
+		recorder.Record(KindSearch, "経済", search("経済", nil)),
+		recorder.Record(KindSearch, "使用量なし", &models.SearchResult{}),
+		recorder.RecordSpeech("AI", "こんにちは"),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := ledger.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("recorded %d entries, want 4 (results without usage are skipped)", len(entries))
+	}
+	if entries[3].Kind != KindSpeech || entries[3].Characters != 5 || entries[3].Profile != "weekly" {
+		t.Errorf("speech entry = %+v", entries[3])
+	}
+
+	rows, total, err := Summarize(entries, GroupTopic, Filter{}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Key != "AI" || rows[0].Searches != 2 || rows[0].Speeches != 1 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if rows[1].Unpriced != 1 || total.Searches != 3 || total.WebSearchCalls != 3 {
+		t.Errorf("rows = %+v, total = %+v", rows, total)
+	}
+	if !almostEqual(total.Cost, 0.05+entries[3].Cost) {
+		t.Errorf("total cost = %v", total.Cost)
+	}
+
+	// 期間で絞り込む
+	rows, _, err = Summarize(entries, GroupDay, Filter{Since: time.Now().Add(time.Hour)}, time.UTC)
+	if err != nil || len(rows) != 0 {
+		t.Errorf("rows after filtering = %+v, %v", rows, err)
+	}
+	if _, _, err := Summarize(entries, "week", Filter{}, nil); err == nil {
+		t.Error("Summarize accepted an unknown group")
+	}
+}
+
+func TestBudget(t *testing.T) {
+	cfg := config.Default()
+	if NewBudget(cfg, nil) != nil {
+		t.Fatal("NewBudget without caps should return nil")
+	}
+	var none *Budget
+	if status, err := none.Check(); err != nil || status.Level != BudgetOK || none.CheckSpeech() != nil {
+		t.Errorf("nil budget: %+v, %v", status, err)
+	}
+
+	cfg.Profile = "weekly"
+	cfg.Budget.ProfileDaily = 1.00
+	cfg.Budget.ProfileMonthly = 10.00
+	cfg.Budget.FallbackModel = "gpt-4o-mini"
+	ledger := NewLedger(filepath.Join(t.TempDir(), "usage.jsonl"))
+	budget := NewBudget(cfg, ledger)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	budget.now = func() time.Time { return now }
+
+	spend := func(at time.Time, profile string, cost float64) {
+		t.Helper()
+		if err := ledger.Append(Entry{Time: at, Kind: KindSearch, Profile: profile, Cost: cost}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spend(now.AddDate(0, -1, 0), "weekly", 100) // 先月の記録は含めない
+	spend(now.Add(-24*time.Hour), "weekly", 2)  // 昨日の記録は今月の分だけに含める
+	spend(now.Add(-time.Hour), "", 5)           // 他のプロファイルの記録はプロファイルの上限に含めない
+	spend(now.Add(-time.Hour), "weekly", 0.5)
+
+	status, err := budget.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Level != BudgetOK || !almostEqual(status.ProfileDailySpent, 0.5) || !almostEqual(status.ProfileMonthlySpent, 2.5) {
+		t.Errorf("status = %+v", status)
+	}
+
+	spend(now.Add(-time.Minute), "weekly", 0.35)
+	status, _ = budget.Check()
+	if status.Level != BudgetApproaching || budget.Err(status) != nil {
+		t.Errorf("status at 85%% = %+v", status)
+	}
+	if err := budget.CheckSpeech(); !errors.Is(err, ErrSpeechSkipped) {
+		t.Errorf("CheckSpeech = %v, want ErrSpeechSkipped", err)
+	}
+
+	spend(now.Add(-time.Minute), "weekly", 0.15)
+	status, _ = budget.Check()
+	var budgetErr *BudgetError
+	if err := budget.Err(status); !errors.As(err, &budgetErr) || budgetErr.Profile != "weekly" {
+		t.Errorf("Err at 100%% = %v", err)
+	}
+
+	// メッセージは表示言語に合わせる
+	i18n.SetLanguage(i18n.English)
+	defer i18n.SetLanguage(i18n.Japanese)
+	if message := budget.Err(status).Error(); !strings.Contains(message, "profile's $1.00 daily cap") || !strings.Contains(message, "weekly") {
+		t.Errorf("Error() in English = %q", message)
+	}
+
+	// 翌日は1日の使用額がリセットされる
+	now = now.Add(24 * time.Hour)
+	if status, _ := budget.Check(); status.Level != BudgetOK || !almostEqual(status.ProfileMonthlySpent, 3.0) {
+		t.Errorf("status on the next day = %+v", status)
+	}
+}
+
+func TestBudgetGlobalCap(t *testing.T) {
+	ledger := NewLedger(filepath.Join(t.TempDir(), "usage.jsonl"))
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	budgetFor := func(profile string, limits config.Budget) *Budget {
+		cfg := config.Default()
+		cfg.Profile = profile
+		limits.WarnAt = config.DefaultBudgetWarnAt
+		cfg.Budget = limits
+		budget := NewBudget(cfg, ledger)
+		budget.now = func() time.Time { return now }
+		return budget
+	}
+	for _, e := range []Entry{
+		{Time: now.Add(-time.Hour), Profile: "economy", Cost: 0.4},
+		{Time: now.Add(-time.Hour), Profile: "tech", Cost: 0.4},
+		{Time: now.Add(-time.Hour), Cost: 0.3},
+	} {
+		if err := ledger.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 全体の上限はすべてのプロファイルの使用額の合計と比べる
+	status, err := budgetFor("economy", config.Budget{Daily: 1.00}).Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Level != BudgetExceeded || !almostEqual(status.DailySpent, 1.1) || !almostEqual(status.ProfileDailySpent, 0.4) {
+		t.Errorf("global cap status = %+v", status)
+	}
+
+	// プロファイルの上限に余裕があっても全体の上限で止める
+	status, _ = budgetFor("tech", config.Budget{Daily: 1.00, ProfileDaily: 5.00}).Check()
+	if status.Level != BudgetExceeded {
+		t.Errorf("status with a loose profile cap = %+v", status)
+	}
+
+	// 全体の上限に余裕があってもプロファイルの上限で止める
+	status, _ = budgetFor("tech", config.Budget{Daily: 5.00, ProfileDaily: 0.40}).Check()
+	if status.Level != BudgetExceeded {
+		t.Errorf("status with a tight profile cap = %+v", status)
+	}
+	if _, spent, limit := status.period(); !almostEqual(spent, 0.4) || !almostEqual(limit, 0.4) {
+		t.Errorf("nearest period = %v / %v, want the profile cap", spent, limit)
+	}
+}
+
+func TestLedgerReadFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	ledger := NewLedger(path)
+	if entries, offset, err := ledger.ReadFrom(0); err != nil || entries != nil || offset != 0 {
+		t.Fatalf("missing file: %v, %d, %v", entries, offset, err)
+	}
+
+	ledger.Append(Entry{Kind: KindSearch, Cost: 0.1})
+	ledger.Append(Entry{Kind: KindSearch, Cost: 0.2})
+	entries, offset, err := ledger.ReadFrom(0)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("ReadFrom(0) = %d entries, %v", len(entries), err)
+	}
+
+	// 追記途中の行は読まずに残し、書き終わってから読む
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"kind":"search","cost_usd":0.3`)
+	if entries, next, err := ledger.ReadFrom(offset); err != nil || len(entries) != 0 || next != offset {
+		t.Errorf("partial line: %d entries, offset %d → %d, %v", len(entries), offset, next, err)
+	}
+	file.WriteString("}\n")
+	file.Close()
+	entries, next, err := ledger.ReadFrom(offset)
+	if err != nil || len(entries) != 1 || !almostEqual(entries[0].Cost, 0.3) {
+		t.Errorf("completed line: %+v, %v", entries, err)
+	}
+
+	// 作り直されたファイルは ErrLedgerRewritten
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ledger.ReadFrom(next); !errors.Is(err, ErrLedgerRewritten) {
+		t.Errorf("ReadFrom after truncation = %v, want ErrLedgerRewritten", err)
+	}
+}
+
+func TestBudgetReadsOnlyNewEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	ledger := NewLedger(path)
+	cfg := config.Default()
+	cfg.Budget.Daily = 1.00
+	budget := NewBudget(cfg, ledger)
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	budget.now = func() time.Time { return now }
+
+	ledger.Append(Entry{Time: now.Add(-time.Hour), Cost: 0.25})
+	if status, err := budget.Check(); err != nil || !almostEqual(status.DailySpent, 0.25) {
+		t.Fatalf("first Check = %+v, %v", status, err)
+	}
+	read := budget.totals.offset
+
+	// 2回目以降は追記された分だけを読み足す
+	ledger.Append(Entry{Time: now.Add(-time.Minute), Cost: 0.5})
+	status, err := budget.Check()
+	if err != nil || !almostEqual(status.DailySpent, 0.75) || !almostEqual(status.MonthlySpent, 0.75) {
+		t.Errorf("Check after append = %+v, %v", status, err)
+	}
+	if budget.totals.offset <= read {
+		t.Errorf("offset did not advance: %d → %d", read, budget.totals.offset)
+	}
+
+	// 作り直された記録ファイルは先頭から集計し直す
+	os.Remove(path)
+	ledger.Append(Entry{Time: now, Cost: 0.1})
+	if status, _ := budget.Check(); !almostEqual(status.DailySpent, 0.1) {
+		t.Errorf("Check after the file was rewritten = %+v", status)
+	}
+
+	// 月が変わると集計し直す
+	now = now.Add(24 * time.Hour)
+	if status, _ := budget.Check(); status.MonthlySpent != 0 || status.DailySpent != 0 {
+		t.Errorf("Check in the next month = %+v", status)
+	}
+}